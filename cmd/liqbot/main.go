@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"os/signal"
 	"syscall"
@@ -10,27 +11,52 @@ import (
 	"github.com/go-kit/kit/log/level"
 	"gitlab.com/q-dev/exchange-rate-oracle/pkg/config"
 	"gitlab.com/q-dev/exchange-rate-oracle/pkg/liqbot"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/metrics"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/notify"
 )
 
 func main() {
 
-	cfg, err := config.FromEnv()
+	if len(os.Args) > 1 && os.Args[1] == "pnl" {
+		if err := pnl(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "scenarios" {
+		if err := scenarios(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	cfg, err := config.Load()
 	if err != nil {
-		panic(err)
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
 	}
 
-	logger := log.NewJSONLogger(log.NewSyncWriter(os.Stdout))
+	base := log.With(log.NewJSONLogger(log.NewSyncWriter(os.Stdout)), "ts", log.DefaultTimestampUTC)
+	logger := &log.SwapLogger{}
+	logger.Swap(level.NewFilter(base, allowLevel(cfg.Log().Level)))
 	level.Info(logger).Log("msg", "...initializing liqbot")
 
 	level.Debug(logger).Log(
 		"msg", "config values",
-		"rpc url", cfg.RPCURL(),
-		"account address", cfg.AccountAddress().Hex(),
-		"contract address", cfg.ContractComptrollerAddress(),
-		"update interval", cfg.UpdateInterval().Seconds(),
+		"rpc_url", config.RedactURL(cfg.RPCURL()),
+		"account", cfg.AccountAddress().Hex(),
+		"comptroller", cfg.ContractComptrollerAddress().Hex(),
+		"update_interval", cfg.UpdateInterval().Seconds(),
+		"log_level", cfg.Log().Level,
 	)
 
-	liqbot_ := liqbot.New(logger, cfg)
+	liqbot_ := liqbot.New(logger, cfg,
+		liqbot.WithMetrics(metrics.NewPrometheus()),
+		liqbot.WithNotifier(notify.FromConfig(logger, cfg)),
+	)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -44,9 +70,49 @@ func main() {
 		cancel()
 	}()
 
-	level.Info(logger).Log("msg", " 🚀 starting liquidation bot")
+	hups := make(chan os.Signal, 1)
+	signal.Notify(hups, syscall.SIGHUP)
 
-	//start bot
-	liqbot_.Start(ctx)
+	go func() {
+		for {
+			select {
+			case <-hups:
+				level.Info(logger).Log("msg", "🔄 reloading config")
+				reloaded, err := config.Load()
+				if err != nil {
+					level.Error(logger).Log("msg", "config reload failed, keeping current config", "err", err)
+					continue
+				}
+				if err := liqbot_.Reload(reloaded); err != nil {
+					level.Error(logger).Log("msg", "config reload rejected, keeping current config", "err", err)
+					continue
+				}
+				logger.Swap(level.NewFilter(base, allowLevel(reloaded.Log().Level)))
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
 
+	level.Info(logger).Log("msg", "🚀 starting liquidation bot")
+
+	//start bot, it only returns early on errors reconnecting cannot fix
+	if err := liqbot_.Start(ctx); err != nil {
+		os.Exit(1)
+	}
+
+}
+
+// allowLevel returns filter option logging name level and above, info when name is unknown
+func allowLevel(name string) level.Option {
+	switch name {
+	case "debug":
+		return level.AllowDebug()
+	case "warn":
+		return level.AllowWarn()
+	case "error":
+		return level.AllowError()
+	default:
+		return level.AllowInfo()
+	}
 }
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/config"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/store"
+)
+
+// pnl prints the realized PnL ledger of the configured store, it is read from the HTTP server of the
+// running bot when the bot holds the store
+func pnl() error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+
+	ledger, err := readLedger(cfg)
+	if errors.Is(err, store.ErrLocked) && cfg.HTTP().Listen != "" {
+		ledger, err = fetchLedger(cfg.HTTP().Listen)
+	}
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	printLedger(w, "DAY", ledger.ByDay)
+	fmt.Fprintln(w)
+	printLedger(w, "MARKET", ledger.ByMarket)
+	fmt.Fprintln(w)
+	printLedger(w, "", []store.LedgerEntry{ledger.Total})
+	return w.Flush()
+}
+
+func readLedger(cfg config.Config) (*store.Ledger, error) {
+	if cfg.Store().Path == "" {
+		return nil, errors.New("store.path is empty, no PnL is kept")
+	}
+
+	st, err := store.OpenReadOnly(cfg.Store().Path)
+	if err != nil {
+		return nil, err
+	}
+	defer st.Close()
+
+	return st.Ledger()
+}
+
+// fetchLedger gets the ledger from /pnl of the bot listening on listen, a listen address on every interface is reached on localhost
+func fetchLedger(listen string) (*store.Ledger, error) {
+	host, port, err := net.SplitHostPort(listen)
+	if err != nil {
+		return nil, err
+	}
+	if host == "" || net.ParseIP(host).IsUnspecified() {
+		host = "localhost"
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Get("http://" + net.JoinHostPort(host, port) + "/pnl")
+	if err != nil {
+		return nil, errors.New("Getting PnL from the running bot: " + err.Error())
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("Getting PnL from the running bot: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	ledger := new(store.Ledger)
+	if err := json.NewDecoder(resp.Body).Decode(ledger); err != nil {
+		return nil, errors.New("Decoding PnL: " + err.Error())
+	}
+	return ledger, nil
+}
+
+func printLedger(w io.Writer, title string, entries []store.LedgerEntry) {
+	fmt.Fprintf(w, "%s\tLIQUIDATIONS\tREVERTED\tREPAY USD\tSEIZED USD\tGAS USD\tPROFIT USD\t\n", title)
+	for _, e := range entries {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%.2f\t%.2f\t%.2f\t%.2f\t\n",
+			e.Key, e.Liquidations, e.Reverted, e.RepayUSD, e.SeizedUSD, e.GasUSD, e.ProfitUSD)
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/config"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/liqbot"
+)
+
+// defaultShocks are the price drops applied to every configured market when no scenario is given
+var defaultShocks = []float64{-10, -20, -30}
+
+// scenarios prints the liquidations opened by price shocks, each argument is a scenario
+// of comma separated MARKET=PERCENT shocks, e.g. ETH=-20,cDAI=-5
+func scenarios(args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+
+	var parsed []liqbot.Scenario
+	for _, arg := range args {
+		scenario, err := parseScenario(cfg, arg)
+		if err != nil {
+			return err
+		}
+		parsed = append(parsed, scenario)
+	}
+	if len(parsed) == 0 {
+		for _, market := range cfg.Markets() {
+			for _, change := range defaultShocks {
+				parsed = append(parsed, liqbot.Scenario{
+					Name:   fmt.Sprintf("%s=%g", market.Name, change),
+					Shocks: []liqbot.Shock{{Market: market.Address, Change: change / 100}},
+				})
+			}
+		}
+	}
+
+	logger := level.NewFilter(log.With(log.NewJSONLogger(log.NewSyncWriter(os.Stderr)), "ts", log.DefaultTimestampUTC),
+		allowLevel(cfg.Log().Level))
+	results, err := liqbot.AnalyzeScenarios(context.Background(), logger, cfg, parsed)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(w, "SCENARIO\tACCOUNTS\tNEW\tSKIPPED\tSHORTFALL USD\tREPAY USD\tSEIZE USD\tPROFIT USD\tPROFITABLE\t\n")
+	for _, r := range results {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%.2f\t%.2f\t%.2f\t%.2f\t%d\t\n", r.Scenario.Name, r.Accounts, r.NewAccounts,
+			r.Skipped, r.ShortfallUSD, r.RepayUSD, r.SeizeUSD, r.ProfitUSD, r.Profitable)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "SCENARIO\tREPAY MARKET\tLIQUIDATIONS\tREPAY USD\t\n")
+	for _, r := range results {
+		for _, repay := range r.Repay {
+			fmt.Fprintf(w, "%s\t%s\t%d\t%.2f\t\n", r.Scenario.Name, repay.Market.Name, repay.Liquidations, repay.USD)
+		}
+	}
+	return w.Flush()
+}
+
+// parseScenario parses comma separated MARKET=PERCENT shocks, MARKET is a configured market name
+// or ETH for the profit.eth_market market
+func parseScenario(cfg config.Config, arg string) (liqbot.Scenario, error) {
+	scenario := liqbot.Scenario{Name: arg}
+	for _, shock := range strings.Split(arg, ",") {
+		parts := strings.SplitN(shock, "=", 2)
+		if len(parts) != 2 {
+			return scenario, fmt.Errorf("invalid shock %q, expected MARKET=PERCENT", shock)
+		}
+		market, ok := findMarket(cfg, strings.TrimSpace(parts[0]))
+		if !ok {
+			return scenario, fmt.Errorf("unknown market %q", parts[0])
+		}
+		change, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(parts[1]), "%"), 64)
+		if err != nil {
+			return scenario, fmt.Errorf("invalid change %q: %v", parts[1], err)
+		}
+		if change <= -100 {
+			return scenario, fmt.Errorf("invalid change %q, must be over -100%%", parts[1])
+		}
+		scenario.Shocks = append(scenario.Shocks, liqbot.Shock{Market: market, Change: change / 100})
+	}
+	return scenario, nil
+}
+
+func findMarket(cfg config.Config, name string) (common.Address, bool) {
+	for _, market := range cfg.Markets() {
+		if strings.EqualFold(market.Name, name) {
+			return market.Address, true
+		}
+	}
+	if strings.EqualFold(name, "ETH") && cfg.Profit().ETHMarket != (common.Address{}) {
+		return cfg.Profit().ETHMarket, true
+	}
+	return common.Address{}, false
+}
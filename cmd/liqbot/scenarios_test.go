@@ -0,0 +1,63 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/config"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/liqbot"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/simulated"
+)
+
+func TestParseScenario(t *testing.T) {
+	cDAI := common.HexToAddress("0x5d3a536E4D6DbD6114cc1Ead35777bAB948E3643")
+	cUSDC := common.HexToAddress("0x39AA39c021dfbaE8faC545936693aC917d5E7563")
+	cETH := common.HexToAddress("0x4Ddc2D193948926D02f9B1fE9e1daa0718270ED5")
+	cfg := &simulated.Config{
+		MarketList:     []config.Market{{Name: "cDAI", Address: cDAI}, {Name: "cUSDC", Address: cUSDC}},
+		ProfitSettings: config.Profit{ETHMarket: cETH},
+	}
+
+	tests := []struct {
+		arg    string
+		shocks []liqbot.Shock
+		err    string
+	}{
+		{arg: "cDAI=-5,ETH=-20", shocks: []liqbot.Shock{{Market: cDAI, Change: -0.05}, {Market: cETH, Change: -0.2}}},
+		{arg: "cusdc=10%", shocks: []liqbot.Shock{{Market: cUSDC, Change: 0.1}}},
+		{arg: " cDAI = -99.5 ", shocks: []liqbot.Shock{{Market: cDAI, Change: -0.995}}},
+		{arg: "cWBTC=-10", err: `unknown market "cWBTC"`},
+		{arg: "cDAI", err: "expected MARKET=PERCENT"},
+		{arg: "cDAI=-5,", err: "expected MARKET=PERCENT"},
+		{arg: "cDAI=five", err: `invalid change "five"`},
+		{arg: "cDAI=-100", err: "must be over -100%"},
+		{arg: "cDAI=-150", err: "must be over -100%"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			scenario, err := parseScenario(cfg, tt.arg)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("got error %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if scenario.Name != tt.arg {
+				t.Errorf("name %q, want %q", scenario.Name, tt.arg)
+			}
+			if len(scenario.Shocks) != len(tt.shocks) {
+				t.Fatalf("shocks %v, want %v", scenario.Shocks, tt.shocks)
+			}
+			for i, shock := range scenario.Shocks {
+				if shock.Market != tt.shocks[i].Market || shock.Change != tt.shocks[i].Change {
+					t.Errorf("shock %d: %v, want %v", i, shock, tt.shocks[i])
+				}
+			}
+		})
+	}
+}
@@ -3,91 +3,190 @@ package config
 import (
 	"crypto/ecdsa"
 	"errors"
-	"fmt"
+	"math/big"
 	"net/url"
 	"os"
-	"strconv"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/crypto"
 )
 
 // Config represents price feed oracle config
 type Config interface {
 	RPCURL() *url.URL
 	AccountAddress() common.Address
+	// AccountKey is nil when the account is held by a keystore or a remote signer
 	AccountKey() *ecdsa.PrivateKey
+	Account() Account
 	UpdateInterval() time.Duration
 	ContractComptrollerAddress() common.Address
 	ContractCusdcAddress() common.Address
+	// ContractMulticallAddress is the Multicall3 contract batching reads
+	ContractMulticallAddress() common.Address
+	Markets() []Market
+	Gas() Gas
+	Profit() Profit
+	Endpoints() Endpoints
+	DryRun() DryRun
+	Store() Store
+	HTTP() HTTP
+	Health() Health
+	Log() Log
+	Notify() Notify
+	Scan() Scan
+	// ChainID is the expected chain id, nil when not configured
+	ChainID() *big.Int
+	// StartupChecks enables CheckChain before the bot starts
+	StartupChecks() bool
 }
 
-// FromEnv creates config from environment variables
-func FromEnv() (Config, error) {
-	rpcURLStr, ok := os.LookupEnv("RPC_URL")
-	if !ok {
-		return nil, errors.New("RPC_URL: not set")
-	}
+// Market represents Compound market the bot liquidates in
+type Market struct {
+	Name    string
+	Address common.Address
+}
 
-	rpcURL, err := url.Parse(rpcURLStr)
-	if err != nil {
-		return nil, fmt.Errorf("RPC_URL: %v", err)
-	}
+// Account represents liquidator account key source, at most one of key, keystore or remote signer is set
+type Account struct {
+	Address common.Address
+	// Keystore is go-ethereum encrypted JSON key file path
+	Keystore string
+	// PasswordFile is the file holding Keystore passphrase
+	PasswordFile string
+	// SignerURL is Clef or node JSON-RPC endpoint signing transactions
+	SignerURL *url.URL
+	// SignerMethod is account_signTransaction (Clef) or eth_signTransaction (node)
+	SignerMethod string
+}
 
-	contractAddressStr, ok := os.LookupEnv("CONTRACT_ADDRESS")
-	if !ok {
-		return nil, errors.New("CONTRACT_ADDRESS: not set")
-	}
+// Gas represents liquidation transactions gas settings
+type Gas struct {
+	// MaxPrice is the gas price ceiling in wei, nil means no ceiling
+	MaxPrice *big.Int
+	// Limit is the liquidation transaction gas limit, 0 means estimated
+	Limit uint64
+	// PriceMultiplier is applied to the suggested gas price
+	PriceMultiplier float64
+}
 
-	if !common.IsHexAddress(contractAddressStr) {
-		return nil, errors.New("CONTRACT_ADDRESS: invalid")
-	}
+// Profit represents liquidation profitability thresholds
+type Profit struct {
+	// MinUSD is the minimum expected profit to send a liquidation
+	MinUSD float64
+	// ETHMarket is the cETH market used to price gas, gas is not priced when zero
+	ETHMarket common.Address
+}
 
-	privateKeyStr, ok := os.LookupEnv("PRIVATE_KEY")
-	if !ok {
-		return nil, errors.New("PRIVATE_KEY: not set")
-	}
+// DryRun represents paper trading settings
+type DryRun struct {
+	// Enabled records liquidations to Output instead of sending them
+	Enabled bool
+	// Output is the JSON lines file liquidations are appended to
+	Output string
+}
 
-	updateIntervalStr, ok := os.LookupEnv("UPDATE_INTERVAL_SECONDS")
-	if !ok {
-		return nil, errors.New("UPDATE_INTERVAL_SECONDS: not set")
-	}
+// Store represents persistent state settings
+type Store struct {
+	// Path is the database file, state is not persisted when empty
+	Path string
+}
 
-	updateIntervalInt64, err := strconv.ParseInt(updateIntervalStr, 10, 64)
-	if err != nil {
-		return nil, fmt.Errorf("UPDATE_INTERVAL_SECONDS: %v", err)
-	}
+// HTTP represents the metrics and health server settings
+type HTTP struct {
+	// Listen is the server address, the server is not started when empty
+	Listen string
+}
+
+// Health represents thresholds of the health and readiness checks, zero values are not checked
+type Health struct {
+	// MaxScanAge is the longest time without a successful scan before the bot is not alive
+	MaxScanAge time.Duration
+	// MaxSubgraphLag is the number of blocks the subgraph may be behind the chain head
+	MaxSubgraphLag uint64
+	// MaxSubgraphAge is the age of the subgraph head block over which account fetches fail as stale
+	MaxSubgraphAge time.Duration
+	// MinBalance is the signer balance in wei under which the bot is not ready, nil means not checked
+	MinBalance *big.Int
+	// StuckAfter is the age of a liquidation without outcome that counts as stuck
+	StuckAfter time.Duration
+}
+
+// Log represents logging settings
+type Log struct {
+	// Level is the lowest level logged: debug, info, warn or error
+	Level string
+}
+
+// Notify represents notification sinks and limits, no notification is sent without a sink
+type Notify struct {
+	// WebhookURL receives events as JSON
+	WebhookURL *url.URL
+	// SlackURL is a Slack compatible incoming webhook
+	SlackURL *url.URL
+	// TelegramToken and TelegramChatID are set together, TelegramAPI is nil for the public Bot API
+	TelegramToken  string
+	TelegramChatID string
+	TelegramAPI    *url.URL
+	// File receives events as JSON lines
+	File string
+	// Dedup is how long an event is not sent again
+	Dedup time.Duration
+	// RateLimit is the number of notifications sent per minute
+	RateLimit int
+	// RPCErrors is the number of consecutive failed RPC calls notified
+	RPCErrors int
+}
 
-	privateKeyBytes := common.FromHex(privateKeyStr)
-	accountKey := crypto.ToECDSAUnsafe(privateKeyBytes)
-	accountAddress := crypto.PubkeyToAddress(accountKey.PublicKey)
-	contractAddress := common.HexToAddress(contractAddressStr)
-	updateInterval := time.Second * time.Duration(updateIntervalInt64)
+// Scan represents how accounts are evaluated during a scan
+type Scan struct {
+	// Concurrency is the number of batched reads sent at once
+	Concurrency int
+	// RPCRateLimit is the number of reads per second sent to each RPC node, 0 means unlimited
+	RPCRateLimit float64
+	// RPCBurst is the number of reads a node may get at once above the rate
+	RPCBurst int
+	// BatchSize is the number of reads aggregated in one multicall
+	BatchSize int
+	// PositionMaxAge is the number of blocks a cached borrower position is evaluated locally before it is read again
+	PositionMaxAge uint64
+	// LookaheadBlocks is the number of blocks borrow interest is projected ahead to find accounts about to go under water
+	LookaheadBlocks uint64
+	// WatchBand puts accounts with health under 1 + WatchBand on the watchlist checked every block, 0 checks every account
+	WatchBand float64
+	// FullScanInterval is the time between scans of every account when the watchlist is on
+	FullScanInterval time.Duration
+}
 
-	contractComptrollerAddressStr, ok := os.LookupEnv("CONTRACT_COMPTROLLER_ADDRESS")
-	contractComptrollerAddress := common.HexToAddress(contractComptrollerAddressStr)
+// Endpoints represents external services the bot talks to
+type Endpoints struct {
+	RPC *url.URL
+	// RPCPool holds nodes pooled with RPC, reads go to the fastest healthy one and transactions to all
+	RPCPool []*url.URL
+	// Subgraph is nil when the default Compound subgraph is used
+	Subgraph *url.URL
+}
 
-	if !ok {
-		return nil, errors.New("CONTRACT_COMPTROLLER_ADDRESS: not set")
+// Load creates config from the file named by CONFIG_FILE when set, from environment variables otherwise
+func Load() (Config, error) {
+	if path, ok := os.LookupEnv("CONFIG_FILE"); ok {
+		return FromFile(path)
 	}
+	return FromEnv()
+}
 
-	contractCusdcAddressStr, ok := os.LookupEnv("CONTRACT_CUSDC_ADDRESS")
-	contractCusdcAddress := common.HexToAddress(contractCusdcAddressStr)
+// FromEnv creates config from environment variables
+func FromEnv() (Config, error) {
+	raw := &fileConfig{}
+	raw.applyEnv()
 
-	if !ok {
-		return nil, errors.New("CONTRACT_CUSDC_ADDRESS: not set")
+	// kept from the price feed oracle config, required for compatibility
+	if contractAddressStr, ok := os.LookupEnv("CONTRACT_ADDRESS"); !ok {
+		raw.invalid("CONTRACT_ADDRESS", errNotSet)
+	} else if !common.IsHexAddress(contractAddressStr) {
+		raw.invalid("CONTRACT_ADDRESS", errors.New("invalid"))
 	}
 
-	return &config{
-		rpcURL:                     rpcURL,
-		accountAddress:             accountAddress,
-		accountKey:                 accountKey,
-		contractAddress:            contractAddress,
-		updateInverval:             updateInterval,
-		contractComptrollerAddress: contractComptrollerAddress,
-		contractCusdcAddress:       contractCusdcAddress,
-	}, nil
+	return raw.build()
 }
 
 type config struct {
@@ -95,9 +194,24 @@ type config struct {
 	contractAddress            common.Address
 	accountAddress             common.Address
 	accountKey                 *ecdsa.PrivateKey
+	account                    Account
 	updateInverval             time.Duration
 	contractComptrollerAddress common.Address
 	contractCusdcAddress       common.Address
+	contractMulticallAddress   common.Address
+	markets                    []Market
+	gas                        Gas
+	profit                     Profit
+	endpoints                  Endpoints
+	dryRun                     DryRun
+	store                      Store
+	http                       HTTP
+	health                     Health
+	log                        Log
+	notify                     Notify
+	scan                       Scan
+	chainID                    *big.Int
+	startupChecks              bool
 }
 
 func (c *config) RPCURL() *url.URL {
@@ -116,6 +230,10 @@ func (c *config) AccountKey() *ecdsa.PrivateKey {
 	return c.accountKey
 }
 
+func (c *config) Account() Account {
+	return c.account
+}
+
 func (c *config) UpdateInterval() time.Duration {
 	return c.updateInverval
 }
@@ -131,3 +249,59 @@ func (c *config) ContractComptrollerAddress() common.Address {
 func (c *config) ContractCusdcAddress() common.Address {
 	return c.contractCusdcAddress
 }
+
+func (c *config) ContractMulticallAddress() common.Address {
+	return c.contractMulticallAddress
+}
+
+func (c *config) Markets() []Market {
+	return c.markets
+}
+
+func (c *config) Gas() Gas {
+	return c.gas
+}
+
+func (c *config) Profit() Profit {
+	return c.profit
+}
+
+func (c *config) Endpoints() Endpoints {
+	return c.endpoints
+}
+
+func (c *config) DryRun() DryRun {
+	return c.dryRun
+}
+
+func (c *config) Store() Store {
+	return c.store
+}
+
+func (c *config) HTTP() HTTP {
+	return c.http
+}
+
+func (c *config) Health() Health {
+	return c.health
+}
+
+func (c *config) Log() Log {
+	return c.log
+}
+
+func (c *config) Notify() Notify {
+	return c.notify
+}
+
+func (c *config) Scan() Scan {
+	return c.scan
+}
+
+func (c *config) ChainID() *big.Int {
+	return c.chainID
+}
+
+func (c *config) StartupChecks() bool {
+	return c.startupChecks
+}
@@ -0,0 +1,166 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Change represents config value changed between two configs
+type Change struct {
+	Key string
+	Old string
+	New string
+}
+
+// immutableKeys cannot change without restarting the bot
+var immutableKeys = []string{
+	"chain_id",
+	"endpoints.rpc",
+	"endpoints.rpc_pool",
+	"account.address",
+	"account.source",
+	"contracts.comptroller",
+	"store.path",
+	"http.listen",
+	"notify.webhook",
+	"notify.slack",
+	"notify.telegram",
+	"notify.file",
+	"notify.dedup",
+	"notify.rate_limit",
+	"scan.rpc_rate_limit",
+	"scan.rpc_burst",
+}
+
+// Diff returns values changed from old to new, sorted by key
+func Diff(old, new Config) []Change {
+	oldValues, newValues := flatten(old), flatten(new)
+
+	var changes []Change
+	for key, oldValue := range oldValues {
+		if newValue := newValues[key]; newValue != oldValue {
+			changes = append(changes, Change{Key: key, Old: oldValue, New: newValue})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Key < changes[j].Key
+	})
+
+	return changes
+}
+
+// CheckReload returns error when new changes a value that cannot be reloaded
+func CheckReload(old, new Config) error {
+	changes := Diff(old, new)
+
+	var problems ValidationError
+	for _, key := range immutableKeys {
+		for _, change := range changes {
+			if change.Key == key {
+				problems = append(problems, &FieldError{Key: key, Err: errors.New("cannot be changed without restart")})
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return problems
+	}
+
+	return nil
+}
+
+func flatten(cfg Config) map[string]string {
+	values := map[string]string{
+		"update_interval_seconds":      fmt.Sprint(cfg.UpdateInterval().Seconds()),
+		"endpoints.rpc":                RedactURL(cfg.Endpoints().RPC),
+		"endpoints.subgraph":           RedactURL(cfg.Endpoints().Subgraph),
+		"account.address":              cfg.AccountAddress().Hex(),
+		"account.source":               accountSource(cfg),
+		"contracts.comptroller":        cfg.ContractComptrollerAddress().Hex(),
+		"contracts.cusdc":              cfg.ContractCusdcAddress().Hex(),
+		"contracts.multicall":          cfg.ContractMulticallAddress().Hex(),
+		"gas.max_price":                fmt.Sprint(cfg.Gas().MaxPrice),
+		"gas.limit":                    fmt.Sprint(cfg.Gas().Limit),
+		"gas.price_multiplier":         fmt.Sprint(cfg.Gas().PriceMultiplier),
+		"profit.min_usd":               fmt.Sprint(cfg.Profit().MinUSD),
+		"profit.eth_market":            cfg.Profit().ETHMarket.Hex(),
+		"dry_run.enabled":              fmt.Sprint(cfg.DryRun().Enabled),
+		"dry_run.output":               cfg.DryRun().Output,
+		"store.path":                   cfg.Store().Path,
+		"http.listen":                  cfg.HTTP().Listen,
+		"log.level":                    cfg.Log().Level,
+		"notify.webhook":               RedactURL(cfg.Notify().WebhookURL),
+		"notify.slack":                 RedactURL(cfg.Notify().SlackURL),
+		"notify.telegram":              telegramTarget(cfg.Notify()),
+		"notify.file":                  cfg.Notify().File,
+		"notify.dedup":                 fmt.Sprint(cfg.Notify().Dedup),
+		"notify.rate_limit":            fmt.Sprint(cfg.Notify().RateLimit),
+		"notify.rpc_errors":            fmt.Sprint(cfg.Notify().RPCErrors),
+		"health.max_scan_age":          fmt.Sprint(cfg.Health().MaxScanAge),
+		"health.max_subgraph_lag":      fmt.Sprint(cfg.Health().MaxSubgraphLag),
+		"health.max_subgraph_age":      fmt.Sprint(cfg.Health().MaxSubgraphAge),
+		"health.min_balance":           fmt.Sprint(cfg.Health().MinBalance),
+		"health.stuck_after":           fmt.Sprint(cfg.Health().StuckAfter),
+		"scan.concurrency":             fmt.Sprint(cfg.Scan().Concurrency),
+		"scan.rpc_rate_limit":          fmt.Sprint(cfg.Scan().RPCRateLimit),
+		"scan.rpc_burst":               fmt.Sprint(cfg.Scan().RPCBurst),
+		"scan.batch_size":              fmt.Sprint(cfg.Scan().BatchSize),
+		"scan.position_max_age_blocks": fmt.Sprint(cfg.Scan().PositionMaxAge),
+		"scan.lookahead_blocks":        fmt.Sprint(cfg.Scan().LookaheadBlocks),
+		"scan.watch_band":              fmt.Sprint(cfg.Scan().WatchBand),
+		"scan.full_scan_interval":      fmt.Sprint(cfg.Scan().FullScanInterval),
+		"chain_id":                     fmt.Sprint(cfg.ChainID()),
+		"startup_checks":               fmt.Sprint(cfg.StartupChecks()),
+	}
+
+	pool := make([]string, 0, len(cfg.Endpoints().RPCPool))
+	for _, rpcURL := range cfg.Endpoints().RPCPool {
+		pool = append(pool, RedactURL(rpcURL))
+	}
+	values["endpoints.rpc_pool"] = strings.Join(pool, ",")
+
+	markets := make([]string, 0, len(cfg.Markets()))
+	for _, market := range cfg.Markets() {
+		markets = append(markets, market.Name+"="+market.Address.Hex())
+	}
+	values["markets"] = strings.Join(markets, ",")
+
+	return values
+}
+
+// accountSource describes where the key comes from without exposing it
+func accountSource(cfg Config) string {
+	account := cfg.Account()
+	switch {
+	case account.Keystore != "":
+		return "keystore:" + account.Keystore
+	case account.SignerURL != nil:
+		return "signer:" + RedactURL(account.SignerURL)
+	default:
+		return "private_key"
+	}
+}
+
+// RedactURL keeps scheme and host of URL for logging, credentials, path and query usually hold a secret.
+// A digest of the whole URL tells changed URLs apart.
+func RedactURL(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+	digest := sha256.Sum256([]byte(u.String()))
+	return u.Scheme + "://" + u.Host + "/...#" + hex.EncodeToString(digest[:4])
+}
+
+// telegramTarget describes the Telegram chat without the bot token
+func telegramTarget(n Notify) string {
+	if n.TelegramChatID == "" {
+		return ""
+	}
+	return "chat:" + n.TelegramChatID + " api:" + RedactURL(n.TelegramAPI)
+}
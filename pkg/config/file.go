@@ -0,0 +1,820 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/multicall"
+	"gopkg.in/yaml.v3"
+)
+
+// FromFile creates config from YAML (.yaml, .yml) or TOML (.toml) file,
+// environment variables override the file values
+func FromFile(path string) (Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := &fileConfig{file: path}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(raw); err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+	case ".toml":
+		meta, err := toml.Decode(string(data), raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+		if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+			return nil, fmt.Errorf("%s: unknown key %s", path, undecoded[0])
+		}
+	default:
+		return nil, fmt.Errorf("%s: unsupported config file extension", path)
+	}
+
+	raw.applyEnv()
+
+	return raw.build()
+}
+
+// fileConfig is the config file schema, environment variables are loaded into it as well
+type fileConfig struct {
+	ChainID               *int64        `yaml:"chain_id" toml:"chain_id"`
+	StartupChecks         *bool         `yaml:"startup_checks" toml:"startup_checks"`
+	UpdateIntervalSeconds *int64        `yaml:"update_interval_seconds" toml:"update_interval_seconds"`
+	Endpoints             fileEndpoints `yaml:"endpoints" toml:"endpoints"`
+	Account               fileAccount   `yaml:"account" toml:"account"`
+	Contracts             fileContracts `yaml:"contracts" toml:"contracts"`
+	Markets               []fileMarket  `yaml:"markets" toml:"markets"`
+	Gas                   fileGas       `yaml:"gas" toml:"gas"`
+	Profit                fileProfit    `yaml:"profit" toml:"profit"`
+	DryRun                fileDryRun    `yaml:"dry_run" toml:"dry_run"`
+	Store                 fileStore     `yaml:"store" toml:"store"`
+	HTTP                  fileHTTP      `yaml:"http" toml:"http"`
+	Health                fileHealth    `yaml:"health" toml:"health"`
+	Log                   fileLog       `yaml:"log" toml:"log"`
+	Notify                fileNotify    `yaml:"notify" toml:"notify"`
+	Scan                  fileScan      `yaml:"scan" toml:"scan"`
+
+	// file is empty when config is loaded from environment only
+	file string
+	// fromEnv holds keys overridden by environment variables
+	fromEnv map[string]bool
+	// problems holds every invalid value found while loading
+	problems ValidationError
+}
+
+type fileEndpoints struct {
+	RPC      string   `yaml:"rpc" toml:"rpc"`
+	RPCPool  []string `yaml:"rpc_pool" toml:"rpc_pool"`
+	Subgraph string   `yaml:"subgraph" toml:"subgraph"`
+}
+
+type fileAccount struct {
+	PrivateKey   string `yaml:"private_key" toml:"private_key"`
+	Keystore     string `yaml:"keystore" toml:"keystore"`
+	PasswordFile string `yaml:"password_file" toml:"password_file"`
+	SignerURL    string `yaml:"signer_url" toml:"signer_url"`
+	SignerMethod string `yaml:"signer_method" toml:"signer_method"`
+	Address      string `yaml:"address" toml:"address"`
+}
+
+type fileContracts struct {
+	Comptroller string `yaml:"comptroller" toml:"comptroller"`
+	Cusdc       string `yaml:"cusdc" toml:"cusdc"`
+	Multicall   string `yaml:"multicall" toml:"multicall"`
+}
+
+type fileMarket struct {
+	Name    string `yaml:"name" toml:"name"`
+	Address string `yaml:"address" toml:"address"`
+}
+
+type fileGas struct {
+	MaxPriceGwei    *float64 `yaml:"max_price_gwei" toml:"max_price_gwei"`
+	Limit           *uint64  `yaml:"limit" toml:"limit"`
+	PriceMultiplier *float64 `yaml:"price_multiplier" toml:"price_multiplier"`
+}
+
+type fileProfit struct {
+	MinUSD    *float64 `yaml:"min_usd" toml:"min_usd"`
+	ETHMarket string   `yaml:"eth_market" toml:"eth_market"`
+}
+
+type fileDryRun struct {
+	Enabled *bool  `yaml:"enabled" toml:"enabled"`
+	Output  string `yaml:"output" toml:"output"`
+}
+
+type fileStore struct {
+	// Path is nil when the key is absent, empty disables the store
+	Path *string `yaml:"path" toml:"path"`
+}
+
+type fileHTTP struct {
+	// Listen is nil when the key is absent, empty disables the server
+	Listen *string `yaml:"listen" toml:"listen"`
+}
+
+type fileNotify struct {
+	WebhookURL         string `yaml:"webhook_url" toml:"webhook_url"`
+	SlackWebhookURL    string `yaml:"slack_webhook_url" toml:"slack_webhook_url"`
+	TelegramBotToken   string `yaml:"telegram_bot_token" toml:"telegram_bot_token"`
+	TelegramChatID     string `yaml:"telegram_chat_id" toml:"telegram_chat_id"`
+	TelegramAPIURL     string `yaml:"telegram_api_url" toml:"telegram_api_url"`
+	File               string `yaml:"file" toml:"file"`
+	DedupSeconds       *int64 `yaml:"dedup_seconds" toml:"dedup_seconds"`
+	RateLimitPerMinute *int64 `yaml:"rate_limit_per_minute" toml:"rate_limit_per_minute"`
+	RPCErrorThreshold  *int64 `yaml:"rpc_error_threshold" toml:"rpc_error_threshold"`
+}
+
+type fileScan struct {
+	Concurrency          *int64   `yaml:"concurrency" toml:"concurrency"`
+	RPCRequestsPerSecond *float64 `yaml:"rpc_requests_per_second" toml:"rpc_requests_per_second"`
+	RPCBurst             *int64   `yaml:"rpc_burst" toml:"rpc_burst"`
+	BatchSize            *int64   `yaml:"batch_size" toml:"batch_size"`
+	PositionMaxAgeBlocks *uint64  `yaml:"position_max_age_blocks" toml:"position_max_age_blocks"`
+	LookaheadBlocks      *uint64  `yaml:"lookahead_blocks" toml:"lookahead_blocks"`
+	WatchHealthBand      *float64 `yaml:"watch_health_band" toml:"watch_health_band"`
+	FullScanInterval     *int64   `yaml:"full_scan_interval_seconds" toml:"full_scan_interval_seconds"`
+}
+
+type fileLog struct {
+	Level string `yaml:"level" toml:"level"`
+}
+
+type fileHealth struct {
+	MaxScanAgeSeconds     *int64   `yaml:"max_scan_age_seconds" toml:"max_scan_age_seconds"`
+	MaxSubgraphLagBlocks  *uint64  `yaml:"max_subgraph_lag_blocks" toml:"max_subgraph_lag_blocks"`
+	MaxSubgraphAgeSeconds *int64   `yaml:"max_subgraph_age_seconds" toml:"max_subgraph_age_seconds"`
+	MinBalanceETH         *float64 `yaml:"min_balance_eth" toml:"min_balance_eth"`
+	StuckAfterSeconds     *int64   `yaml:"stuck_after_seconds" toml:"stuck_after_seconds"`
+}
+
+// envVars maps config keys to the environment variables overriding them
+var envVars = map[string]string{
+	"chain_id":                "CHAIN_ID",
+	"startup_checks":          "STARTUP_CHECKS",
+	"update_interval_seconds": "UPDATE_INTERVAL_SECONDS",
+	"endpoints.rpc":           "RPC_URL",
+	"endpoints.rpc_pool":      "RPC_POOL_URLS",
+	"endpoints.subgraph":      "SUBGRAPH_URL",
+	"account.private_key":     "PRIVATE_KEY",
+	"account.keystore":        "KEYSTORE_FILE",
+	"account.password_file":   "KEYSTORE_PASSWORD_FILE",
+	"account.signer_url":      "SIGNER_URL",
+	"account.signer_method":   "SIGNER_METHOD",
+	"account.address":         "SIGNER_ADDRESS",
+	"contracts.comptroller":   "CONTRACT_COMPTROLLER_ADDRESS",
+	"contracts.cusdc":         "CONTRACT_CUSDC_ADDRESS",
+	"contracts.multicall":     "CONTRACT_MULTICALL_ADDRESS",
+	"gas.max_price_gwei":      "GAS_MAX_PRICE_GWEI",
+	"gas.limit":               "GAS_LIMIT",
+	"gas.price_multiplier":    "GAS_PRICE_MULTIPLIER",
+	"profit.min_usd":          "PROFIT_MIN_USD",
+	"profit.eth_market":       "PROFIT_ETH_MARKET",
+	"dry_run.enabled":         "DRY_RUN",
+	"dry_run.output":          "DRY_RUN_OUTPUT",
+	"store.path":              "STORE_PATH",
+	"http.listen":             "HTTP_LISTEN",
+	"log.level":               "LOG_LEVEL",
+
+	"notify.webhook_url":           "NOTIFY_WEBHOOK_URL",
+	"notify.slack_webhook_url":     "NOTIFY_SLACK_WEBHOOK_URL",
+	"notify.telegram_bot_token":    "NOTIFY_TELEGRAM_BOT_TOKEN",
+	"notify.telegram_chat_id":      "NOTIFY_TELEGRAM_CHAT_ID",
+	"notify.telegram_api_url":      "NOTIFY_TELEGRAM_API_URL",
+	"notify.file":                  "NOTIFY_FILE",
+	"notify.dedup_seconds":         "NOTIFY_DEDUP_SECONDS",
+	"notify.rate_limit_per_minute": "NOTIFY_RATE_LIMIT_PER_MINUTE",
+	"notify.rpc_error_threshold":   "NOTIFY_RPC_ERROR_THRESHOLD",
+
+	"scan.concurrency":                "SCAN_CONCURRENCY",
+	"scan.rpc_requests_per_second":    "SCAN_RPC_REQUESTS_PER_SECOND",
+	"scan.rpc_burst":                  "SCAN_RPC_BURST",
+	"scan.batch_size":                 "SCAN_BATCH_SIZE",
+	"scan.position_max_age_blocks":    "SCAN_POSITION_MAX_AGE_BLOCKS",
+	"scan.lookahead_blocks":           "SCAN_LOOKAHEAD_BLOCKS",
+	"scan.watch_health_band":          "SCAN_WATCH_HEALTH_BAND",
+	"scan.full_scan_interval_seconds": "SCAN_FULL_SCAN_INTERVAL_SECONDS",
+
+	"health.max_scan_age_seconds":     "HEALTH_MAX_SCAN_AGE_SECONDS",
+	"health.max_subgraph_lag_blocks":  "HEALTH_MAX_SUBGRAPH_LAG_BLOCKS",
+	"health.max_subgraph_age_seconds": "HEALTH_MAX_SUBGRAPH_AGE_SECONDS",
+	"health.min_balance_eth":          "HEALTH_MIN_BALANCE_ETH",
+	"health.stuck_after_seconds":      "HEALTH_STUCK_AFTER_SECONDS",
+}
+
+func (f *fileConfig) applyEnv() {
+	f.fromEnv = map[string]bool{}
+
+	strs := map[string]*string{
+		"endpoints.rpc":         &f.Endpoints.RPC,
+		"endpoints.subgraph":    &f.Endpoints.Subgraph,
+		"account.private_key":   &f.Account.PrivateKey,
+		"account.keystore":      &f.Account.Keystore,
+		"account.password_file": &f.Account.PasswordFile,
+		"account.signer_url":    &f.Account.SignerURL,
+		"account.signer_method": &f.Account.SignerMethod,
+		"account.address":       &f.Account.Address,
+		"profit.eth_market":     &f.Profit.ETHMarket,
+		"dry_run.output":        &f.DryRun.Output,
+		"log.level":             &f.Log.Level,
+
+		"notify.webhook_url":        &f.Notify.WebhookURL,
+		"notify.slack_webhook_url":  &f.Notify.SlackWebhookURL,
+		"notify.telegram_bot_token": &f.Notify.TelegramBotToken,
+		"notify.telegram_chat_id":   &f.Notify.TelegramChatID,
+		"notify.telegram_api_url":   &f.Notify.TelegramAPIURL,
+		"notify.file":               &f.Notify.File,
+		"contracts.comptroller":     &f.Contracts.Comptroller,
+		"contracts.cusdc":           &f.Contracts.Cusdc,
+		"contracts.multicall":       &f.Contracts.Multicall,
+	}
+	for key, dst := range strs {
+		if value, ok := f.lookupEnv(key); ok {
+			*dst = value
+		}
+	}
+
+	// optional strings take their default when the key is absent, empty values are kept
+	optStrs := map[string]**string{
+		"store.path":  &f.Store.Path,
+		"http.listen": &f.HTTP.Listen,
+	}
+	for key, dst := range optStrs {
+		if value, ok := f.lookupEnv(key); ok {
+			*dst = &value
+		}
+	}
+
+	floats := map[string]**float64{
+		"gas.max_price_gwei":     &f.Gas.MaxPriceGwei,
+		"gas.price_multiplier":   &f.Gas.PriceMultiplier,
+		"profit.min_usd":         &f.Profit.MinUSD,
+		"health.min_balance_eth": &f.Health.MinBalanceETH,
+
+		"scan.rpc_requests_per_second": &f.Scan.RPCRequestsPerSecond,
+		"scan.watch_health_band":       &f.Scan.WatchHealthBand,
+	}
+	for key, dst := range floats {
+		if value, ok := f.lookupEnv(key); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				f.invalid(key, err)
+				continue
+			}
+			*dst = &parsed
+		}
+	}
+
+	ints := map[string]**int64{
+		"chain_id":                        &f.ChainID,
+		"update_interval_seconds":         &f.UpdateIntervalSeconds,
+		"health.max_scan_age_seconds":     &f.Health.MaxScanAgeSeconds,
+		"health.max_subgraph_age_seconds": &f.Health.MaxSubgraphAgeSeconds,
+		"health.stuck_after_seconds":      &f.Health.StuckAfterSeconds,
+		"notify.dedup_seconds":            &f.Notify.DedupSeconds,
+		"notify.rate_limit_per_minute":    &f.Notify.RateLimitPerMinute,
+		"notify.rpc_error_threshold":      &f.Notify.RPCErrorThreshold,
+		"scan.concurrency":                &f.Scan.Concurrency,
+		"scan.rpc_burst":                  &f.Scan.RPCBurst,
+		"scan.batch_size":                 &f.Scan.BatchSize,
+		"scan.full_scan_interval_seconds": &f.Scan.FullScanInterval,
+	}
+	for key, dst := range ints {
+		if value, ok := f.lookupEnv(key); ok {
+			parsed, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				f.invalid(key, err)
+				continue
+			}
+			*dst = &parsed
+		}
+	}
+
+	if value, ok := f.lookupEnv("endpoints.rpc_pool"); ok {
+		f.Endpoints.RPCPool = nil
+		for _, rawURL := range strings.Split(value, ",") {
+			if rawURL = strings.TrimSpace(rawURL); rawURL != "" {
+				f.Endpoints.RPCPool = append(f.Endpoints.RPCPool, rawURL)
+			}
+		}
+	}
+
+	uints := map[string]**uint64{
+		"gas.limit":                      &f.Gas.Limit,
+		"health.max_subgraph_lag_blocks": &f.Health.MaxSubgraphLagBlocks,
+		"scan.position_max_age_blocks":   &f.Scan.PositionMaxAgeBlocks,
+		"scan.lookahead_blocks":          &f.Scan.LookaheadBlocks,
+	}
+	for key, dst := range uints {
+		if value, ok := f.lookupEnv(key); ok {
+			parsed, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				f.invalid(key, err)
+				continue
+			}
+			*dst = &parsed
+		}
+	}
+
+	bools := map[string]**bool{
+		"startup_checks":  &f.StartupChecks,
+		"dry_run.enabled": &f.DryRun.Enabled,
+	}
+	for key, dst := range bools {
+		if value, ok := f.lookupEnv(key); ok {
+			parsed, err := strconv.ParseBool(value)
+			if err != nil {
+				f.invalid(key, err)
+				continue
+			}
+			*dst = &parsed
+		}
+	}
+}
+
+func (f *fileConfig) lookupEnv(key string) (string, bool) {
+	value, ok := os.LookupEnv(envVars[key])
+	if ok {
+		f.fromEnv[key] = true
+	}
+	return value, ok
+}
+
+// build validates every value and creates config, all problems are reported in a single ValidationError
+func (f *fileConfig) build() (*config, error) {
+	cfg := &config{
+		contractAddress: common.HexToAddress(os.Getenv("CONTRACT_ADDRESS")),
+		gas:             Gas{PriceMultiplier: 1},
+	}
+
+	if f.ChainID != nil {
+		if *f.ChainID <= 0 {
+			f.invalid("chain_id", errors.New("must be positive"))
+		} else {
+			cfg.chainID = big.NewInt(*f.ChainID)
+		}
+	}
+
+	if f.StartupChecks != nil {
+		cfg.startupChecks = *f.StartupChecks
+	}
+
+	cfg.rpcURL = f.url("endpoints.rpc", f.Endpoints.RPC, true)
+	cfg.endpoints.RPC = cfg.rpcURL
+	cfg.endpoints.Subgraph = f.url("endpoints.subgraph", f.Endpoints.Subgraph, false)
+	for i, rawURL := range f.Endpoints.RPCPool {
+		if rpcURL := f.url(fmt.Sprintf("endpoints.rpc_pool[%d]", i), rawURL, true); rpcURL != nil {
+			cfg.endpoints.RPCPool = append(cfg.endpoints.RPCPool, rpcURL)
+		}
+	}
+
+	f.buildAccount(cfg)
+
+	if f.UpdateIntervalSeconds == nil {
+		f.invalid("update_interval_seconds", errNotSet)
+	} else if *f.UpdateIntervalSeconds <= 0 {
+		f.invalid("update_interval_seconds", errors.New("must be positive"))
+	} else {
+		cfg.updateInverval = time.Second * time.Duration(*f.UpdateIntervalSeconds)
+	}
+
+	cfg.contractComptrollerAddress = f.address("contracts.comptroller", f.Contracts.Comptroller)
+	cfg.contractCusdcAddress = f.address("contracts.cusdc", f.Contracts.Cusdc)
+	cfg.contractMulticallAddress = multicall.Address
+	if f.Contracts.Multicall != "" {
+		cfg.contractMulticallAddress = f.address("contracts.multicall", f.Contracts.Multicall)
+	}
+
+	seen := map[common.Address]string{}
+	for i, m := range f.Markets {
+		key := fmt.Sprintf("markets[%d]", i)
+		market := Market{Name: m.Name, Address: f.address(key+".address", m.Address)}
+		if market.Address == (common.Address{}) {
+			continue
+		}
+
+		if other, ok := seen[market.Address]; ok {
+			f.invalid(key+".address", fmt.Errorf("duplicate of %s.address", other))
+			continue
+		}
+		seen[market.Address] = key
+
+		if market.Name == "" {
+			market.Name = market.Address.Hex()
+		}
+		cfg.markets = append(cfg.markets, market)
+	}
+
+	if len(f.Markets) == 0 {
+		cfg.markets = []Market{{Name: "cUSDC", Address: cfg.contractCusdcAddress}}
+	}
+
+	if f.Gas.MaxPriceGwei != nil {
+		if *f.Gas.MaxPriceGwei <= 0 {
+			f.invalid("gas.max_price_gwei", errors.New("must be positive"))
+		} else {
+			maxPrice, _ := new(big.Float).Mul(big.NewFloat(*f.Gas.MaxPriceGwei), big.NewFloat(1e9)).Int(nil)
+			cfg.gas.MaxPrice = maxPrice
+		}
+	}
+
+	if f.Gas.Limit != nil {
+		cfg.gas.Limit = *f.Gas.Limit
+	}
+
+	if f.Gas.PriceMultiplier != nil {
+		if *f.Gas.PriceMultiplier <= 0 {
+			f.invalid("gas.price_multiplier", errors.New("must be positive"))
+		} else {
+			cfg.gas.PriceMultiplier = *f.Gas.PriceMultiplier
+		}
+	}
+
+	if f.Profit.MinUSD != nil {
+		if *f.Profit.MinUSD < 0 {
+			f.invalid("profit.min_usd", errors.New("must not be negative"))
+		} else {
+			cfg.profit.MinUSD = *f.Profit.MinUSD
+		}
+	}
+
+	if f.Profit.ETHMarket != "" {
+		cfg.profit.ETHMarket = f.address("profit.eth_market", f.Profit.ETHMarket)
+	}
+
+	cfg.dryRun.Output = defaultDryRunOutput
+	if f.DryRun.Enabled != nil {
+		cfg.dryRun.Enabled = *f.DryRun.Enabled
+	}
+	if f.DryRun.Output != "" {
+		cfg.dryRun.Output = f.DryRun.Output
+	}
+
+	cfg.store.Path = defaultStorePath
+	if f.Store.Path != nil {
+		cfg.store.Path = *f.Store.Path
+	}
+
+	cfg.http.Listen = defaultHTTPListen
+	if f.HTTP.Listen != nil {
+		cfg.http.Listen = *f.HTTP.Listen
+	}
+
+	f.buildHealth(cfg)
+
+	f.buildNotify(cfg)
+
+	f.buildScan(cfg)
+
+	cfg.log.Level = defaultLogLevel
+	switch f.Log.Level {
+	case "":
+	case "debug", "info", "warn", "error":
+		cfg.log.Level = f.Log.Level
+	default:
+		f.invalid("log.level", errors.New("must be debug, info, warn or error"))
+	}
+
+	if len(f.problems) > 0 {
+		return nil, f.problems
+	}
+
+	return cfg, nil
+}
+
+// buildHealth validates health thresholds, the ones not set get defaults
+func (f *fileConfig) buildHealth(cfg *config) {
+	cfg.health = Health{
+		MaxScanAge:     defaultHealthMaxScanAge,
+		MaxSubgraphLag: defaultHealthMaxSubgraphLag,
+		MaxSubgraphAge: defaultHealthMaxSubgraphAge,
+		StuckAfter:     defaultHealthStuckAfter,
+	}
+
+	if f.Health.MaxScanAgeSeconds != nil {
+		if *f.Health.MaxScanAgeSeconds <= 0 {
+			f.invalid("health.max_scan_age_seconds", errors.New("must be positive"))
+		} else {
+			cfg.health.MaxScanAge = time.Second * time.Duration(*f.Health.MaxScanAgeSeconds)
+		}
+	}
+
+	if f.Health.MaxSubgraphLagBlocks != nil {
+		cfg.health.MaxSubgraphLag = *f.Health.MaxSubgraphLagBlocks
+	}
+
+	if f.Health.MaxSubgraphAgeSeconds != nil {
+		if *f.Health.MaxSubgraphAgeSeconds < 0 {
+			f.invalid("health.max_subgraph_age_seconds", errors.New("must not be negative"))
+		} else {
+			cfg.health.MaxSubgraphAge = time.Second * time.Duration(*f.Health.MaxSubgraphAgeSeconds)
+		}
+	}
+
+	if f.Health.MinBalanceETH != nil {
+		if *f.Health.MinBalanceETH < 0 {
+			f.invalid("health.min_balance_eth", errors.New("must not be negative"))
+		} else {
+			minBalance, _ := new(big.Float).Mul(big.NewFloat(*f.Health.MinBalanceETH), big.NewFloat(1e18)).Int(nil)
+			cfg.health.MinBalance = minBalance
+		}
+	}
+
+	if f.Health.StuckAfterSeconds != nil {
+		if *f.Health.StuckAfterSeconds <= 0 {
+			f.invalid("health.stuck_after_seconds", errors.New("must be positive"))
+		} else {
+			cfg.health.StuckAfter = time.Second * time.Duration(*f.Health.StuckAfterSeconds)
+		}
+	}
+}
+
+// buildScan validates scan concurrency, batch size and RPC rate limit, the burst defaults to the concurrency
+func (f *fileConfig) buildScan(cfg *config) {
+	cfg.scan = Scan{
+		Concurrency:      defaultScanConcurrency,
+		BatchSize:        defaultScanBatchSize,
+		PositionMaxAge:   defaultPositionMaxAge,
+		LookaheadBlocks:  defaultLookaheadBlocks,
+		WatchBand:        defaultWatchBand,
+		FullScanInterval: defaultFullScanInterval,
+	}
+
+	if f.Scan.Concurrency != nil {
+		if *f.Scan.Concurrency <= 0 {
+			f.invalid("scan.concurrency", errors.New("must be positive"))
+		} else {
+			cfg.scan.Concurrency = int(*f.Scan.Concurrency)
+		}
+	}
+
+	if f.Scan.RPCRequestsPerSecond != nil {
+		if *f.Scan.RPCRequestsPerSecond < 0 {
+			f.invalid("scan.rpc_requests_per_second", errors.New("must not be negative"))
+		} else {
+			cfg.scan.RPCRateLimit = *f.Scan.RPCRequestsPerSecond
+		}
+	}
+
+	if f.Scan.BatchSize != nil {
+		if *f.Scan.BatchSize <= 0 {
+			f.invalid("scan.batch_size", errors.New("must be positive"))
+		} else {
+			cfg.scan.BatchSize = int(*f.Scan.BatchSize)
+		}
+	}
+
+	if f.Scan.PositionMaxAgeBlocks != nil {
+		cfg.scan.PositionMaxAge = *f.Scan.PositionMaxAgeBlocks
+	}
+
+	if f.Scan.LookaheadBlocks != nil {
+		cfg.scan.LookaheadBlocks = *f.Scan.LookaheadBlocks
+	}
+
+	if f.Scan.WatchHealthBand != nil {
+		if *f.Scan.WatchHealthBand < 0 {
+			f.invalid("scan.watch_health_band", errors.New("must not be negative"))
+		} else {
+			cfg.scan.WatchBand = *f.Scan.WatchHealthBand
+		}
+	}
+
+	if f.Scan.FullScanInterval != nil {
+		if *f.Scan.FullScanInterval <= 0 {
+			f.invalid("scan.full_scan_interval_seconds", errors.New("must be positive"))
+		} else {
+			cfg.scan.FullScanInterval = time.Second * time.Duration(*f.Scan.FullScanInterval)
+		}
+	}
+
+	cfg.scan.RPCBurst = cfg.scan.Concurrency
+	if f.Scan.RPCBurst != nil {
+		if *f.Scan.RPCBurst <= 0 {
+			f.invalid("scan.rpc_burst", errors.New("must be positive"))
+		} else {
+			cfg.scan.RPCBurst = int(*f.Scan.RPCBurst)
+		}
+	}
+}
+
+// buildNotify validates notification sinks and limits, the limits not set get defaults
+func (f *fileConfig) buildNotify(cfg *config) {
+	cfg.notify = Notify{
+		WebhookURL:     f.url("notify.webhook_url", f.Notify.WebhookURL, false),
+		SlackURL:       f.url("notify.slack_webhook_url", f.Notify.SlackWebhookURL, false),
+		TelegramToken:  f.Notify.TelegramBotToken,
+		TelegramChatID: f.Notify.TelegramChatID,
+		TelegramAPI:    f.url("notify.telegram_api_url", f.Notify.TelegramAPIURL, false),
+		File:           f.Notify.File,
+		Dedup:          defaultNotifyDedup,
+		RateLimit:      defaultNotifyRateLimit,
+		RPCErrors:      defaultNotifyRPCErrors,
+	}
+
+	if (f.Notify.TelegramBotToken == "") != (f.Notify.TelegramChatID == "") {
+		f.invalid("notify.telegram_chat_id", errors.New("telegram bot token and chat id must be set together"))
+	}
+
+	if f.Notify.DedupSeconds != nil {
+		if *f.Notify.DedupSeconds <= 0 {
+			f.invalid("notify.dedup_seconds", errors.New("must be positive"))
+		} else {
+			cfg.notify.Dedup = time.Second * time.Duration(*f.Notify.DedupSeconds)
+		}
+	}
+
+	if f.Notify.RateLimitPerMinute != nil {
+		if *f.Notify.RateLimitPerMinute <= 0 {
+			f.invalid("notify.rate_limit_per_minute", errors.New("must be positive"))
+		} else {
+			cfg.notify.RateLimit = int(*f.Notify.RateLimitPerMinute)
+		}
+	}
+
+	if f.Notify.RPCErrorThreshold != nil {
+		if *f.Notify.RPCErrorThreshold <= 0 {
+			f.invalid("notify.rpc_error_threshold", errors.New("must be positive"))
+		} else {
+			cfg.notify.RPCErrors = int(*f.Notify.RPCErrorThreshold)
+		}
+	}
+}
+
+// buildAccount validates that exactly one key source is configured: plaintext key, keystore or remote signer
+func (f *fileConfig) buildAccount(cfg *config) {
+	sources := 0
+	for _, value := range []string{f.Account.PrivateKey, f.Account.Keystore, f.Account.SignerURL} {
+		if value != "" {
+			sources++
+		}
+	}
+
+	switch {
+	case sources == 0:
+		f.invalid("account.private_key", errors.New("not set, a keystore or a remote signer can be used instead"))
+		return
+	case sources > 1:
+		f.invalid("account", errors.New("only one of private key, keystore or remote signer can be set"))
+		return
+	}
+
+	switch {
+	case f.Account.PrivateKey != "":
+		accountKey, err := crypto.HexToECDSA(strings.TrimPrefix(strings.TrimSpace(f.Account.PrivateKey), "0x"))
+		if err != nil {
+			f.invalid("account.private_key", errors.New("invalid hex encoded secp256k1 private key"))
+			return
+		}
+		cfg.accountKey = accountKey
+		cfg.accountAddress = crypto.PubkeyToAddress(accountKey.PublicKey)
+
+	case f.Account.Keystore != "":
+		cfg.account.Keystore = f.Account.Keystore
+		cfg.account.PasswordFile = f.Account.PasswordFile
+
+		keyJSON, err := ioutil.ReadFile(f.Account.Keystore)
+		if err != nil {
+			f.invalid("account.keystore", err)
+		} else {
+			// the address is stored unencrypted, the key is decrypted by the signer
+			var key struct {
+				Address string `json:"address"`
+			}
+			if err := json.Unmarshal(keyJSON, &key); err != nil || !common.IsHexAddress(key.Address) {
+				f.invalid("account.keystore", errors.New("not a go-ethereum JSON key file"))
+			} else {
+				cfg.accountAddress = common.HexToAddress(key.Address)
+			}
+		}
+
+		if f.Account.PasswordFile == "" {
+			f.invalid("account.password_file", errNotSet)
+		} else if _, err := os.Stat(f.Account.PasswordFile); err != nil {
+			f.invalid("account.password_file", err)
+		}
+
+	case f.Account.SignerURL != "":
+		cfg.account.SignerURL = f.url("account.signer_url", f.Account.SignerURL, true)
+		cfg.account.SignerMethod = f.Account.SignerMethod
+		cfg.accountAddress = f.address("account.address", f.Account.Address)
+
+		switch f.Account.SignerMethod {
+		case "", "account_signTransaction", "eth_signTransaction":
+		default:
+			f.invalid("account.signer_method", errors.New("must be account_signTransaction or eth_signTransaction"))
+		}
+	}
+
+	cfg.account.Address = cfg.accountAddress
+}
+
+func (f *fileConfig) url(key, value string, required bool) *url.URL {
+	if value == "" {
+		if required {
+			f.invalid(key, errNotSet)
+		}
+		return nil
+	}
+
+	parsed, err := url.Parse(value)
+	if err != nil {
+		f.invalid(key, err)
+		return nil
+	}
+
+	switch parsed.Scheme {
+	case "http", "https", "ws", "wss":
+		if parsed.Host == "" {
+			f.invalid(key, errors.New("missing host"))
+			return nil
+		}
+	case "":
+		// IPC socket path
+	default:
+		f.invalid(key, fmt.Errorf("unsupported scheme %q", parsed.Scheme))
+		return nil
+	}
+
+	return parsed
+}
+
+func (f *fileConfig) address(key, value string) common.Address {
+	if value == "" {
+		f.invalid(key, errNotSet)
+		return common.Address{}
+	}
+
+	if !common.IsHexAddress(value) {
+		f.invalid(key, errors.New("invalid hex address"))
+		return common.Address{}
+	}
+
+	address := common.HexToAddress(value)
+	if address == (common.Address{}) {
+		f.invalid(key, errors.New("zero address"))
+	}
+
+	return address
+}
+
+// invalid records a problem naming the environment variable when the value came from one, the file key otherwise
+func (f *fileConfig) invalid(key string, err error) {
+	if envVar, ok := envVars[key]; ok && (f.fromEnv[key] || f.file == "") {
+		key = envVar
+	}
+	f.problems = append(f.problems, &FieldError{Key: key, Err: err})
+}
+
+var errNotSet = errors.New("not set")
+
+const defaultDryRunOutput = "dryrun.jsonl"
+
+const defaultStorePath = "liqbot.db"
+
+const defaultHTTPListen = ":9090"
+
+const defaultLogLevel = "info"
+
+const (
+	defaultScanConcurrency  = 8
+	defaultScanBatchSize    = 100
+	defaultPositionMaxAge   = 100
+	defaultLookaheadBlocks  = 20
+	defaultWatchBand        = 0.1
+	defaultFullScanInterval = 5 * time.Minute
+)
+
+const (
+	defaultNotifyDedup     = time.Hour
+	defaultNotifyRateLimit = 10
+	defaultNotifyRPCErrors = 5
+)
+
+const (
+	defaultHealthMaxScanAge     = 5 * time.Minute
+	defaultHealthMaxSubgraphLag = 50
+	defaultHealthMaxSubgraphAge = 10 * time.Minute
+	defaultHealthStuckAfter     = 10 * time.Minute
+)
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// minimalYAML is the smallest config file FromFile accepts
+const minimalYAML = `
+update_interval_seconds: 6
+endpoints:
+  rpc: http://localhost:8545
+account:
+  private_key: 4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318
+contracts:
+  comptroller: "0x3d9819210A31b4961b30EF54bE2aeD79B9c9Cd3B"
+  cusdc: "0x39AA39c021dfbaE8faC545936693aC917d5E7563"
+`
+
+// fromYAML loads minimalYAML followed by extra with env set and every other variable of envVars cleared
+func fromYAML(t *testing.T, extra string, env map[string]string) Config {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "config.yaml")
+	if err := ioutil.WriteFile(path, []byte(minimalYAML+extra), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range envVars {
+		if value, ok := os.LookupEnv(name); ok {
+			defer os.Setenv(name, value)
+		} else {
+			defer os.Unsetenv(name)
+		}
+		os.Unsetenv(name)
+	}
+	for name, value := range env {
+		os.Setenv(name, value)
+	}
+
+	cfg, err := FromFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cfg
+}
+
+func TestStorePath(t *testing.T) {
+	tests := []struct {
+		name  string
+		extra string
+		env   map[string]string
+		path  string
+	}{
+		{name: "absent", path: defaultStorePath},
+		{name: "empty", extra: "store:\n  path: \"\"\n", path: ""},
+		{name: "set", extra: "store:\n  path: /var/lib/liqbot.db\n", path: "/var/lib/liqbot.db"},
+		{name: "empty env", env: map[string]string{"STORE_PATH": ""}, path: ""},
+		{name: "env", extra: "store:\n  path: \"\"\n", env: map[string]string{"STORE_PATH": "state.db"}, path: "state.db"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if path := fromYAML(t, tt.extra, tt.env).Store().Path; path != tt.path {
+				t.Errorf("store path %q, want %q", path, tt.path)
+			}
+		})
+	}
+}
+
+func TestHTTPListen(t *testing.T) {
+	tests := []struct {
+		name   string
+		extra  string
+		env    map[string]string
+		listen string
+	}{
+		{name: "absent", listen: defaultHTTPListen},
+		{name: "empty", extra: "http:\n  listen: \"\"\n", listen: ""},
+		{name: "set", extra: "http:\n  listen: 127.0.0.1:9100\n", listen: "127.0.0.1:9100"},
+		{name: "empty env", env: map[string]string{"HTTP_LISTEN": ""}, listen: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if listen := fromYAML(t, tt.extra, tt.env).HTTP().Listen; listen != tt.listen {
+				t.Errorf("http listen %q, want %q", listen, tt.listen)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/contracts"
+)
+
+// FieldError represents invalid config value, Key is the file key or the environment variable it came from
+type FieldError struct {
+	Key string
+	Err error
+}
+
+func (e *FieldError) Error() string {
+	return e.Key + ": " + e.Err.Error()
+}
+
+// ValidationError holds every problem found in config
+type ValidationError []*FieldError
+
+func (e ValidationError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "invalid config, %d problem(s):", len(e))
+	for _, problem := range e {
+		b.WriteString("\n  ")
+		b.WriteString(problem.Error())
+	}
+	return b.String()
+}
+
+// ChainBackend is the subset of ethclient used by CheckChain
+type ChainBackend interface {
+	bind.ContractCaller
+	ChainID(ctx context.Context) (*big.Int, error)
+}
+
+// CheckChain verifies config against the chain: chain id matches, configured contracts have code
+// and markets are cTokens. All problems are reported in a single ValidationError, other errors are
+// the node failing to answer.
+func CheckChain(ctx context.Context, cfg Config, backend ChainBackend) error {
+	var problems ValidationError
+	var nodeErr error
+
+	if expected := cfg.ChainID(); expected != nil {
+		chainID, err := backend.ChainID(ctx)
+		if err != nil {
+			return fmt.Errorf("getting chain id: %v", err)
+		}
+		if chainID.Cmp(expected) != 0 {
+			problems = append(problems, &FieldError{
+				Key: "chain_id",
+				Err: fmt.Errorf("configured %s, node reports %s", expected, chainID),
+			})
+		}
+	}
+
+	checkCode := func(key string, address common.Address) bool {
+		if nodeErr != nil {
+			return false
+		}
+		code, err := backend.CodeAt(ctx, address, nil)
+		if err != nil {
+			nodeErr = fmt.Errorf("getting code of %s: %v", key, err)
+			return false
+		}
+		if len(bytes.TrimLeft(code, "\x00")) == 0 {
+			problems = append(problems, &FieldError{Key: key, Err: fmt.Errorf("no contract at %s", address.Hex())})
+			return false
+		}
+		return true
+	}
+
+	checkCToken := func(key string, address common.Address) {
+		if !checkCode(key, address) {
+			return
+		}
+
+		ctokenABI, err := contracts.CTokenMetaData.GetAbi()
+		if err != nil {
+			problems = append(problems, &FieldError{Key: key, Err: err})
+			return
+		}
+		data, err := ctokenABI.Pack("isCToken")
+		if err != nil {
+			problems = append(problems, &FieldError{Key: key, Err: err})
+			return
+		}
+
+		out, err := backend.CallContract(ctx, ethereum.CallMsg{To: &address, Data: data}, nil)
+		var rpcErr rpc.Error
+		if err != nil && !errors.As(err, &rpcErr) {
+			// not a revert, the node failed to answer
+			nodeErr = fmt.Errorf("calling isCToken of %s: %v", key, err)
+			return
+		}
+		if err != nil {
+			problems = append(problems, &FieldError{Key: key, Err: fmt.Errorf("calling isCToken: %v", err)})
+			return
+		}
+
+		// an account or a contract without isCToken answers nothing or something else
+		results, err := ctokenABI.Unpack("isCToken", out)
+		if err != nil {
+			problems = append(problems, &FieldError{Key: key, Err: fmt.Errorf("not a cToken, isCToken returned %d bytes: %v", len(out), err)})
+			return
+		}
+		if isCToken, ok := results[0].(bool); !ok || !isCToken {
+			problems = append(problems, &FieldError{Key: key, Err: errors.New("isCToken returned false")})
+		}
+	}
+
+	checkCode("contracts.comptroller", cfg.ContractComptrollerAddress())
+	checkCToken("contracts.cusdc", cfg.ContractCusdcAddress())
+
+	for i, market := range cfg.Markets() {
+		if market.Address == cfg.ContractCusdcAddress() {
+			continue
+		}
+		checkCToken(fmt.Sprintf("markets[%d].address", i), market.Address)
+	}
+
+	if nodeErr != nil {
+		return nodeErr
+	}
+	if len(problems) > 0 {
+		return problems
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+)
+
+// chainStub answers CheckChain calls, every address has code and isCToken returns out or fails with err
+type chainStub struct {
+	out []byte
+	err error
+}
+
+func (c *chainStub) ChainID(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(1), nil
+}
+
+func (c *chainStub) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return []byte{0x60, 0x80}, nil
+}
+
+func (c *chainStub) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return c.out, c.err
+}
+
+// revertError is a JSON-RPC error as the node reports reverts
+type revertError struct{}
+
+func (revertError) Error() string  { return "execution reverted" }
+func (revertError) ErrorCode() int { return 3 }
+
+func TestCheckChainCToken(t *testing.T) {
+	cfg := &config{
+		chainID:                    big.NewInt(1),
+		contractComptrollerAddress: common.HexToAddress("0x3d9819210A31b4961b30EF54bE2aeD79B9c9Cd3B"),
+		contractCusdcAddress:       common.HexToAddress("0x39AA39c021dfbaE8faC545936693aC917d5E7563"),
+	}
+
+	tests := []struct {
+		name    string
+		chain   *chainStub
+		invalid bool
+		node    bool
+	}{
+		{name: "cToken", chain: &chainStub{out: math.U256Bytes(big.NewInt(1))}},
+		{name: "isCToken false", chain: &chainStub{out: math.U256Bytes(big.NewInt(0))}, invalid: true},
+		{name: "account or contract without isCToken", chain: &chainStub{out: []byte{}}, invalid: true},
+		{name: "other return data", chain: &chainStub{out: []byte{1, 2, 3}}, invalid: true},
+		{name: "revert", chain: &chainStub{err: revertError{}}, invalid: true},
+		{name: "node not answering", chain: &chainStub{err: errors.New("connection refused")}, node: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckChain(context.Background(), cfg, tt.chain)
+			var problems ValidationError
+			switch {
+			case tt.invalid:
+				if !errors.As(err, &problems) || len(problems) != 1 || problems[0].Key != "contracts.cusdc" {
+					t.Errorf("want contracts.cusdc problem, got %v", err)
+				}
+			case tt.node:
+				if err == nil || errors.As(err, &problems) {
+					t.Errorf("want node error, got %v", err)
+				}
+			default:
+				if err != nil {
+					t.Errorf("want no error, got %v", err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,505 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package contracts
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = errors.New
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+	_ = abi.ConvertType
+)
+
+// ComptrollerCoreMetaData contains all meta data concerning the ComptrollerCore contract.
+var ComptrollerCoreMetaData = &bind.MetaData{
+	ABI: "[{\"constant\":true,\"inputs\":[{\"name\":\"account\",\"type\":\"address\"}],\"name\":\"getAccountLiquidity\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"},{\"name\":\"\",\"type\":\"uint256\"},{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"account\",\"type\":\"address\"},{\"name\":\"cTokenModify\",\"type\":\"address\"},{\"name\":\"redeemTokens\",\"type\":\"uint256\"},{\"name\":\"borrowAmount\",\"type\":\"uint256\"}],\"name\":\"getHypotheticalAccountLiquidity\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"},{\"name\":\"\",\"type\":\"uint256\"},{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"closeFactorMantissa\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"liquidationIncentiveMantissa\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"oracle\",\"outputs\":[{\"name\":\"\",\"type\":\"address\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"\",\"type\":\"address\"}],\"name\":\"markets\",\"outputs\":[{\"name\":\"isListed\",\"type\":\"bool\"},{\"name\":\"collateralFactorMantissa\",\"type\":\"uint256\"},{\"name\":\"isComped\",\"type\":\"bool\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"account\",\"type\":\"address\"}],\"name\":\"getAssetsIn\",\"outputs\":[{\"name\":\"\",\"type\":\"address[]\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"getAllMarkets\",\"outputs\":[{\"name\":\"\",\"type\":\"address[]\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"cTokenBorrowed\",\"type\":\"address\"},{\"name\":\"cTokenCollateral\",\"type\":\"address\"},{\"name\":\"actualRepayAmount\",\"type\":\"uint256\"}],\"name\":\"liquidateCalculateSeizeTokens\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"},{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"cTokens\",\"type\":\"address[]\"}],\"name\":\"enterMarkets\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256[]\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"}]",
+}
+
+// ComptrollerCoreABI is the input ABI used to generate the binding from.
+// Deprecated: Use ComptrollerCoreMetaData.ABI instead.
+var ComptrollerCoreABI = ComptrollerCoreMetaData.ABI
+
+// ComptrollerCore is an auto generated Go binding around an Ethereum contract.
+type ComptrollerCore struct {
+	ComptrollerCoreCaller     // Read-only binding to the contract
+	ComptrollerCoreTransactor // Write-only binding to the contract
+	ComptrollerCoreFilterer   // Log filterer for contract events
+}
+
+// ComptrollerCoreCaller is an auto generated read-only Go binding around an Ethereum contract.
+type ComptrollerCoreCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// ComptrollerCoreTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type ComptrollerCoreTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// ComptrollerCoreFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type ComptrollerCoreFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// ComptrollerCoreSession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type ComptrollerCoreSession struct {
+	Contract     *ComptrollerCore  // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// ComptrollerCoreCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type ComptrollerCoreCallerSession struct {
+	Contract *ComptrollerCoreCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts          // Call options to use throughout this session
+}
+
+// ComptrollerCoreTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type ComptrollerCoreTransactorSession struct {
+	Contract     *ComptrollerCoreTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts          // Transaction auth options to use throughout this session
+}
+
+// ComptrollerCoreRaw is an auto generated low-level Go binding around an Ethereum contract.
+type ComptrollerCoreRaw struct {
+	Contract *ComptrollerCore // Generic contract binding to access the raw methods on
+}
+
+// ComptrollerCoreCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type ComptrollerCoreCallerRaw struct {
+	Contract *ComptrollerCoreCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// ComptrollerCoreTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type ComptrollerCoreTransactorRaw struct {
+	Contract *ComptrollerCoreTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewComptrollerCore creates a new instance of ComptrollerCore, bound to a specific deployed contract.
+func NewComptrollerCore(address common.Address, backend bind.ContractBackend) (*ComptrollerCore, error) {
+	contract, err := bindComptrollerCore(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &ComptrollerCore{ComptrollerCoreCaller: ComptrollerCoreCaller{contract: contract}, ComptrollerCoreTransactor: ComptrollerCoreTransactor{contract: contract}, ComptrollerCoreFilterer: ComptrollerCoreFilterer{contract: contract}}, nil
+}
+
+// NewComptrollerCoreCaller creates a new read-only instance of ComptrollerCore, bound to a specific deployed contract.
+func NewComptrollerCoreCaller(address common.Address, caller bind.ContractCaller) (*ComptrollerCoreCaller, error) {
+	contract, err := bindComptrollerCore(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &ComptrollerCoreCaller{contract: contract}, nil
+}
+
+// NewComptrollerCoreTransactor creates a new write-only instance of ComptrollerCore, bound to a specific deployed contract.
+func NewComptrollerCoreTransactor(address common.Address, transactor bind.ContractTransactor) (*ComptrollerCoreTransactor, error) {
+	contract, err := bindComptrollerCore(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &ComptrollerCoreTransactor{contract: contract}, nil
+}
+
+// NewComptrollerCoreFilterer creates a new log filterer instance of ComptrollerCore, bound to a specific deployed contract.
+func NewComptrollerCoreFilterer(address common.Address, filterer bind.ContractFilterer) (*ComptrollerCoreFilterer, error) {
+	contract, err := bindComptrollerCore(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &ComptrollerCoreFilterer{contract: contract}, nil
+}
+
+// bindComptrollerCore binds a generic wrapper to an already deployed contract.
+func bindComptrollerCore(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := ComptrollerCoreMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, *parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_ComptrollerCore *ComptrollerCoreRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _ComptrollerCore.Contract.ComptrollerCoreCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_ComptrollerCore *ComptrollerCoreRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _ComptrollerCore.Contract.ComptrollerCoreTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_ComptrollerCore *ComptrollerCoreRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _ComptrollerCore.Contract.ComptrollerCoreTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_ComptrollerCore *ComptrollerCoreCallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _ComptrollerCore.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_ComptrollerCore *ComptrollerCoreTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _ComptrollerCore.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_ComptrollerCore *ComptrollerCoreTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _ComptrollerCore.Contract.contract.Transact(opts, method, params...)
+}
+
+// CloseFactorMantissa is a free data retrieval call binding the contract method 0xe8755446.
+//
+// Solidity: function closeFactorMantissa() view returns(uint256)
+func (_ComptrollerCore *ComptrollerCoreCaller) CloseFactorMantissa(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _ComptrollerCore.contract.Call(opts, &out, "closeFactorMantissa")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// CloseFactorMantissa is a free data retrieval call binding the contract method 0xe8755446.
+//
+// Solidity: function closeFactorMantissa() view returns(uint256)
+func (_ComptrollerCore *ComptrollerCoreSession) CloseFactorMantissa() (*big.Int, error) {
+	return _ComptrollerCore.Contract.CloseFactorMantissa(&_ComptrollerCore.CallOpts)
+}
+
+// CloseFactorMantissa is a free data retrieval call binding the contract method 0xe8755446.
+//
+// Solidity: function closeFactorMantissa() view returns(uint256)
+func (_ComptrollerCore *ComptrollerCoreCallerSession) CloseFactorMantissa() (*big.Int, error) {
+	return _ComptrollerCore.Contract.CloseFactorMantissa(&_ComptrollerCore.CallOpts)
+}
+
+// GetAccountLiquidity is a free data retrieval call binding the contract method 0x5ec88c79.
+//
+// Solidity: function getAccountLiquidity(address account) view returns(uint256, uint256, uint256)
+func (_ComptrollerCore *ComptrollerCoreCaller) GetAccountLiquidity(opts *bind.CallOpts, account common.Address) (*big.Int, *big.Int, *big.Int, error) {
+	var out []interface{}
+	err := _ComptrollerCore.contract.Call(opts, &out, "getAccountLiquidity", account)
+
+	if err != nil {
+		return *new(*big.Int), *new(*big.Int), *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+	out1 := *abi.ConvertType(out[1], new(*big.Int)).(**big.Int)
+	out2 := *abi.ConvertType(out[2], new(*big.Int)).(**big.Int)
+
+	return out0, out1, out2, err
+
+}
+
+// GetAccountLiquidity is a free data retrieval call binding the contract method 0x5ec88c79.
+//
+// Solidity: function getAccountLiquidity(address account) view returns(uint256, uint256, uint256)
+func (_ComptrollerCore *ComptrollerCoreSession) GetAccountLiquidity(account common.Address) (*big.Int, *big.Int, *big.Int, error) {
+	return _ComptrollerCore.Contract.GetAccountLiquidity(&_ComptrollerCore.CallOpts, account)
+}
+
+// GetAccountLiquidity is a free data retrieval call binding the contract method 0x5ec88c79.
+//
+// Solidity: function getAccountLiquidity(address account) view returns(uint256, uint256, uint256)
+func (_ComptrollerCore *ComptrollerCoreCallerSession) GetAccountLiquidity(account common.Address) (*big.Int, *big.Int, *big.Int, error) {
+	return _ComptrollerCore.Contract.GetAccountLiquidity(&_ComptrollerCore.CallOpts, account)
+}
+
+// GetAllMarkets is a free data retrieval call binding the contract method 0xb0772d0b.
+//
+// Solidity: function getAllMarkets() view returns(address[])
+func (_ComptrollerCore *ComptrollerCoreCaller) GetAllMarkets(opts *bind.CallOpts) ([]common.Address, error) {
+	var out []interface{}
+	err := _ComptrollerCore.contract.Call(opts, &out, "getAllMarkets")
+
+	if err != nil {
+		return *new([]common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([]common.Address)).(*[]common.Address)
+
+	return out0, err
+
+}
+
+// GetAllMarkets is a free data retrieval call binding the contract method 0xb0772d0b.
+//
+// Solidity: function getAllMarkets() view returns(address[])
+func (_ComptrollerCore *ComptrollerCoreSession) GetAllMarkets() ([]common.Address, error) {
+	return _ComptrollerCore.Contract.GetAllMarkets(&_ComptrollerCore.CallOpts)
+}
+
+// GetAllMarkets is a free data retrieval call binding the contract method 0xb0772d0b.
+//
+// Solidity: function getAllMarkets() view returns(address[])
+func (_ComptrollerCore *ComptrollerCoreCallerSession) GetAllMarkets() ([]common.Address, error) {
+	return _ComptrollerCore.Contract.GetAllMarkets(&_ComptrollerCore.CallOpts)
+}
+
+// GetAssetsIn is a free data retrieval call binding the contract method 0xabfceffc.
+//
+// Solidity: function getAssetsIn(address account) view returns(address[])
+func (_ComptrollerCore *ComptrollerCoreCaller) GetAssetsIn(opts *bind.CallOpts, account common.Address) ([]common.Address, error) {
+	var out []interface{}
+	err := _ComptrollerCore.contract.Call(opts, &out, "getAssetsIn", account)
+
+	if err != nil {
+		return *new([]common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([]common.Address)).(*[]common.Address)
+
+	return out0, err
+
+}
+
+// GetAssetsIn is a free data retrieval call binding the contract method 0xabfceffc.
+//
+// Solidity: function getAssetsIn(address account) view returns(address[])
+func (_ComptrollerCore *ComptrollerCoreSession) GetAssetsIn(account common.Address) ([]common.Address, error) {
+	return _ComptrollerCore.Contract.GetAssetsIn(&_ComptrollerCore.CallOpts, account)
+}
+
+// GetAssetsIn is a free data retrieval call binding the contract method 0xabfceffc.
+//
+// Solidity: function getAssetsIn(address account) view returns(address[])
+func (_ComptrollerCore *ComptrollerCoreCallerSession) GetAssetsIn(account common.Address) ([]common.Address, error) {
+	return _ComptrollerCore.Contract.GetAssetsIn(&_ComptrollerCore.CallOpts, account)
+}
+
+// GetHypotheticalAccountLiquidity is a free data retrieval call binding the contract method 0x4e79238f.
+//
+// Solidity: function getHypotheticalAccountLiquidity(address account, address cTokenModify, uint256 redeemTokens, uint256 borrowAmount) view returns(uint256, uint256, uint256)
+func (_ComptrollerCore *ComptrollerCoreCaller) GetHypotheticalAccountLiquidity(opts *bind.CallOpts, account common.Address, cTokenModify common.Address, redeemTokens *big.Int, borrowAmount *big.Int) (*big.Int, *big.Int, *big.Int, error) {
+	var out []interface{}
+	err := _ComptrollerCore.contract.Call(opts, &out, "getHypotheticalAccountLiquidity", account, cTokenModify, redeemTokens, borrowAmount)
+
+	if err != nil {
+		return *new(*big.Int), *new(*big.Int), *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+	out1 := *abi.ConvertType(out[1], new(*big.Int)).(**big.Int)
+	out2 := *abi.ConvertType(out[2], new(*big.Int)).(**big.Int)
+
+	return out0, out1, out2, err
+
+}
+
+// GetHypotheticalAccountLiquidity is a free data retrieval call binding the contract method 0x4e79238f.
+//
+// Solidity: function getHypotheticalAccountLiquidity(address account, address cTokenModify, uint256 redeemTokens, uint256 borrowAmount) view returns(uint256, uint256, uint256)
+func (_ComptrollerCore *ComptrollerCoreSession) GetHypotheticalAccountLiquidity(account common.Address, cTokenModify common.Address, redeemTokens *big.Int, borrowAmount *big.Int) (*big.Int, *big.Int, *big.Int, error) {
+	return _ComptrollerCore.Contract.GetHypotheticalAccountLiquidity(&_ComptrollerCore.CallOpts, account, cTokenModify, redeemTokens, borrowAmount)
+}
+
+// GetHypotheticalAccountLiquidity is a free data retrieval call binding the contract method 0x4e79238f.
+//
+// Solidity: function getHypotheticalAccountLiquidity(address account, address cTokenModify, uint256 redeemTokens, uint256 borrowAmount) view returns(uint256, uint256, uint256)
+func (_ComptrollerCore *ComptrollerCoreCallerSession) GetHypotheticalAccountLiquidity(account common.Address, cTokenModify common.Address, redeemTokens *big.Int, borrowAmount *big.Int) (*big.Int, *big.Int, *big.Int, error) {
+	return _ComptrollerCore.Contract.GetHypotheticalAccountLiquidity(&_ComptrollerCore.CallOpts, account, cTokenModify, redeemTokens, borrowAmount)
+}
+
+// LiquidateCalculateSeizeTokens is a free data retrieval call binding the contract method 0xc488847b.
+//
+// Solidity: function liquidateCalculateSeizeTokens(address cTokenBorrowed, address cTokenCollateral, uint256 actualRepayAmount) view returns(uint256, uint256)
+func (_ComptrollerCore *ComptrollerCoreCaller) LiquidateCalculateSeizeTokens(opts *bind.CallOpts, cTokenBorrowed common.Address, cTokenCollateral common.Address, actualRepayAmount *big.Int) (*big.Int, *big.Int, error) {
+	var out []interface{}
+	err := _ComptrollerCore.contract.Call(opts, &out, "liquidateCalculateSeizeTokens", cTokenBorrowed, cTokenCollateral, actualRepayAmount)
+
+	if err != nil {
+		return *new(*big.Int), *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+	out1 := *abi.ConvertType(out[1], new(*big.Int)).(**big.Int)
+
+	return out0, out1, err
+
+}
+
+// LiquidateCalculateSeizeTokens is a free data retrieval call binding the contract method 0xc488847b.
+//
+// Solidity: function liquidateCalculateSeizeTokens(address cTokenBorrowed, address cTokenCollateral, uint256 actualRepayAmount) view returns(uint256, uint256)
+func (_ComptrollerCore *ComptrollerCoreSession) LiquidateCalculateSeizeTokens(cTokenBorrowed common.Address, cTokenCollateral common.Address, actualRepayAmount *big.Int) (*big.Int, *big.Int, error) {
+	return _ComptrollerCore.Contract.LiquidateCalculateSeizeTokens(&_ComptrollerCore.CallOpts, cTokenBorrowed, cTokenCollateral, actualRepayAmount)
+}
+
+// LiquidateCalculateSeizeTokens is a free data retrieval call binding the contract method 0xc488847b.
+//
+// Solidity: function liquidateCalculateSeizeTokens(address cTokenBorrowed, address cTokenCollateral, uint256 actualRepayAmount) view returns(uint256, uint256)
+func (_ComptrollerCore *ComptrollerCoreCallerSession) LiquidateCalculateSeizeTokens(cTokenBorrowed common.Address, cTokenCollateral common.Address, actualRepayAmount *big.Int) (*big.Int, *big.Int, error) {
+	return _ComptrollerCore.Contract.LiquidateCalculateSeizeTokens(&_ComptrollerCore.CallOpts, cTokenBorrowed, cTokenCollateral, actualRepayAmount)
+}
+
+// LiquidationIncentiveMantissa is a free data retrieval call binding the contract method 0x4ada90af.
+//
+// Solidity: function liquidationIncentiveMantissa() view returns(uint256)
+func (_ComptrollerCore *ComptrollerCoreCaller) LiquidationIncentiveMantissa(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _ComptrollerCore.contract.Call(opts, &out, "liquidationIncentiveMantissa")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// LiquidationIncentiveMantissa is a free data retrieval call binding the contract method 0x4ada90af.
+//
+// Solidity: function liquidationIncentiveMantissa() view returns(uint256)
+func (_ComptrollerCore *ComptrollerCoreSession) LiquidationIncentiveMantissa() (*big.Int, error) {
+	return _ComptrollerCore.Contract.LiquidationIncentiveMantissa(&_ComptrollerCore.CallOpts)
+}
+
+// LiquidationIncentiveMantissa is a free data retrieval call binding the contract method 0x4ada90af.
+//
+// Solidity: function liquidationIncentiveMantissa() view returns(uint256)
+func (_ComptrollerCore *ComptrollerCoreCallerSession) LiquidationIncentiveMantissa() (*big.Int, error) {
+	return _ComptrollerCore.Contract.LiquidationIncentiveMantissa(&_ComptrollerCore.CallOpts)
+}
+
+// Markets is a free data retrieval call binding the contract method 0x8e8f294b.
+//
+// Solidity: function markets(address ) view returns(bool isListed, uint256 collateralFactorMantissa, bool isComped)
+func (_ComptrollerCore *ComptrollerCoreCaller) Markets(opts *bind.CallOpts, arg0 common.Address) (struct {
+	IsListed                 bool
+	CollateralFactorMantissa *big.Int
+	IsComped                 bool
+}, error) {
+	var out []interface{}
+	err := _ComptrollerCore.contract.Call(opts, &out, "markets", arg0)
+
+	outstruct := new(struct {
+		IsListed                 bool
+		CollateralFactorMantissa *big.Int
+		IsComped                 bool
+	})
+	if err != nil {
+		return *outstruct, err
+	}
+
+	outstruct.IsListed = *abi.ConvertType(out[0], new(bool)).(*bool)
+	outstruct.CollateralFactorMantissa = *abi.ConvertType(out[1], new(*big.Int)).(**big.Int)
+	outstruct.IsComped = *abi.ConvertType(out[2], new(bool)).(*bool)
+
+	return *outstruct, err
+
+}
+
+// Markets is a free data retrieval call binding the contract method 0x8e8f294b.
+//
+// Solidity: function markets(address ) view returns(bool isListed, uint256 collateralFactorMantissa, bool isComped)
+func (_ComptrollerCore *ComptrollerCoreSession) Markets(arg0 common.Address) (struct {
+	IsListed                 bool
+	CollateralFactorMantissa *big.Int
+	IsComped                 bool
+}, error) {
+	return _ComptrollerCore.Contract.Markets(&_ComptrollerCore.CallOpts, arg0)
+}
+
+// Markets is a free data retrieval call binding the contract method 0x8e8f294b.
+//
+// Solidity: function markets(address ) view returns(bool isListed, uint256 collateralFactorMantissa, bool isComped)
+func (_ComptrollerCore *ComptrollerCoreCallerSession) Markets(arg0 common.Address) (struct {
+	IsListed                 bool
+	CollateralFactorMantissa *big.Int
+	IsComped                 bool
+}, error) {
+	return _ComptrollerCore.Contract.Markets(&_ComptrollerCore.CallOpts, arg0)
+}
+
+// Oracle is a free data retrieval call binding the contract method 0x7dc0d1d0.
+//
+// Solidity: function oracle() view returns(address)
+func (_ComptrollerCore *ComptrollerCoreCaller) Oracle(opts *bind.CallOpts) (common.Address, error) {
+	var out []interface{}
+	err := _ComptrollerCore.contract.Call(opts, &out, "oracle")
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// Oracle is a free data retrieval call binding the contract method 0x7dc0d1d0.
+//
+// Solidity: function oracle() view returns(address)
+func (_ComptrollerCore *ComptrollerCoreSession) Oracle() (common.Address, error) {
+	return _ComptrollerCore.Contract.Oracle(&_ComptrollerCore.CallOpts)
+}
+
+// Oracle is a free data retrieval call binding the contract method 0x7dc0d1d0.
+//
+// Solidity: function oracle() view returns(address)
+func (_ComptrollerCore *ComptrollerCoreCallerSession) Oracle() (common.Address, error) {
+	return _ComptrollerCore.Contract.Oracle(&_ComptrollerCore.CallOpts)
+}
+
+// EnterMarkets is a paid mutator transaction binding the contract method 0xc2998238.
+//
+// Solidity: function enterMarkets(address[] cTokens) returns(uint256[])
+func (_ComptrollerCore *ComptrollerCoreTransactor) EnterMarkets(opts *bind.TransactOpts, cTokens []common.Address) (*types.Transaction, error) {
+	return _ComptrollerCore.contract.Transact(opts, "enterMarkets", cTokens)
+}
+
+// EnterMarkets is a paid mutator transaction binding the contract method 0xc2998238.
+//
+// Solidity: function enterMarkets(address[] cTokens) returns(uint256[])
+func (_ComptrollerCore *ComptrollerCoreSession) EnterMarkets(cTokens []common.Address) (*types.Transaction, error) {
+	return _ComptrollerCore.Contract.EnterMarkets(&_ComptrollerCore.TransactOpts, cTokens)
+}
+
+// EnterMarkets is a paid mutator transaction binding the contract method 0xc2998238.
+//
+// Solidity: function enterMarkets(address[] cTokens) returns(uint256[])
+func (_ComptrollerCore *ComptrollerCoreTransactorSession) EnterMarkets(cTokens []common.Address) (*types.Transaction, error) {
+	return _ComptrollerCore.Contract.EnterMarkets(&_ComptrollerCore.TransactOpts, cTokens)
+}
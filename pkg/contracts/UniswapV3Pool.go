@@ -0,0 +1,640 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package contracts
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = errors.New
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+	_ = abi.ConvertType
+)
+
+// UniswapV3PoolMetaData contains all meta data concerning the UniswapV3Pool contract.
+var UniswapV3PoolMetaData = &bind.MetaData{
+	ABI: "[{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"sender\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"recipient\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"int256\",\"name\":\"amount0\",\"type\":\"int256\"},{\"indexed\":false,\"internalType\":\"int256\",\"name\":\"amount1\",\"type\":\"int256\"},{\"indexed\":false,\"internalType\":\"uint160\",\"name\":\"sqrtPriceX96\",\"type\":\"uint160\"},{\"indexed\":false,\"internalType\":\"uint128\",\"name\":\"liquidity\",\"type\":\"uint128\"},{\"indexed\":false,\"internalType\":\"int24\",\"name\":\"tick\",\"type\":\"int24\"}],\"name\":\"Swap\",\"type\":\"event\"},{\"inputs\":[],\"name\":\"factory\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"fee\",\"outputs\":[{\"internalType\":\"uint24\",\"name\":\"\",\"type\":\"uint24\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"liquidity\",\"outputs\":[{\"internalType\":\"uint128\",\"name\":\"\",\"type\":\"uint128\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint32[]\",\"name\":\"secondsAgos\",\"type\":\"uint32[]\"}],\"name\":\"observe\",\"outputs\":[{\"internalType\":\"int56[]\",\"name\":\"tickCumulatives\",\"type\":\"int56[]\"},{\"internalType\":\"uint160[]\",\"name\":\"secondsPerLiquidityCumulativeX128s\",\"type\":\"uint160[]\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"slot0\",\"outputs\":[{\"internalType\":\"uint160\",\"name\":\"sqrtPriceX96\",\"type\":\"uint160\"},{\"internalType\":\"int24\",\"name\":\"tick\",\"type\":\"int24\"},{\"internalType\":\"uint16\",\"name\":\"observationIndex\",\"type\":\"uint16\"},{\"internalType\":\"uint16\",\"name\":\"observationCardinality\",\"type\":\"uint16\"},{\"internalType\":\"uint16\",\"name\":\"observationCardinalityNext\",\"type\":\"uint16\"},{\"internalType\":\"uint8\",\"name\":\"feeProtocol\",\"type\":\"uint8\"},{\"internalType\":\"bool\",\"name\":\"unlocked\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"tickSpacing\",\"outputs\":[{\"internalType\":\"int24\",\"name\":\"\",\"type\":\"int24\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"token0\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"token1\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"}]",
+}
+
+// UniswapV3PoolABI is the input ABI used to generate the binding from.
+// Deprecated: Use UniswapV3PoolMetaData.ABI instead.
+var UniswapV3PoolABI = UniswapV3PoolMetaData.ABI
+
+// UniswapV3Pool is an auto generated Go binding around an Ethereum contract.
+type UniswapV3Pool struct {
+	UniswapV3PoolCaller     // Read-only binding to the contract
+	UniswapV3PoolTransactor // Write-only binding to the contract
+	UniswapV3PoolFilterer   // Log filterer for contract events
+}
+
+// UniswapV3PoolCaller is an auto generated read-only Go binding around an Ethereum contract.
+type UniswapV3PoolCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// UniswapV3PoolTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type UniswapV3PoolTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// UniswapV3PoolFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type UniswapV3PoolFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// UniswapV3PoolSession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type UniswapV3PoolSession struct {
+	Contract     *UniswapV3Pool    // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// UniswapV3PoolCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type UniswapV3PoolCallerSession struct {
+	Contract *UniswapV3PoolCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts        // Call options to use throughout this session
+}
+
+// UniswapV3PoolTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type UniswapV3PoolTransactorSession struct {
+	Contract     *UniswapV3PoolTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts        // Transaction auth options to use throughout this session
+}
+
+// UniswapV3PoolRaw is an auto generated low-level Go binding around an Ethereum contract.
+type UniswapV3PoolRaw struct {
+	Contract *UniswapV3Pool // Generic contract binding to access the raw methods on
+}
+
+// UniswapV3PoolCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type UniswapV3PoolCallerRaw struct {
+	Contract *UniswapV3PoolCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// UniswapV3PoolTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type UniswapV3PoolTransactorRaw struct {
+	Contract *UniswapV3PoolTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewUniswapV3Pool creates a new instance of UniswapV3Pool, bound to a specific deployed contract.
+func NewUniswapV3Pool(address common.Address, backend bind.ContractBackend) (*UniswapV3Pool, error) {
+	contract, err := bindUniswapV3Pool(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &UniswapV3Pool{UniswapV3PoolCaller: UniswapV3PoolCaller{contract: contract}, UniswapV3PoolTransactor: UniswapV3PoolTransactor{contract: contract}, UniswapV3PoolFilterer: UniswapV3PoolFilterer{contract: contract}}, nil
+}
+
+// NewUniswapV3PoolCaller creates a new read-only instance of UniswapV3Pool, bound to a specific deployed contract.
+func NewUniswapV3PoolCaller(address common.Address, caller bind.ContractCaller) (*UniswapV3PoolCaller, error) {
+	contract, err := bindUniswapV3Pool(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &UniswapV3PoolCaller{contract: contract}, nil
+}
+
+// NewUniswapV3PoolTransactor creates a new write-only instance of UniswapV3Pool, bound to a specific deployed contract.
+func NewUniswapV3PoolTransactor(address common.Address, transactor bind.ContractTransactor) (*UniswapV3PoolTransactor, error) {
+	contract, err := bindUniswapV3Pool(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &UniswapV3PoolTransactor{contract: contract}, nil
+}
+
+// NewUniswapV3PoolFilterer creates a new log filterer instance of UniswapV3Pool, bound to a specific deployed contract.
+func NewUniswapV3PoolFilterer(address common.Address, filterer bind.ContractFilterer) (*UniswapV3PoolFilterer, error) {
+	contract, err := bindUniswapV3Pool(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &UniswapV3PoolFilterer{contract: contract}, nil
+}
+
+// bindUniswapV3Pool binds a generic wrapper to an already deployed contract.
+func bindUniswapV3Pool(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := UniswapV3PoolMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, *parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_UniswapV3Pool *UniswapV3PoolRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _UniswapV3Pool.Contract.UniswapV3PoolCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_UniswapV3Pool *UniswapV3PoolRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _UniswapV3Pool.Contract.UniswapV3PoolTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_UniswapV3Pool *UniswapV3PoolRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _UniswapV3Pool.Contract.UniswapV3PoolTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_UniswapV3Pool *UniswapV3PoolCallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _UniswapV3Pool.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_UniswapV3Pool *UniswapV3PoolTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _UniswapV3Pool.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_UniswapV3Pool *UniswapV3PoolTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _UniswapV3Pool.Contract.contract.Transact(opts, method, params...)
+}
+
+// Factory is a free data retrieval call binding the contract method 0xc45a0155.
+//
+// Solidity: function factory() view returns(address)
+func (_UniswapV3Pool *UniswapV3PoolCaller) Factory(opts *bind.CallOpts) (common.Address, error) {
+	var out []interface{}
+	err := _UniswapV3Pool.contract.Call(opts, &out, "factory")
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// Factory is a free data retrieval call binding the contract method 0xc45a0155.
+//
+// Solidity: function factory() view returns(address)
+func (_UniswapV3Pool *UniswapV3PoolSession) Factory() (common.Address, error) {
+	return _UniswapV3Pool.Contract.Factory(&_UniswapV3Pool.CallOpts)
+}
+
+// Factory is a free data retrieval call binding the contract method 0xc45a0155.
+//
+// Solidity: function factory() view returns(address)
+func (_UniswapV3Pool *UniswapV3PoolCallerSession) Factory() (common.Address, error) {
+	return _UniswapV3Pool.Contract.Factory(&_UniswapV3Pool.CallOpts)
+}
+
+// Fee is a free data retrieval call binding the contract method 0xddca3f43.
+//
+// Solidity: function fee() view returns(uint24)
+func (_UniswapV3Pool *UniswapV3PoolCaller) Fee(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _UniswapV3Pool.contract.Call(opts, &out, "fee")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// Fee is a free data retrieval call binding the contract method 0xddca3f43.
+//
+// Solidity: function fee() view returns(uint24)
+func (_UniswapV3Pool *UniswapV3PoolSession) Fee() (*big.Int, error) {
+	return _UniswapV3Pool.Contract.Fee(&_UniswapV3Pool.CallOpts)
+}
+
+// Fee is a free data retrieval call binding the contract method 0xddca3f43.
+//
+// Solidity: function fee() view returns(uint24)
+func (_UniswapV3Pool *UniswapV3PoolCallerSession) Fee() (*big.Int, error) {
+	return _UniswapV3Pool.Contract.Fee(&_UniswapV3Pool.CallOpts)
+}
+
+// Liquidity is a free data retrieval call binding the contract method 0x1a686502.
+//
+// Solidity: function liquidity() view returns(uint128)
+func (_UniswapV3Pool *UniswapV3PoolCaller) Liquidity(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _UniswapV3Pool.contract.Call(opts, &out, "liquidity")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// Liquidity is a free data retrieval call binding the contract method 0x1a686502.
+//
+// Solidity: function liquidity() view returns(uint128)
+func (_UniswapV3Pool *UniswapV3PoolSession) Liquidity() (*big.Int, error) {
+	return _UniswapV3Pool.Contract.Liquidity(&_UniswapV3Pool.CallOpts)
+}
+
+// Liquidity is a free data retrieval call binding the contract method 0x1a686502.
+//
+// Solidity: function liquidity() view returns(uint128)
+func (_UniswapV3Pool *UniswapV3PoolCallerSession) Liquidity() (*big.Int, error) {
+	return _UniswapV3Pool.Contract.Liquidity(&_UniswapV3Pool.CallOpts)
+}
+
+// Observe is a free data retrieval call binding the contract method 0x883bdbfd.
+//
+// Solidity: function observe(uint32[] secondsAgos) view returns(int56[] tickCumulatives, uint160[] secondsPerLiquidityCumulativeX128s)
+func (_UniswapV3Pool *UniswapV3PoolCaller) Observe(opts *bind.CallOpts, secondsAgos []uint32) (struct {
+	TickCumulatives                    []*big.Int
+	SecondsPerLiquidityCumulativeX128s []*big.Int
+}, error) {
+	var out []interface{}
+	err := _UniswapV3Pool.contract.Call(opts, &out, "observe", secondsAgos)
+
+	outstruct := new(struct {
+		TickCumulatives                    []*big.Int
+		SecondsPerLiquidityCumulativeX128s []*big.Int
+	})
+	if err != nil {
+		return *outstruct, err
+	}
+
+	outstruct.TickCumulatives = *abi.ConvertType(out[0], new([]*big.Int)).(*[]*big.Int)
+	outstruct.SecondsPerLiquidityCumulativeX128s = *abi.ConvertType(out[1], new([]*big.Int)).(*[]*big.Int)
+
+	return *outstruct, err
+
+}
+
+// Observe is a free data retrieval call binding the contract method 0x883bdbfd.
+//
+// Solidity: function observe(uint32[] secondsAgos) view returns(int56[] tickCumulatives, uint160[] secondsPerLiquidityCumulativeX128s)
+func (_UniswapV3Pool *UniswapV3PoolSession) Observe(secondsAgos []uint32) (struct {
+	TickCumulatives                    []*big.Int
+	SecondsPerLiquidityCumulativeX128s []*big.Int
+}, error) {
+	return _UniswapV3Pool.Contract.Observe(&_UniswapV3Pool.CallOpts, secondsAgos)
+}
+
+// Observe is a free data retrieval call binding the contract method 0x883bdbfd.
+//
+// Solidity: function observe(uint32[] secondsAgos) view returns(int56[] tickCumulatives, uint160[] secondsPerLiquidityCumulativeX128s)
+func (_UniswapV3Pool *UniswapV3PoolCallerSession) Observe(secondsAgos []uint32) (struct {
+	TickCumulatives                    []*big.Int
+	SecondsPerLiquidityCumulativeX128s []*big.Int
+}, error) {
+	return _UniswapV3Pool.Contract.Observe(&_UniswapV3Pool.CallOpts, secondsAgos)
+}
+
+// Slot0 is a free data retrieval call binding the contract method 0x3850c7bd.
+//
+// Solidity: function slot0() view returns(uint160 sqrtPriceX96, int24 tick, uint16 observationIndex, uint16 observationCardinality, uint16 observationCardinalityNext, uint8 feeProtocol, bool unlocked)
+func (_UniswapV3Pool *UniswapV3PoolCaller) Slot0(opts *bind.CallOpts) (struct {
+	SqrtPriceX96               *big.Int
+	Tick                       *big.Int
+	ObservationIndex           uint16
+	ObservationCardinality     uint16
+	ObservationCardinalityNext uint16
+	FeeProtocol                uint8
+	Unlocked                   bool
+}, error) {
+	var out []interface{}
+	err := _UniswapV3Pool.contract.Call(opts, &out, "slot0")
+
+	outstruct := new(struct {
+		SqrtPriceX96               *big.Int
+		Tick                       *big.Int
+		ObservationIndex           uint16
+		ObservationCardinality     uint16
+		ObservationCardinalityNext uint16
+		FeeProtocol                uint8
+		Unlocked                   bool
+	})
+	if err != nil {
+		return *outstruct, err
+	}
+
+	outstruct.SqrtPriceX96 = *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+	outstruct.Tick = *abi.ConvertType(out[1], new(*big.Int)).(**big.Int)
+	outstruct.ObservationIndex = *abi.ConvertType(out[2], new(uint16)).(*uint16)
+	outstruct.ObservationCardinality = *abi.ConvertType(out[3], new(uint16)).(*uint16)
+	outstruct.ObservationCardinalityNext = *abi.ConvertType(out[4], new(uint16)).(*uint16)
+	outstruct.FeeProtocol = *abi.ConvertType(out[5], new(uint8)).(*uint8)
+	outstruct.Unlocked = *abi.ConvertType(out[6], new(bool)).(*bool)
+
+	return *outstruct, err
+
+}
+
+// Slot0 is a free data retrieval call binding the contract method 0x3850c7bd.
+//
+// Solidity: function slot0() view returns(uint160 sqrtPriceX96, int24 tick, uint16 observationIndex, uint16 observationCardinality, uint16 observationCardinalityNext, uint8 feeProtocol, bool unlocked)
+func (_UniswapV3Pool *UniswapV3PoolSession) Slot0() (struct {
+	SqrtPriceX96               *big.Int
+	Tick                       *big.Int
+	ObservationIndex           uint16
+	ObservationCardinality     uint16
+	ObservationCardinalityNext uint16
+	FeeProtocol                uint8
+	Unlocked                   bool
+}, error) {
+	return _UniswapV3Pool.Contract.Slot0(&_UniswapV3Pool.CallOpts)
+}
+
+// Slot0 is a free data retrieval call binding the contract method 0x3850c7bd.
+//
+// Solidity: function slot0() view returns(uint160 sqrtPriceX96, int24 tick, uint16 observationIndex, uint16 observationCardinality, uint16 observationCardinalityNext, uint8 feeProtocol, bool unlocked)
+func (_UniswapV3Pool *UniswapV3PoolCallerSession) Slot0() (struct {
+	SqrtPriceX96               *big.Int
+	Tick                       *big.Int
+	ObservationIndex           uint16
+	ObservationCardinality     uint16
+	ObservationCardinalityNext uint16
+	FeeProtocol                uint8
+	Unlocked                   bool
+}, error) {
+	return _UniswapV3Pool.Contract.Slot0(&_UniswapV3Pool.CallOpts)
+}
+
+// TickSpacing is a free data retrieval call binding the contract method 0xd0c93a7c.
+//
+// Solidity: function tickSpacing() view returns(int24)
+func (_UniswapV3Pool *UniswapV3PoolCaller) TickSpacing(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _UniswapV3Pool.contract.Call(opts, &out, "tickSpacing")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// TickSpacing is a free data retrieval call binding the contract method 0xd0c93a7c.
+//
+// Solidity: function tickSpacing() view returns(int24)
+func (_UniswapV3Pool *UniswapV3PoolSession) TickSpacing() (*big.Int, error) {
+	return _UniswapV3Pool.Contract.TickSpacing(&_UniswapV3Pool.CallOpts)
+}
+
+// TickSpacing is a free data retrieval call binding the contract method 0xd0c93a7c.
+//
+// Solidity: function tickSpacing() view returns(int24)
+func (_UniswapV3Pool *UniswapV3PoolCallerSession) TickSpacing() (*big.Int, error) {
+	return _UniswapV3Pool.Contract.TickSpacing(&_UniswapV3Pool.CallOpts)
+}
+
+// Token0 is a free data retrieval call binding the contract method 0x0dfe1681.
+//
+// Solidity: function token0() view returns(address)
+func (_UniswapV3Pool *UniswapV3PoolCaller) Token0(opts *bind.CallOpts) (common.Address, error) {
+	var out []interface{}
+	err := _UniswapV3Pool.contract.Call(opts, &out, "token0")
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// Token0 is a free data retrieval call binding the contract method 0x0dfe1681.
+//
+// Solidity: function token0() view returns(address)
+func (_UniswapV3Pool *UniswapV3PoolSession) Token0() (common.Address, error) {
+	return _UniswapV3Pool.Contract.Token0(&_UniswapV3Pool.CallOpts)
+}
+
+// Token0 is a free data retrieval call binding the contract method 0x0dfe1681.
+//
+// Solidity: function token0() view returns(address)
+func (_UniswapV3Pool *UniswapV3PoolCallerSession) Token0() (common.Address, error) {
+	return _UniswapV3Pool.Contract.Token0(&_UniswapV3Pool.CallOpts)
+}
+
+// Token1 is a free data retrieval call binding the contract method 0xd21220a7.
+//
+// Solidity: function token1() view returns(address)
+func (_UniswapV3Pool *UniswapV3PoolCaller) Token1(opts *bind.CallOpts) (common.Address, error) {
+	var out []interface{}
+	err := _UniswapV3Pool.contract.Call(opts, &out, "token1")
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// Token1 is a free data retrieval call binding the contract method 0xd21220a7.
+//
+// Solidity: function token1() view returns(address)
+func (_UniswapV3Pool *UniswapV3PoolSession) Token1() (common.Address, error) {
+	return _UniswapV3Pool.Contract.Token1(&_UniswapV3Pool.CallOpts)
+}
+
+// Token1 is a free data retrieval call binding the contract method 0xd21220a7.
+//
+// Solidity: function token1() view returns(address)
+func (_UniswapV3Pool *UniswapV3PoolCallerSession) Token1() (common.Address, error) {
+	return _UniswapV3Pool.Contract.Token1(&_UniswapV3Pool.CallOpts)
+}
+
+// UniswapV3PoolSwapIterator is returned from FilterSwap and is used to iterate over the raw logs and unpacked data for Swap events raised by the UniswapV3Pool contract.
+type UniswapV3PoolSwapIterator struct {
+	Event *UniswapV3PoolSwap // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *UniswapV3PoolSwapIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(UniswapV3PoolSwap)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(UniswapV3PoolSwap)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *UniswapV3PoolSwapIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *UniswapV3PoolSwapIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// UniswapV3PoolSwap represents a Swap event raised by the UniswapV3Pool contract.
+type UniswapV3PoolSwap struct {
+	Sender       common.Address
+	Recipient    common.Address
+	Amount0      *big.Int
+	Amount1      *big.Int
+	SqrtPriceX96 *big.Int
+	Liquidity    *big.Int
+	Tick         *big.Int
+	Raw          types.Log // Blockchain specific contextual infos
+}
+
+// FilterSwap is a free log retrieval operation binding the contract event 0xc42079f94a6350d7e6235f29174924f928cc2ac818eb64fed8004e115fbcca67.
+//
+// Solidity: event Swap(address indexed sender, address indexed recipient, int256 amount0, int256 amount1, uint160 sqrtPriceX96, uint128 liquidity, int24 tick)
+func (_UniswapV3Pool *UniswapV3PoolFilterer) FilterSwap(opts *bind.FilterOpts, sender []common.Address, recipient []common.Address) (*UniswapV3PoolSwapIterator, error) {
+
+	var senderRule []interface{}
+	for _, senderItem := range sender {
+		senderRule = append(senderRule, senderItem)
+	}
+	var recipientRule []interface{}
+	for _, recipientItem := range recipient {
+		recipientRule = append(recipientRule, recipientItem)
+	}
+
+	logs, sub, err := _UniswapV3Pool.contract.FilterLogs(opts, "Swap", senderRule, recipientRule)
+	if err != nil {
+		return nil, err
+	}
+	return &UniswapV3PoolSwapIterator{contract: _UniswapV3Pool.contract, event: "Swap", logs: logs, sub: sub}, nil
+}
+
+// WatchSwap is a free log subscription operation binding the contract event 0xc42079f94a6350d7e6235f29174924f928cc2ac818eb64fed8004e115fbcca67.
+//
+// Solidity: event Swap(address indexed sender, address indexed recipient, int256 amount0, int256 amount1, uint160 sqrtPriceX96, uint128 liquidity, int24 tick)
+func (_UniswapV3Pool *UniswapV3PoolFilterer) WatchSwap(opts *bind.WatchOpts, sink chan<- *UniswapV3PoolSwap, sender []common.Address, recipient []common.Address) (event.Subscription, error) {
+
+	var senderRule []interface{}
+	for _, senderItem := range sender {
+		senderRule = append(senderRule, senderItem)
+	}
+	var recipientRule []interface{}
+	for _, recipientItem := range recipient {
+		recipientRule = append(recipientRule, recipientItem)
+	}
+
+	logs, sub, err := _UniswapV3Pool.contract.WatchLogs(opts, "Swap", senderRule, recipientRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(UniswapV3PoolSwap)
+				if err := _UniswapV3Pool.contract.UnpackLog(event, "Swap", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseSwap is a log parse operation binding the contract event 0xc42079f94a6350d7e6235f29174924f928cc2ac818eb64fed8004e115fbcca67.
+//
+// Solidity: event Swap(address indexed sender, address indexed recipient, int256 amount0, int256 amount1, uint160 sqrtPriceX96, uint128 liquidity, int24 tick)
+func (_UniswapV3Pool *UniswapV3PoolFilterer) ParseSwap(log types.Log) (*UniswapV3PoolSwap, error) {
+	event := new(UniswapV3PoolSwap)
+	if err := _UniswapV3Pool.contract.UnpackLog(event, "Swap", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
@@ -0,0 +1,132 @@
+package liqbot
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/go-kit/kit/metrics"
+)
+
+// instrumentedBackend counts failed calls of Backend by JSON-RPC method, missing receipts and transactions are not failures
+type instrumentedBackend struct {
+	Backend
+	errors metrics.Counter
+	// onErrors is called with the number of consecutive failed calls and the last error
+	onErrors func(consecutive int, method string, err error)
+
+	consecutive int32
+}
+
+func (b *instrumentedBackend) record(method string, err error) {
+	if err == nil || errors.Is(err, ethereum.NotFound) {
+		atomic.StoreInt32(&b.consecutive, 0)
+		return
+	}
+
+	b.errors.With("method", method).Add(1)
+	consecutive := atomic.AddInt32(&b.consecutive, 1)
+	if b.onErrors != nil {
+		b.onErrors(int(consecutive), method, err)
+	}
+}
+
+func (b *instrumentedBackend) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	code, err := b.Backend.CodeAt(ctx, contract, blockNumber)
+	b.record("eth_getCode", err)
+	return code, err
+}
+
+func (b *instrumentedBackend) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	result, err := b.Backend.CallContract(ctx, call, blockNumber)
+	b.record("eth_call", err)
+	return result, err
+}
+
+func (b *instrumentedBackend) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	header, err := b.Backend.HeaderByNumber(ctx, number)
+	b.record("eth_getBlockByNumber", err)
+	return header, err
+}
+
+func (b *instrumentedBackend) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	code, err := b.Backend.PendingCodeAt(ctx, account)
+	b.record("eth_getCode", err)
+	return code, err
+}
+
+func (b *instrumentedBackend) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	nonce, err := b.Backend.PendingNonceAt(ctx, account)
+	b.record("eth_getTransactionCount", err)
+	return nonce, err
+}
+
+func (b *instrumentedBackend) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	price, err := b.Backend.SuggestGasPrice(ctx)
+	b.record("eth_gasPrice", err)
+	return price, err
+}
+
+func (b *instrumentedBackend) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	tip, err := b.Backend.SuggestGasTipCap(ctx)
+	b.record("eth_maxPriorityFeePerGas", err)
+	return tip, err
+}
+
+func (b *instrumentedBackend) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	gas, err := b.Backend.EstimateGas(ctx, call)
+	b.record("eth_estimateGas", err)
+	return gas, err
+}
+
+func (b *instrumentedBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	err := b.Backend.SendTransaction(ctx, tx)
+	b.record("eth_sendRawTransaction", err)
+	return err
+}
+
+func (b *instrumentedBackend) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	logs, err := b.Backend.FilterLogs(ctx, query)
+	b.record("eth_getLogs", err)
+	return logs, err
+}
+
+func (b *instrumentedBackend) SubscribeFilterLogs(ctx context.Context, query ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	sub, err := b.Backend.SubscribeFilterLogs(ctx, query, ch)
+	b.record("eth_subscribe", err)
+	return sub, err
+}
+
+func (b *instrumentedBackend) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	receipt, err := b.Backend.TransactionReceipt(ctx, txHash)
+	b.record("eth_getTransactionReceipt", err)
+	return receipt, err
+}
+
+func (b *instrumentedBackend) BlockNumber(ctx context.Context) (uint64, error) {
+	number, err := b.Backend.BlockNumber(ctx)
+	b.record("eth_blockNumber", err)
+	return number, err
+}
+
+func (b *instrumentedBackend) ChainID(ctx context.Context) (*big.Int, error) {
+	chainID, err := b.Backend.ChainID(ctx)
+	b.record("eth_chainId", err)
+	return chainID, err
+}
+
+func (b *instrumentedBackend) TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error) {
+	tx, pending, err := b.Backend.TransactionByHash(ctx, hash)
+	b.record("eth_getTransactionByHash", err)
+	return tx, pending, err
+}
+
+func (b *instrumentedBackend) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	balance, err := b.Backend.BalanceAt(ctx, account, blockNumber)
+	b.record("eth_getBalance", err)
+	return balance, err
+}
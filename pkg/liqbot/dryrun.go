@@ -0,0 +1,84 @@
+package liqbot
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// dryRunRecord is a liquidation the bot would have sent, written as one JSON line
+type dryRunRecord struct {
+	Time              time.Time `json:"time"`
+	Block             uint64    `json:"block"`
+	Borrower          string    `json:"borrower"`
+	RepayMarket       string    `json:"repay_market"`
+	RepayCToken       string    `json:"repay_ctoken"`
+	CollateralMarket  string    `json:"collateral_market"`
+	CollateralCToken  string    `json:"collateral_ctoken"`
+	Shortfall         string    `json:"shortfall"`
+	RepayAmount       string    `json:"repay_amount"`
+	SeizeTokens       string    `json:"seize_tokens"`
+	RepayUSD          float64   `json:"repay_usd"`
+	SeizeUSD          float64   `json:"seize_usd"`
+	GasUSD            float64   `json:"gas_usd"`
+	ExpectedProfitUSD float64   `json:"expected_profit_usd"`
+	GasPrice          string    `json:"gas_price"`
+	GasLimit          uint64    `json:"gas_limit"`
+	Nonce             uint64    `json:"nonce"`
+	TxHash            string    `json:"tx_hash"`
+	RawTx             string    `json:"raw_tx"`
+	SimulationError   string    `json:"simulation_error,omitempty"`
+}
+
+// recordDryRun appends signed but unsent liquidation to the JSON lines file at path
+func recordDryRun(path string, op *opportunity, tx *types.Transaction) error {
+	rawTx, err := tx.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	record := dryRunRecord{
+		Time:              time.Now().UTC(),
+		Block:             op.block,
+		Borrower:          op.borrower.Hex(),
+		RepayMarket:       op.repay.Name,
+		RepayCToken:       op.repay.Address.Hex(),
+		CollateralMarket:  op.collateral.Name,
+		CollateralCToken:  op.collateral.Address.Hex(),
+		Shortfall:         op.shortfall.String(),
+		RepayAmount:       op.repayAmount.String(),
+		SeizeTokens:       op.seizeTokens.String(),
+		RepayUSD:          op.repayUSD,
+		SeizeUSD:          op.seizeUSD,
+		GasUSD:            op.gasUSD,
+		ExpectedProfitUSD: op.profitUSD,
+		GasPrice:          op.gasPrice.String(),
+		GasLimit:          op.gasLimit,
+		Nonce:             tx.Nonce(),
+		TxHash:            tx.Hash().Hex(),
+		RawTx:             hexutil.Encode(rawTx),
+	}
+	if op.simulationErr != nil {
+		record.SimulationError = op.simulationErr.Error()
+	}
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
@@ -0,0 +1,479 @@
+package liqbot
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/go-kit/kit/log/level"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/config"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/contracts"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/liquidity"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/multicall"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/subgraph"
+)
+
+// opportunityQueue is a heap of opportunities, the most profitable first
+type opportunityQueue []*opportunity
+
+func (q opportunityQueue) Len() int { return len(q) }
+
+func (q opportunityQueue) Less(i, j int) bool { return q[i].profitUSD > q[j].profitUSD }
+
+func (q opportunityQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *opportunityQueue) Push(x interface{}) {
+	*q = append(*q, x.(*opportunity))
+}
+
+func (q *opportunityQueue) Pop() interface{} {
+	old := *q
+	op := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return op
+}
+
+// evaluation is the result of evaluating a borrower, shortfall is nil until evaluated
+// and op is nil when the borrower cannot be liquidated profitably
+type evaluation struct {
+	borrower common.Address
+	// fingerprint describes the subgraph balances of the borrower, see fingerprint
+	fingerprint string
+	position    *position
+	// account is the position with borrows projected to the scan block
+	account *liquidity.Account
+	// projected is the local shortfall of account, nil when it could not be computed
+	projected *big.Int
+	// accrued is set when the account is in shortfall only with the interest accrued since the last accrual
+	accrued bool
+	// health is the local health of account, 0 when it could not be computed
+	health    float64
+	shortfall *big.Int
+	op        *opportunity
+	err       error
+}
+
+// evaluateAll evaluates the liquidable accounts and queues the profitable liquidations, accounts are
+// every borrower on a full scan and the watchlist otherwise. The accounts close to liquidation are watched next.
+func (o *liqbot) evaluateAll(ctx context.Context, src *sources, cfg config.Config, params *marketParams, accounts []subgraph.Account, full bool) *opportunityQueue {
+	if full {
+		o.positions.keep(accounts)
+	}
+
+	var candidates []subgraph.Account
+	watched := map[common.Address]subgraph.Account{}
+	for _, a := range accounts {
+		candidate, pending := o.isCandidate(src, a)
+		if candidate {
+			candidates = append(candidates, a)
+		}
+		// checked again once its liquidation is mined
+		if pending {
+			watched[common.HexToAddress(a.Id)] = a
+		}
+	}
+
+	queue := &opportunityQueue{}
+	evaluations := o.evaluate(ctx, src, cfg, params, candidates)
+	o.recordEvaluations(src.store, candidates, evaluations, params.block, full)
+	for i, e := range evaluations {
+		if watches(cfg, e) {
+			watched[e.borrower] = candidates[i]
+		}
+		if e.err != nil {
+			level.Error(o.logger).Log("msg", "❌ Error evaluating account", "account", candidates[i].Id, "err", e.err)
+			continue
+		}
+		if e.op == nil {
+			continue
+		}
+		o.metrics.Candidates.Add(1)
+		heap.Push(queue, e.op)
+	}
+
+	o.watch.set(watched, params.block, full, time.Now())
+	o.metrics.WatchlistAccounts.Set(float64(len(watched)))
+	return queue
+}
+
+// isCandidate returns true when the subgraph reports account under water and it has no pending liquidation,
+// pending is true when it has one
+func (o *liqbot) isCandidate(src *sources, a subgraph.Account) (candidate, pending bool) {
+	liquidable, err := a.IsLiquidable()
+	if err != nil {
+		level.Warn(o.logger).Log("msg", "invalid subgraph account", "account", a.Id, "err", err)
+		return false, false
+	}
+	if !liquidable {
+		return false, false
+	}
+	level.Debug(o.logger).Log("msg", "evaluating account", "account", a.Id, "health", a.Health)
+
+	if src.store == nil {
+		return true, false
+	}
+	attempt, err := src.store.PendingAttempt(common.HexToAddress(a.Id))
+	if err != nil {
+		level.Error(o.logger).Log("msg", "❌ Error reading pending attempt", "account", a.Id, "err", err)
+		return false, false
+	}
+	if attempt != nil {
+		level.Info(o.logger).Log("msg", "liquidation pending", "account", a.Id, "tx", attempt.TxHash.Hex())
+		return false, true
+	}
+	return true, false
+}
+
+// evaluate finds the accounts under water, sizes their liquidations and estimates the profit.
+// Accounts are evaluated locally from cached positions, read again when the subgraph balances change or
+// after cfg.Scan().PositionMaxAge blocks, and only the ones under water locally are verified on-chain.
+// Reads are batched in multicalls at the params block, sent cfg.Scan().Concurrency at once.
+func (o *liqbot) evaluate(ctx context.Context, src *sources, cfg config.Config, params *marketParams, accounts []subgraph.Account) []*evaluation {
+	borrowers := make([]common.Address, len(accounts))
+	for i, a := range accounts {
+		borrowers[i] = common.HexToAddress(a.Id)
+	}
+
+	opts := params.callOpts(ctx)
+	batch := newBatch(src, cfg)
+
+	comptrollerABI, err := contracts.ComptrollerCoreMetaData.GetAbi()
+	if err != nil {
+		return failedEvaluations(borrowers, err)
+	}
+	comptroller := cfg.ContractComptrollerAddress()
+
+	// positions
+	evaluations := make([]*evaluation, len(borrowers))
+	var stale []*evaluation
+	for i, borrower := range borrowers {
+		e := &evaluation{borrower: borrower, fingerprint: fingerprint(accounts[i]), position: o.positions.get(borrower)}
+		if !e.position.fresh(e.fingerprint, params.block, cfg.Scan().PositionMaxAge, cfg.Markets()) {
+			stale = append(stale, e)
+		}
+		evaluations[i] = e
+	}
+	o.readPositions(opts, batch, cfg, params, stale)
+
+	// local liquidity, with borrows projected to the scan block liquidations are simulated at
+	markets := params.markets()
+	var suspects []*evaluation
+	approaching := 0
+	for _, e := range evaluations {
+		if e.err != nil {
+			continue
+		}
+		e.account = liquidity.Project(&e.position.account, params.accruals, params.block)
+		result, err := liquidity.AccountLiquidity(e.account, markets)
+		if err != nil {
+			// entered markets not configured or not priced are left to the comptroller
+			level.Debug(o.logger).Log("msg", "account not evaluated locally", "account", e.borrower.Hex(), "err", err)
+			suspects = append(suspects, e)
+			continue
+		}
+		e.projected = result.Shortfall
+		e.health = result.Health()
+		if result.Shortfall.Sign() > 0 {
+			suspects = append(suspects, e)
+			continue
+		}
+		e.shortfall = result.Shortfall
+
+		if lookahead := cfg.Scan().LookaheadBlocks; lookahead > 0 {
+			block, crossing, err := liquidity.ShortfallBlock(&e.position.account, markets, params.accruals, params.block, params.block+lookahead)
+			if err == nil && crossing {
+				// read again every scan until it crosses, so the crossing is found from exact balances
+				e.position.crossing = block
+				approaching++
+				level.Info(o.logger).Log("msg", "⏳ account approaching shortfall", "account", e.borrower.Hex(),
+					"shortfall_block", block, "blocks", block-params.block)
+			}
+		}
+	}
+	o.metrics.AccountsApproaching.Set(float64(approaching))
+
+	// verification
+	calls := make([]multicall.Call, len(suspects))
+	for i, e := range suspects {
+		calls[i] = multicall.Call{Target: comptroller, ABI: comptrollerABI, Method: "getAccountLiquidity", Args: []interface{}{e.borrower}}
+	}
+
+	var underWater, outdated []*evaluation
+	for i, result := range batch.Call(opts, calls) {
+		e := suspects[i]
+		if result.Err != nil {
+			e.err = fmt.Errorf("getAccountLiquidity: %v", result.Err)
+			continue
+		}
+		if errCode := result.Values[0].(*big.Int); errCode.Sign() != 0 {
+			e.err = fmt.Errorf("getAccountLiquidity returned error code %s", errCode)
+			continue
+		}
+		e.shortfall = result.Values[2].(*big.Int)
+		if e.shortfall.Sign() == 0 {
+			if e.projected == nil {
+				continue
+			}
+			// the comptroller sees stored borrows, liquidateBorrow accrues interest before checking them
+			e.accrued = true
+		}
+		underWater = append(underWater, e)
+		if e.position.block != params.block {
+			outdated = append(outdated, e)
+		}
+	}
+
+	// sizing, from positions read at the params block
+	o.readPositions(opts, batch, cfg, params, outdated)
+
+	var priced []config.Market
+	for _, market := range cfg.Markets() {
+		if price := params.prices[market.Address]; price != nil && price.Sign() != 0 {
+			priced = append(priced, market)
+		}
+	}
+
+	var sized []*evaluation
+	for _, e := range underWater {
+		if e.err != nil {
+			continue
+		}
+		e.account = liquidity.Project(&e.position.account, params.accruals, params.block)
+		if e.accrued {
+			result, err := liquidity.AccountLiquidity(e.account, markets)
+			if err != nil || result.Shortfall.Sign() == 0 {
+				level.Debug(o.logger).Log("msg", "local shortfall not confirmed, account is healthy", "account", e.borrower.Hex())
+				continue
+			}
+			e.shortfall = result.Shortfall
+			level.Info(o.logger).Log("msg", "account in shortfall with accrued interest", "account", e.borrower.Hex(), "shortfall", e.shortfall)
+		}
+		e.op, e.err = o.size(cfg, params, e, priced)
+		if e.op != nil {
+			sized = append(sized, e)
+		}
+	}
+
+	// profitability
+	calls = calls[:0]
+	for _, e := range sized {
+		calls = append(calls, multicall.Call{Target: comptroller, ABI: comptrollerABI, Method: "liquidateCalculateSeizeTokens",
+			Args: []interface{}{e.op.repay.Address, e.op.collateral.Address, e.op.repayAmount}})
+	}
+
+	for i, result := range batch.Call(opts, calls) {
+		e := sized[i]
+		if result.Err != nil {
+			e.op, e.err = nil, fmt.Errorf("liquidateCalculateSeizeTokens: %v", result.Err)
+			continue
+		}
+		if errCode := result.Values[0].(*big.Int); errCode.Sign() != 0 {
+			e.op, e.err = nil, fmt.Errorf("liquidateCalculateSeizeTokens returned error code %s", errCode)
+			continue
+		}
+
+		op := e.op
+		op.seizeTokens = result.Values[1].(*big.Int)
+		op.seizeUSD = toUSD(mulExp(mulExp(op.seizeTokens, op.collateralRate), params.prices[op.collateral.Address]))
+		op.updateProfit()
+
+		if op.profitUSD < cfg.Profit().MinUSD {
+			level.Info(o.logger).Log("msg", "liquidation not profitable", "account", e.borrower.Hex(),
+				"market", op.repay.Name, "collateral", op.collateral.Name, "profit_usd", op.profitUSD)
+			e.op = nil
+		}
+	}
+
+	return evaluations
+}
+
+// newBatch creates the multicall caller scans read with
+func newBatch(src *sources, cfg config.Config) *multicall.Caller {
+	return multicall.New(src.client,
+		multicall.WithAddress(cfg.ContractMulticallAddress()),
+		multicall.WithChunkSize(cfg.Scan().BatchSize),
+		multicall.WithConcurrency(cfg.Scan().Concurrency),
+	)
+}
+
+// readPositions reads getAssetsIn and the getAccountSnapshot of every configured market for evaluations
+// at the params block and caches them
+func (o *liqbot) readPositions(opts *bind.CallOpts, batch *multicall.Caller, cfg config.Config, params *marketParams, evaluations []*evaluation) {
+	if len(evaluations) == 0 {
+		return
+	}
+
+	comptrollerABI, err := contracts.ComptrollerCoreMetaData.GetAbi()
+	if err != nil {
+		failPositions(evaluations, err)
+		return
+	}
+	ctokenABI, err := contracts.CTokenMetaData.GetAbi()
+	if err != nil {
+		failPositions(evaluations, err)
+		return
+	}
+
+	markets := cfg.Markets()
+	var calls []multicall.Call
+	for _, e := range evaluations {
+		calls = append(calls, multicall.Call{Target: cfg.ContractComptrollerAddress(), ABI: comptrollerABI, Method: "getAssetsIn", Args: []interface{}{e.borrower}})
+		for _, market := range markets {
+			calls = append(calls, multicall.Call{Target: market.Address, ABI: ctokenABI, Method: "getAccountSnapshot", Args: []interface{}{e.borrower}})
+		}
+	}
+
+	results := batch.Call(opts, calls)
+	for i, e := range evaluations {
+		row := results[i*(len(markets)+1) : (i+1)*(len(markets)+1)]
+		account, err := positionAccount(markets, params.accruals, row)
+		if err != nil {
+			e.err = err
+			continue
+		}
+		e.position = &position{account: *account, block: params.block, fingerprint: e.fingerprint}
+		o.positions.put(e.borrower, e.position)
+	}
+}
+
+// positionAccount decodes the getAssetsIn and getAccountSnapshot results of a borrower read at the block of accruals
+func positionAccount(markets []config.Market, accruals map[common.Address]*liquidity.Accrual, row []multicall.Result) (*liquidity.Account, error) {
+	if row[0].Err != nil {
+		return nil, fmt.Errorf("getAssetsIn: %v", row[0].Err)
+	}
+	account := &liquidity.Account{
+		AssetsIn:  row[0].Values[0].([]common.Address),
+		Snapshots: map[common.Address]liquidity.Snapshot{},
+	}
+
+	for i, market := range markets {
+		snapshot := row[i+1]
+		if snapshot.Err != nil {
+			return nil, fmt.Errorf("%s getAccountSnapshot: %v", market.Name, snapshot.Err)
+		}
+		if errCode := snapshot.Values[0].(*big.Int); errCode.Sign() != 0 {
+			return nil, fmt.Errorf("%s getAccountSnapshot returned error code %s", market.Name, errCode)
+		}
+		position := liquidity.Snapshot{
+			CTokenBalance: snapshot.Values[1].(*big.Int),
+			BorrowBalance: snapshot.Values[2].(*big.Int),
+			ExchangeRate:  snapshot.Values[3].(*big.Int),
+		}
+		// the snapshot and the accrual are read at the same block, the stored borrow is at the accrual index
+		if accrual := accruals[market.Address]; accrual != nil {
+			position.BorrowIndex = accrual.BorrowIndex
+		}
+		account.Snapshots[market.Address] = position
+	}
+	return account, nil
+}
+
+// size picks the largest collateral to seize and the largest borrow to repay from the projected position of e,
+// within the inventory of params. It returns nil when e has nothing to liquidate in the priced markets.
+func (o *liqbot) size(cfg config.Config, params *marketParams, e *evaluation, markets []config.Market) (*opportunity, error) {
+	entered := map[common.Address]bool{}
+	for _, asset := range e.account.AssetsIn {
+		entered[asset] = true
+	}
+
+	var (
+		collateral                            *config.Market
+		collateralExchangeRate, collateralUSD *big.Int
+	)
+	for i := range markets {
+		market := &markets[i]
+		snapshot, ok := e.account.Snapshots[market.Address]
+		if !ok {
+			return nil, fmt.Errorf("no %s snapshot", market.Name)
+		}
+		if entered[market.Address] {
+			value := mulExp(mulExp(snapshot.CTokenBalance, snapshot.ExchangeRate), params.prices[market.Address])
+			if collateralUSD == nil || value.Cmp(collateralUSD) > 0 {
+				collateral, collateralExchangeRate, collateralUSD = market, snapshot.ExchangeRate, value
+			}
+		}
+	}
+	if collateral == nil || collateralUSD.Sign() == 0 {
+		level.Debug(o.logger).Log("msg", "no borrow or collateral in configured markets", "account", e.borrower.Hex())
+		return nil, nil
+	}
+
+	// the seized value, repay value times incentive, must fit in the collateral
+	maxRepayUSD := divExp(collateralUSD, params.incentive)
+	maxRepayUSD.Mul(maxRepayUSD, collateralSafetyNumerator).Div(maxRepayUSD, collateralSafetyDenominator)
+
+	var (
+		wanted, repay             *config.Market
+		wantedAmount, repayAmount *big.Int
+		borrowUSD, repayUSD       *big.Int
+	)
+	for i := range markets {
+		market := &markets[i]
+		// cETH is repaid with value, the CErc20 binding cannot liquidate it
+		if market.Address == cfg.Profit().ETHMarket {
+			continue
+		}
+		snapshot, price := e.account.Snapshots[market.Address], params.prices[market.Address]
+
+		// comptroller allows repaying up to close factor of the borrow
+		amount := mulExp(snapshot.BorrowBalance, params.closeFactor)
+		if maxRepay := divExp(maxRepayUSD, price); maxRepay.Cmp(amount) < 0 {
+			amount = maxRepay
+		}
+		if value := mulExp(snapshot.BorrowBalance, price); borrowUSD == nil || value.Cmp(borrowUSD) > 0 {
+			wanted, wantedAmount, borrowUSD = market, amount, value
+		}
+		capped := params.inventory.capped(market.Address, amount)
+		if value := mulExp(capped, price); repayUSD == nil || value.Cmp(repayUSD) > 0 {
+			repay, repayAmount, repayUSD = market, capped, value
+		}
+	}
+	if wanted == nil || wantedAmount.Sign() == 0 {
+		level.Debug(o.logger).Log("msg", "no borrow or collateral in configured markets", "account", e.borrower.Hex())
+		return nil, nil
+	}
+	if short := new(big.Int).Sub(wantedAmount, params.inventory.capped(wanted.Address, wantedAmount)); short.Sign() > 0 {
+		params.inventory.short(wanted.Address, short)
+		level.Info(o.logger).Log("msg", "repay capped to inventory", "account", e.borrower.Hex(), "market", wanted.Name,
+			"needed", wantedAmount, "short", short)
+	}
+	if repayAmount.Sign() == 0 {
+		return nil, nil
+	}
+
+	op := &opportunity{
+		borrower:       e.borrower,
+		repay:          *repay,
+		collateral:     *collateral,
+		shortfall:      e.shortfall,
+		repayAmount:    repayAmount,
+		repayUSD:       toUSD(mulExp(repayAmount, params.prices[repay.Address])),
+		collateralRate: collateralExchangeRate,
+		gasPrice:       params.gasPrice,
+		gasLimit:       cfg.Gas().Limit,
+		ethPrice:       params.prices[cfg.Profit().ETHMarket],
+		block:          params.block,
+	}
+	if op.gasLimit == 0 {
+		op.gasLimit = defaultLiquidationGasLimit
+	}
+	return op, nil
+}
+
+func failPositions(evaluations []*evaluation, err error) {
+	for _, e := range evaluations {
+		e.err = err
+	}
+}
+
+func failedEvaluations(borrowers []common.Address, err error) []*evaluation {
+	evaluations := make([]*evaluation, len(borrowers))
+	for i, borrower := range borrowers {
+		evaluations[i] = &evaluation{borrower: borrower, err: err}
+	}
+	return evaluations
+}
@@ -0,0 +1,220 @@
+package liqbot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/params"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/notify"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/rpcpool"
+)
+
+// health holds scan results the health checks report on
+type health struct {
+	mu sync.Mutex
+	// started is the liveness grace period start, before the first scan
+	started  time.Time
+	lastScan time.Time
+	// subgraphErr is the error of the last account fetch
+	subgraphErr error
+	// subgraphLag is nil when the account source does not report its block
+	subgraphLag *uint64
+}
+
+func (h *health) scanned() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.lastScan = time.Now()
+}
+
+func (h *health) fetched(err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.subgraphErr = err
+}
+
+func (h *health) lagged(lag uint64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.subgraphLag = &lag
+}
+
+// check represents result of a health check
+type check struct {
+	OK     bool   `json:"ok"`
+	Detail string `json:"detail"`
+}
+
+// notConnected is the detail of chain checks while the bot reconnects
+const notConnected = "not connected, reconnecting"
+
+// livenessChecks fail when restarting the bot may help: the node is unreachable or scans stopped
+var livenessChecks = []string{"rpc", "scan"}
+
+// checkHealth runs every health check, the scan check fails before the first scan unless alive is set,
+// checks needing the chain fail while src is nil
+func (o *liqbot) checkHealth(ctx context.Context, src *sources, alive bool) map[string]check {
+	cfg := o.getConfig()
+	limits := cfg.Health()
+	checks := map[string]check{}
+
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	if src == nil {
+		checks["rpc"] = check{Detail: notConnected}
+	} else if block, err := src.client.BlockNumber(ctx); err != nil {
+		checks["rpc"] = check{Detail: err.Error()}
+	} else {
+		checks["rpc"] = check{OK: true, Detail: fmt.Sprintf("block %d", block)}
+	}
+	if src != nil && src.pool != nil {
+		rpc := checks["rpc"]
+		rpc.Detail += ", " + poolStatus(src.pool)
+		checks["rpc"] = rpc
+	}
+
+	o.health.mu.Lock()
+	started, lastScan := o.health.started, o.health.lastScan
+	subgraphErr, subgraphLag := o.health.subgraphErr, o.health.subgraphLag
+	o.health.mu.Unlock()
+
+	switch {
+	case lastScan.IsZero() && !alive:
+		checks["scan"] = check{Detail: "no successful scan yet"}
+	case lastScan.IsZero():
+		age := time.Since(started)
+		checks["scan"] = check{OK: limits.MaxScanAge == 0 || age <= limits.MaxScanAge,
+			Detail: fmt.Sprintf("no successful scan in %s since start", age.Truncate(time.Second))}
+	default:
+		age := time.Since(lastScan)
+		checks["scan"] = check{OK: limits.MaxScanAge == 0 || age <= limits.MaxScanAge,
+			Detail: fmt.Sprintf("last successful scan at %s, %s ago", lastScan.UTC().Format(time.RFC3339), age.Truncate(time.Second))}
+	}
+
+	switch {
+	case subgraphErr != nil:
+		checks["subgraph"] = check{Detail: subgraphErr.Error()}
+	case subgraphLag == nil:
+		checks["subgraph"] = check{OK: true, Detail: "lag not reported"}
+	default:
+		checks["subgraph"] = check{OK: limits.MaxSubgraphLag == 0 || *subgraphLag <= limits.MaxSubgraphLag,
+			Detail: fmt.Sprintf("%d blocks behind", *subgraphLag)}
+	}
+
+	if src == nil {
+		checks["balance"] = check{Detail: notConnected}
+		checks["pending"] = check{Detail: notConnected}
+		return checks
+	}
+
+	if balance, err := src.client.BalanceAt(ctx, cfg.AccountAddress(), nil); err != nil {
+		checks["balance"] = check{Detail: err.Error()}
+	} else {
+		checks["balance"] = check{OK: limits.MinBalance == nil || balance.Cmp(limits.MinBalance) >= 0,
+			Detail: fmt.Sprintf("%s ETH", weiToETH(balance).Text('f', 6))}
+	}
+
+	checks["pending"] = o.checkPending(src, limits.StuckAfter)
+
+	return checks
+}
+
+// checkPending fails when a liquidation has no outcome for longer than stuckAfter
+func (o *liqbot) checkPending(src *sources, stuckAfter time.Duration) check {
+	if src.store == nil {
+		return check{OK: true, Detail: "not tracked without store"}
+	}
+
+	attempts, err := src.store.PendingAttempts()
+	if err != nil {
+		return check{Detail: err.Error()}
+	}
+
+	stuck := 0
+	for _, attempt := range attempts {
+		if stuckAfter > 0 && time.Since(attempt.Time) > stuckAfter {
+			stuck++
+		}
+	}
+	return check{OK: stuck == 0, Detail: fmt.Sprintf("%d pending, %d stuck", len(attempts), stuck)}
+}
+
+// healthHandler reports every check and fails with 503 when one of required fails, all of them when required is nil
+func (o *liqbot) healthHandler(required []string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		checks := o.checkHealth(r.Context(), o.currentSources(), required != nil)
+
+		ok := true
+		for name, c := range checks {
+			if c.OK {
+				continue
+			}
+			if required == nil {
+				ok = false
+			}
+			for _, req := range required {
+				if req == name {
+					ok = false
+				}
+			}
+		}
+
+		status, code := "ok", http.StatusOK
+		if !ok {
+			status, code = "failing", http.StatusServiceUnavailable
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status": status,
+			"checks": checks,
+		})
+	}
+}
+
+// notifyRPCErrors notifies runs of failed RPC calls reaching the configured threshold
+func (o *liqbot) notifyRPCErrors(consecutive int, method string, err error) {
+	threshold := o.getConfig().Notify().RPCErrors
+	if threshold <= 0 || consecutive < threshold {
+		return
+	}
+
+	o.notifier.Notify(notify.Event{
+		Kind:     notify.KindRPCErrors,
+		Severity: notify.SeverityError,
+		Key:      notify.KindRPCErrors,
+		Text:     fmt.Sprintf("%d consecutive RPC calls failed, last %s: %v", consecutive, method, err),
+		Fields:   map[string]string{"method": method, "err": err.Error()},
+	})
+}
+
+// poolStatus describes RPC nodes, e.g. "2/3 nodes healthy (a.example.com 120ms block 100, ...)"
+func poolStatus(pool *rpcpool.Pool) string {
+	statuses := pool.Status()
+	healthy := 0
+	nodes := make([]string, len(statuses))
+	for i, status := range statuses {
+		if status.Healthy {
+			healthy++
+			nodes[i] = fmt.Sprintf("%s %s block %d", status.Name, status.Latency.Truncate(time.Millisecond), status.Head)
+		} else {
+			nodes[i] = fmt.Sprintf("%s unhealthy: %v", status.Name, status.Err)
+		}
+	}
+	return fmt.Sprintf("%d/%d nodes healthy (%s)", healthy, len(statuses), strings.Join(nodes, ", "))
+}
+
+// weiToETH converts wei amount to ETH
+func weiToETH(wei *big.Int) *big.Float {
+	return new(big.Float).Quo(new(big.Float).SetInt(wei), big.NewFloat(params.Ether))
+}
+
+const healthCheckTimeout = 5 * time.Second
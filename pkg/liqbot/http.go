@@ -0,0 +1,53 @@
+package liqbot
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// serveHTTP starts the metrics, health and PnL server on listen, Close it when the bot stops
+func (o *liqbot) serveHTTP(listen string) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	mux.Handle("/healthz", o.healthHandler(livenessChecks))
+	mux.Handle("/readyz", o.healthHandler(nil))
+	mux.Handle("/pnl", o.pnlHandler())
+
+	server := &http.Server{Addr: listen, Handler: mux}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			level.Error(o.logger).Log("msg", "❌ Error serving HTTP", "listen", listen, "err", err)
+		}
+	}()
+	level.Info(o.logger).Log("msg", "✅ HTTP SERVER STARTED", "listen", listen)
+
+	return server
+}
+
+// pnlHandler serves the realized PnL ledger of the store as JSON, the store is locked by the bot
+// so liqbot pnl reads it here while the bot runs
+func (o *liqbot) pnlHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		src := o.currentSources()
+		if src == nil {
+			http.Error(w, notConnected, http.StatusServiceUnavailable)
+			return
+		}
+		if src.store == nil {
+			http.Error(w, "no store configured", http.StatusNotFound)
+			return
+		}
+
+		ledger, err := src.store.Ledger()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ledger)
+	}
+}
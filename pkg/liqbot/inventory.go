@@ -0,0 +1,288 @@
+package liqbot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/go-kit/kit/log/level"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/config"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/contracts"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/multicall"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/notify"
+)
+
+// maxAllowance is approved to the cTokens, allowances are approved again only once spent
+var maxAllowance = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+
+// inventory holds the underlying the liquidator can repay with during a scan by cToken,
+// the liquidations sent draw from it
+type inventory struct {
+	// available is the balance the cToken is allowed to transfer, less the repay amounts of pending liquidations
+	available map[common.Address]*big.Int
+	// shortfalls are the repay amounts liquidations needed over the available inventory
+	shortfalls map[common.Address]*big.Int
+}
+
+// capped returns amount capped to the available inventory of market, amount when inv is nil
+func (inv *inventory) capped(market common.Address, amount *big.Int) *big.Int {
+	if inv == nil {
+		return amount
+	}
+	available := inv.available[market]
+	if available == nil {
+		return new(big.Int)
+	}
+	if available.Cmp(amount) < 0 {
+		return new(big.Int).Set(available)
+	}
+	return amount
+}
+
+// short records that a liquidation needed amount more of market than available
+func (inv *inventory) short(market common.Address, amount *big.Int) {
+	if inv == nil || amount.Sign() <= 0 {
+		return
+	}
+	if inv.shortfalls[market] == nil {
+		inv.shortfalls[market] = new(big.Int)
+	}
+	inv.shortfalls[market].Add(inv.shortfalls[market], amount)
+}
+
+// fit caps the repay amount of op to what earlier liquidations of the scan left, seized tokens shrink in
+// proportion. It returns false when nothing is left to repay with.
+func (inv *inventory) fit(op *opportunity) bool {
+	repayAmount := inv.capped(op.repay.Address, op.repayAmount)
+	if repayAmount.Cmp(op.repayAmount) == 0 {
+		return true
+	}
+	inv.short(op.repay.Address, new(big.Int).Sub(op.repayAmount, repayAmount))
+	if repayAmount.Sign() == 0 {
+		return false
+	}
+
+	op.seizeTokens = new(big.Int).Div(new(big.Int).Mul(op.seizeTokens, repayAmount), op.repayAmount)
+	scale := toUSD(divExp(repayAmount, op.repayAmount))
+	op.repayUSD *= scale
+	op.seizeUSD *= scale
+	op.repayAmount = repayAmount
+	op.updateProfit()
+	return true
+}
+
+// spend takes the repay amount of op sent from the inventory
+func (inv *inventory) spend(op *opportunity) {
+	if inv == nil {
+		return
+	}
+	if available := inv.available[op.repay.Address]; available != nil {
+		available.Sub(available, op.repayAmount)
+	}
+}
+
+// approvals tracks the underlyings of the markets and the approve transactions sent to them, by cToken
+type approvals struct {
+	mu          sync.Mutex
+	underlyings map[common.Address]common.Address
+	pending     map[common.Address]common.Hash
+}
+
+// underlying returns the underlying of market, read once
+func (a *approvals) underlying(opts *bind.CallOpts, src *sources, market common.Address) (common.Address, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if underlying, ok := a.underlyings[market]; ok {
+		return underlying, nil
+	}
+
+	ctoken, err := contracts.NewCTokenCaller(market, src.client)
+	if err != nil {
+		return common.Address{}, err
+	}
+	underlying, err := ctoken.Underlying(opts)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if a.underlyings == nil {
+		a.underlyings = map[common.Address]common.Address{}
+	}
+	a.underlyings[market] = underlying
+	return underlying, nil
+}
+
+// readInventory reads the liquidator balances of the repay market underlyings and their allowances to the
+// cTokens, approves the cTokens missing an allowance and takes out the repay amounts of pending liquidations.
+// Markets that cannot be read have no inventory.
+func (o *liqbot) readInventory(ctx context.Context, src *sources, cfg config.Config) *inventory {
+	inv := &inventory{available: map[common.Address]*big.Int{}, shortfalls: map[common.Address]*big.Int{}}
+	opts := &bind.CallOpts{Context: ctx}
+
+	erc20ABI, err := contracts.Erc20MetaData.GetAbi()
+	if err != nil {
+		level.Error(o.logger).Log("msg", "❌ Error reading inventory", "err", err)
+		return inv
+	}
+
+	var (
+		markets     []config.Market
+		underlyings []common.Address
+		calls       []multicall.Call
+	)
+	for _, market := range cfg.Markets() {
+		// cETH is repaid with value, it is never liquidated
+		if market.Address == cfg.Profit().ETHMarket {
+			continue
+		}
+		underlying, err := o.approvals.underlying(opts, src, market.Address)
+		if err != nil {
+			level.Error(o.logger).Log("msg", "❌ Error getting underlying", "market", market.Name, "err", err)
+			continue
+		}
+		markets = append(markets, market)
+		underlyings = append(underlyings, underlying)
+		calls = append(calls,
+			multicall.Call{Target: underlying, ABI: erc20ABI, Method: "balanceOf", Args: []interface{}{src.txOpts.From}},
+			multicall.Call{Target: underlying, ABI: erc20ABI, Method: "allowance", Args: []interface{}{src.txOpts.From, market.Address}},
+		)
+	}
+
+	results := newBatch(src, cfg).Call(opts, calls)
+	for i, market := range markets {
+		balance, allowance := results[2*i], results[2*i+1]
+		if balance.Err != nil || allowance.Err != nil {
+			level.Error(o.logger).Log("msg", "❌ Error reading inventory", "market", market.Name,
+				"err", firstErr(balance.Err, allowance.Err))
+			continue
+		}
+		available := balance.Values[0].(*big.Int)
+		if allowed := allowance.Values[0].(*big.Int); allowed.Cmp(available) < 0 {
+			o.approve(ctx, src, cfg, market, underlyings[i])
+			available = allowed
+		}
+		inv.available[market.Address] = new(big.Int).Set(available)
+		level.Debug(o.logger).Log("msg", "inventory", "market", market.Name, "balance", balance.Values[0], "allowance", allowance.Values[0])
+	}
+
+	if src.store == nil {
+		return inv
+	}
+	attempts, err := src.store.PendingAttempts()
+	if err != nil {
+		level.Error(o.logger).Log("msg", "❌ Error reading pending attempts", "err", err)
+		return inv
+	}
+	for _, attempt := range attempts {
+		if available := inv.available[attempt.RepayCToken]; available != nil {
+			available.Sub(available, attempt.RepayAmount)
+			if available.Sign() < 0 {
+				available.SetInt64(0)
+			}
+		}
+	}
+	return inv
+}
+
+// approve approves market to transfer the underlying of the liquidator, once at a time:
+// no approval is sent while the previous one is not mined. Its nonce is taken in turn with the liquidations.
+func (o *liqbot) approve(ctx context.Context, src *sources, cfg config.Config, market config.Market, underlying common.Address) {
+	a := &o.approvals
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if hash, ok := a.pending[market.Address]; ok {
+		receipt, err := src.client.TransactionReceipt(ctx, hash)
+		if errors.Is(err, ethereum.NotFound) {
+			if _, _, err := src.client.TransactionByHash(ctx, hash); err == nil {
+				level.Info(o.logger).Log("msg", "approval pending", "market", market.Name, "tx", hash.Hex())
+				return
+			}
+			level.Warn(o.logger).Log("msg", "approval dropped", "market", market.Name, "tx", hash.Hex())
+		} else if err != nil {
+			level.Error(o.logger).Log("msg", "❌ Error getting receipt", "tx", hash.Hex(), "err", err)
+			return
+		} else if receipt.Status != types.ReceiptStatusSuccessful {
+			level.Error(o.logger).Log("msg", "❌ approval reverted", "market", market.Name, "tx", hash.Hex())
+		} else {
+			// mined after the allowance was read
+			delete(a.pending, market.Address)
+			return
+		}
+		delete(a.pending, market.Address)
+	}
+
+	if cfg.DryRun().Enabled {
+		level.Warn(o.logger).Log("msg", "allowance missing, not approved in dry run", "market", market.Name, "token", underlying.Hex())
+		return
+	}
+
+	token, err := contracts.NewErc20(underlying, src.client)
+	if err != nil {
+		level.Error(o.logger).Log("msg", "❌ Error approving market", "market", market.Name, "err", err)
+		return
+	}
+	txOpts := *src.txOpts
+	txOpts.Context = ctx
+	if txOpts.Nonce, err = o.nonces.take(ctx, src); err != nil {
+		level.Error(o.logger).Log("msg", "❌ Error getting nonce", "market", market.Name, "err", err)
+		return
+	}
+	tx, err := token.Approve(&txOpts, market.Address, maxAllowance)
+	if err != nil {
+		o.nonces.release(err)
+		level.Error(o.logger).Log("msg", "❌ Error approving market", "market", market.Name, "err", err)
+		return
+	}
+	if a.pending == nil {
+		a.pending = map[common.Address]common.Hash{}
+	}
+	a.pending[market.Address] = tx.Hash()
+	level.Info(o.logger).Log("msg", "🔓 approving market", "market", market.Name, "token", underlying.Hex(), "tx", tx.Hash().Hex())
+}
+
+// reportInventory exports the inventory left after the scan and notifies the repay markets
+// liquidations needed more of than available
+func (o *liqbot) reportInventory(cfg config.Config, params *marketParams, inv *inventory) {
+	for _, market := range cfg.Markets() {
+		price := params.prices[market.Address]
+		available := inv.available[market.Address]
+		if price == nil || available == nil {
+			continue
+		}
+		o.metrics.InventoryUSD.With("market", market.Name).Set(toUSD(mulExp(available, price)))
+
+		shortfall := inv.shortfalls[market.Address]
+		if shortfall == nil {
+			o.metrics.InventoryShortfallUSD.With("market", market.Name).Set(0)
+			continue
+		}
+		shortfallUSD := toUSD(mulExp(shortfall, price))
+		o.metrics.InventoryShortfallUSD.With("market", market.Name).Set(shortfallUSD)
+
+		level.Warn(o.logger).Log("msg", "💸 inventory short", "market", market.Name, "left", available,
+			"shortfall", shortfall, "shortfall_usd", shortfallUSD)
+		o.notifier.Notify(notify.Event{
+			Kind:     notify.KindInventoryShort,
+			Severity: notify.SeverityWarning,
+			Key:      notify.KindInventoryShort + market.Address.Hex(),
+			Text: fmt.Sprintf("liquidations need %.2f USD more %s underlying than the liquidator %s holds",
+				shortfallUSD, market.Name, cfg.AccountAddress().Hex()),
+			Fields: map[string]string{"market": market.Name, "shortfall_usd": fmt.Sprintf("%.2f", shortfallUSD)},
+		})
+	}
+}
+
+func firstErr(errs ...error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -1,149 +1,516 @@
 package liqbot
 
 import (
+	"container/heap"
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"math/big"
+	"net/http"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/log/level"
 	"gitlab.com/q-dev/exchange-rate-oracle/pkg/config"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/metrics"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/notify"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/rpcpool"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/signer"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/store"
 	"gitlab.com/q-dev/exchange-rate-oracle/pkg/subgraph"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/ethclient"
 	"gitlab.com/q-dev/exchange-rate-oracle/pkg/contracts"
 )
 
 // Oracle represents price feed oracle
 type Liqbot interface {
-	Start(ctx context.Context)
+	// Start runs the bot until ctx is done, it reconnects after transient errors and returns fatal ones
+	Start(ctx context.Context) error
+	// Reload swaps config of the running bot, changes to immutable values are rejected
+	Reload(cfg config.Config) error
+}
+
+// Backend represents chain access the bot needs, *ethclient.Client implements it
+type Backend interface {
+	bind.ContractBackend
+	bind.DeployBackend
+	BlockNumber(ctx context.Context) (uint64, error)
+	ChainID(ctx context.Context) (*big.Int, error)
+	TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error)
+	BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error)
+}
+
+// AccountSource represents borrower discovery, the Compound subgraph by default
+type AccountSource interface {
+	GetAccounts(ctx context.Context) ([]subgraph.Account, error)
+}
+
+// Option configures liqbot
+type Option func(*liqbot)
+
+// WithBackend makes the bot use backend instead of dialing the configured RPC URL
+func WithBackend(backend Backend) Option {
+	return func(o *liqbot) {
+		o.backend = backend
+	}
+}
+
+// WithAccountSource makes the bot discover borrowers from source instead of the subgraph
+func WithAccountSource(source AccountSource) Option {
+	return func(o *liqbot) {
+		o.accountSource = source
+	}
+}
+
+// WithMetrics makes the bot record metrics, they are discarded by default
+func WithMetrics(m *metrics.Metrics) Option {
+	return func(o *liqbot) {
+		o.metrics = m
+	}
+}
+
+// WithNotifier makes the bot notify liquidations and failures, nothing is notified by default
+func WithNotifier(n *notify.Notifier) Option {
+	return func(o *liqbot) {
+		o.notifier = n
+	}
 }
 
 // New creates new price feed oracle
-func New(logger log.Logger, cfg config.Config) Liqbot {
-	return &liqbot{
-		logger: logger,
-		cfg:    cfg,
+func New(logger log.Logger, cfg config.Config, opts ...Option) Liqbot {
+	o := &liqbot{
+		metrics: metrics.NewDiscard(),
 	}
+	o.logger = log.With(logger, "scan", log.Valuer(o.scanID))
+	o.cfg.Store(cfg)
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
 }
 
 type liqbot struct {
-	logger log.Logger
-	cfg    config.Config
+	logger        log.Logger
+	backend       Backend
+	accountSource AccountSource
+	metrics       *metrics.Metrics
+	notifier      *notify.Notifier
+	health        health
+	// src holds *sources while connected, nil otherwise
+	src atomic.Value
+	// currentScan holds the correlation ID of the running scan, logged with every line
+	currentScan atomic.Value
+	// cfg holds config.Config, swapped on reload
+	cfg atomic.Value
+	// reloadMu serializes reloads so checks run against the config being replaced
+	reloadMu sync.Mutex
+	// positions caches borrower positions across scans and reconnects
+	positions positionCache
+	watch     watchlist
+	approvals approvals
+	// nonces is shared by approvals and liquidations
+	nonces nonces
 }
 
-func (o *liqbot) Start(ctx context.Context) {
-	comptroller, ctoken, err := o.getInitialSources()
-	if err != nil {
-		level.Error(o.logger).Log("msg", err.Error())
-		return
+// scanID returns the correlation ID of the running scan, empty between scans
+func (o *liqbot) scanID() interface{} {
+	id, _ := o.currentScan.Load().(string)
+	return id
+}
+
+// newScanID returns random correlation ID
+func newScanID() string {
+	id := make([]byte, 6)
+	rand.Read(id)
+	return hex.EncodeToString(id)
+}
+
+func (o *liqbot) getConfig() config.Config {
+	return o.cfg.Load().(config.Config)
+}
+
+func (o *liqbot) Reload(cfg config.Config) error {
+	o.reloadMu.Lock()
+	defer o.reloadMu.Unlock()
+
+	old := o.getConfig()
+	if err := config.CheckReload(old, cfg); err != nil {
+		return err
 	}
 
-	if err != nil {
-		level.Error(o.logger).Log("msg", err.Error())
-		return
+	changes := config.Diff(old, cfg)
+	o.cfg.Store(cfg)
+
+	if len(changes) == 0 {
+		level.Info(o.logger).Log("msg", "config reloaded, no changes")
+		return nil
 	}
 
-	//to remove
-	callerOpts := &bind.CallOpts{
-		Pending: false,
-		Context: ctx,
+	for _, change := range changes {
+		level.Info(o.logger).Log("msg", "config changed", "key", change.Key, "old", change.Old, "new", change.New)
 	}
 
-	foo, err := comptroller.Admin(callerOpts)
-	if err != nil {
-		level.Error(o.logger).Log("error comptroller", err.Error())
-		return
+	return nil
+}
+
+func (o *liqbot) Start(ctx context.Context) error {
+	o.health.started = time.Now()
+	go o.notifier.Run(ctx)
+
+	if listen := o.getConfig().HTTP().Listen; listen != "" {
+		server := o.serveHTTP(listen)
+		defer server.Close()
+	}
+
+	if err := o.supervise(ctx); err != nil {
+		level.Error(o.logger).Log("msg", "❌ Error starting liquidation bot", "err", err)
+		return err
+	}
+	return nil
+}
+
+// scan discovers borrowers and liquidates the ones under water, it fails when the chain cannot be read.
+// With the watchlist on, every account is checked every cfg.Scan().FullScanInterval and the watchlist on
+// every new block in between.
+func (o *liqbot) scan(ctx context.Context, src *sources) error {
+	cfg := o.getConfig()
+	full := o.watch.fullDue(cfg, time.Now())
+	if !full {
+		head, err := src.client.BlockNumber(ctx)
+		if err != nil {
+			return fmt.Errorf("getting block number: %v", err)
+		}
+		if !o.watch.newBlock(head) {
+			return nil
+		}
+	}
+
+	begin := time.Now()
+	o.currentScan.Store(newScanID())
+	defer func() {
+		o.metrics.ScanDuration.Observe(time.Since(begin).Seconds())
+		o.currentScan.Store("")
+	}()
+
+	level.Info(o.logger).Log("msg", "==== LIQBOT", "full", full)
+	accountSource := o.accountSource
+	if accountSource == nil {
+		accountSource = newSubgraph(cfg)
 	}
-	level.Info(o.logger).Log("✅ SUCCESS COMPTROLLER CALL", foo)
 
-	for {
-		select {
-		case <-time.After(o.cfg.UpdateInterval()):
-			o.logger.Log("msg", "==== LIQBOT")
-			subgraph := subgraph.NewSubgraph()
+	var accounts []subgraph.Account
+	var fetchErr error
+	if full {
+		accounts, fetchErr = accountSource.GetAccounts(ctx)
+		o.health.fetched(fetchErr)
 
-			// test subgraph to remove
-			accounts, err := subgraph.GetAccounts(ctx)
+		if fetchErr != nil {
+			level.Error(o.logger).Log("msg", "❌ Error fetching subgraph", "err", fetchErr)
+			if errors.Is(fetchErr, subgraph.ErrStale) {
+				o.notifySubgraphStale(fetchErr.Error())
+			}
+		} else {
+			level.Info(o.logger).Log("msg", "✅ SUCCESS FETCHING SUBGRAPH", "accounts", len(accounts))
+		}
+		o.metrics.AccountsScanned.Add(float64(len(accounts)))
 
-			if err != nil {
-				level.Error(o.logger).Log("ERROR SUBGRAPH", err)
-			} else {
-				level.Info(o.logger).Log("msg", "✅ SUCCESS FETCHING SUBGRAPH")
+		// the watchlist is still checked while the subgraph fails
+		if fetchErr != nil && cfg.Scan().WatchBand > 0 {
+			if accounts = o.watch.list(); len(accounts) > 0 {
+				full, fetchErr = false, nil
 			}
+		}
+	} else {
+		accounts = o.watch.list()
+		level.Info(o.logger).Log("msg", "👀 checking watchlist", "accounts", len(accounts))
+	}
+
+	params, err := o.getMarketParams(ctx, src, cfg)
+	if err != nil {
+		return fmt.Errorf("getting market parameters: %v", err)
+	}
+	level.Debug(o.logger).Log("msg", "market parameters", "block", params.block)
+	if full {
+		o.recordSubgraphLag(ctx, accountSource, params.block)
+	}
+	o.checkBalance(ctx, src, cfg)
+	if fetchErr == nil {
+		defer o.health.scanned()
+	}
 
-			//search
-			level.Info(o.logger).Log("msg", "🔎 Searching unhealthy positions")
+	o.trackPending(ctx, src, cfg)
 
-			for i, a := range accounts {
+	if fetchErr != nil {
+		return nil
+	}
 
-				fmt.Println(" account ", i, " -", a.Id)
+	// repay amounts are capped to the underlying the liquidator holds
+	params.inventory = o.readInventory(ctx, src, cfg)
+	defer o.reportInventory(cfg, params, params.inventory)
 
-				if a.IsLiquidable() {
+	//search
+	level.Info(o.logger).Log("msg", "🔎 Searching unhealthy positions")
 
-					fmt.Println(" 🗡️ liquidating account ")
-					tx, err := liquidateBorrow(a.Id, ctoken, ctx)
-					if err != nil {
-						level.Error(o.logger).Log("msg", "❌ Error calling liquidateBorrow method")
-						level.Error(o.logger).Log("msg", err)
-					} else {
-						fmt.Println("✅ Account liquidated :", tx.Hash().Hex())
-					}
+	queue := o.evaluateAll(ctx, src, cfg, params, accounts, full)
+	level.Info(o.logger).Log("msg", "accounts evaluated", "candidates", queue.Len())
 
-				}
-			}
+	// most profitable first, later ones may land in the next block
+	for queue.Len() > 0 {
+		op := heap.Pop(queue).(*opportunity)
+		account := op.borrower.Hex()
+		if !params.inventory.fit(op) {
+			level.Warn(o.logger).Log("msg", "no inventory left to repay", "account", account, "market", op.repay.Name)
+			continue
+		}
 
-			break
-		case <-ctx.Done():
-			return
+		level.Info(o.logger).Log("msg", "🗡️ liquidating account", "account", account, "market", op.repay.Name,
+			"collateral", op.collateral.Name, "profit_usd", op.profitUSD, "block", op.block)
+		tx, err := o.liquidate(ctx, src, cfg, op)
+		if err != nil {
+			level.Error(o.logger).Log("msg", "❌ Error calling liquidateBorrow method", "account", account, "market", op.repay.Name, "err", err)
+		} else if cfg.DryRun().Enabled {
+			params.inventory.spend(op)
+			level.Info(o.logger).Log("msg", "📝 liquidation recorded (dry run)", "account", account, "tx", tx.Hash().Hex())
+		} else {
+			params.inventory.spend(op)
+			o.metrics.LiquidationsSent.Add(1)
+			level.Info(o.logger).Log("msg", "✅ account liquidated", "account", account, "market", op.repay.Name, "tx", tx.Hash().Hex())
 		}
 	}
+
+	return nil
+}
+
+// metaSource is an account source reporting its indexed block, the subgraph client implements it
+type metaSource interface {
+	GetMeta(ctx context.Context) (*subgraph.Meta, error)
+}
+
+// recordSubgraphLag sets the number of blocks the account source is behind head
+func (o *liqbot) recordSubgraphLag(ctx context.Context, accountSource AccountSource, head uint64) {
+	source, ok := accountSource.(metaSource)
+	if !ok {
+		return
+	}
+
+	meta, err := source.GetMeta(ctx)
+	if err != nil {
+		level.Warn(o.logger).Log("msg", "subgraph status not available", "err", err)
+		return
+	}
+
+	lag := uint64(0)
+	if head > meta.Block.Number {
+		lag = head - meta.Block.Number
+	}
+	o.metrics.SubgraphLag.Set(float64(lag))
+	o.health.lagged(lag)
+
+	if maxLag := o.getConfig().Health().MaxSubgraphLag; maxLag > 0 && lag > maxLag {
+		o.notifySubgraphStale(fmt.Sprintf("subgraph is %d blocks behind the chain head", lag))
+	}
+}
+
+func (o *liqbot) notifySubgraphStale(text string) {
+	o.notifier.Notify(notify.Event{
+		Kind:     notify.KindSubgraphStale,
+		Severity: notify.SeverityWarning,
+		Key:      notify.KindSubgraphStale,
+		Text:     text,
+	})
 }
 
-func liquidateBorrow(borrowerAddrStr string, ctoken *contracts.CToken, ctx context.Context) (*types.Transaction, error) {
+// checkBalance notifies when the signer balance is under the health minimum
+func (o *liqbot) checkBalance(ctx context.Context, src *sources, cfg config.Config) {
+	minBalance := cfg.Health().MinBalance
+	if minBalance == nil {
+		return
+	}
+
+	balance, err := src.client.BalanceAt(ctx, cfg.AccountAddress(), nil)
+	if err != nil {
+		level.Error(o.logger).Log("msg", "❌ Error getting signer balance", "err", err)
+		return
+	}
+	if balance.Cmp(minBalance) >= 0 {
+		return
+	}
 
-	borrowerAddress := common.HexToAddress(borrowerAddrStr)
-	cTokenCollateral := common.HexToAddress("0x0")
-	amount := new(big.Int)
+	level.Warn(o.logger).Log("msg", "signer balance low", "balance_eth", weiToETH(balance).Text('f', 6))
+	o.notifier.Notify(notify.Event{
+		Kind:     notify.KindLowBalance,
+		Severity: notify.SeverityWarning,
+		Key:      notify.KindLowBalance,
+		Text: fmt.Sprintf("liquidator %s balance %s ETH is under %s ETH", cfg.AccountAddress().Hex(),
+			weiToETH(balance).Text('f', 6), weiToETH(minBalance).Text('f', 6)),
+		Fields: map[string]string{"account": cfg.AccountAddress().Hex(), "balance_eth": weiToETH(balance).Text('f', 6)},
+	})
+}
 
-	gasPrice := new(big.Int).SetInt64(240736218990)
+// newSubgraph creates subgraph client for the configured endpoint, the public one when not set.
+// Fetches fail with subgraph.ErrStale when its head block is older than cfg.Health().MaxSubgraphAge.
+func newSubgraph(cfg config.Config) AccountSource {
+	endpoint := subgraph.DefaultEndpoint
+	if cfg.Endpoints().Subgraph != nil {
+		endpoint = cfg.Endpoints().Subgraph.String()
+	}
+	var opts []subgraph.Option
+	if maxAge := cfg.Health().MaxSubgraphAge; maxAge > 0 {
+		opts = append(opts, subgraph.WithMaxStaleness(maxAge))
+	}
+	return subgraph.New(endpoint, &http.Client{}, opts...)
+}
 
-	txOps := &bind.TransactOpts{
-		Context:  ctx,
-		GasPrice: gasPrice,
+// close releases the store, the signer and the pool connections
+func (src *sources) close() {
+	if src.signer != nil {
+		src.signer.Close()
+	}
+	if src.store != nil {
+		src.store.Close()
 	}
+	if src.pool != nil {
+		src.pool.Close()
+	}
+}
+
+// currentSources returns the sources of the running connection, nil while connecting
+func (o *liqbot) currentSources() *sources {
+	src, _ := o.src.Load().(*sources)
+	return src
+}
+
+// sources holds chain bindings shared by scans
+type sources struct {
+	client      Backend
+	comptroller *contracts.ComptrollerCore
+	oracle      *contracts.PriceOracle
+	txOpts      *bind.TransactOpts
+	// signer signs with txOpts, nil when the sources read the chain only
+	signer signer.Signer
+	// pool is nil when the backend is injected
+	pool *rpcpool.Pool
+	// store is nil when state is not persisted
+	store *store.Store
+}
 
-	tx, err := ctoken.LiquidateBorrow(txOps, borrowerAddress, amount, cTokenCollateral)
+// getInitialSources connects to the chain, binds contracts, the signer and the store, errors a reconnect cannot fix are fatal
+func (o *liqbot) getInitialSources(ctx context.Context) (_ *sources, err error) {
+	cfg := o.getConfig()
+	// not the result, returning nil must not hide the sources from the close below
+	src, err := o.connect(ctx)
 	if err != nil {
 		return nil, err
 	}
+	defer func() {
+		if err != nil {
+			src.close()
+		}
+	}()
+
+	chainID, err := src.client.ChainID(ctx)
+	if err != nil {
+		return nil, errors.New("Getting chain id: " + err.Error())
+	}
+	txSigner, err := signer.FromConfig(ctx, cfg)
+	if err != nil {
+		// a remote signer may be restarting, a key that does not decrypt stays so
+		if cfg.Account().SignerURL != nil {
+			return nil, errors.New("Setting signer: " + err.Error())
+		}
+		return nil, fatal(errors.New("Setting signer: " + err.Error()))
+	}
+	src.signer = txSigner
+	if txSigner.Address() != cfg.AccountAddress() {
+		return nil, fatal(errors.New("Setting signer: signer address " + txSigner.Address().Hex() +
+			" does not match account address " + cfg.AccountAddress().Hex()))
+	}
+	src.txOpts = signer.TransactOpts(ctx, txSigner, chainID)
 
-	return tx, nil
+	if path := cfg.Store().Path; path != "" {
+		src.store, err = store.Open(path)
+		if errors.Is(err, store.ErrLocked) {
+			// the previous bot may still be stopping
+			return nil, err
+		}
+		if err != nil {
+			return nil, fatal(err)
+		}
+		level.Info(o.logger).Log("msg", "✅ STORE OPENED", "path", path)
+	}
 
+	return src, nil
 }
 
-func (o *liqbot) getInitialSources() (*contracts.Comptroller, *contracts.CToken, error) {
-	cl, err := ethclient.Dial(o.cfg.RPCURL().String())
+// connect connects to the chain and binds the comptroller and the oracle, the sources read the chain only
+func (o *liqbot) connect(ctx context.Context) (src *sources, err error) {
+	cfg := o.getConfig()
+	cl := o.backend
+	var pool *rpcpool.Pool
+	defer func() {
+		if err != nil && pool != nil {
+			pool.Close()
+		}
+	}()
+	if cl == nil {
+		nodes := []rpcpool.Node{rpcpool.URLNode(cfg.RPCURL().String())}
+		for _, rpcURL := range cfg.Endpoints().RPCPool {
+			nodes = append(nodes, rpcpool.URLNode(rpcURL.String()))
+		}
+		pool, err = rpcpool.New(ctx, o.logger, nodes,
+			rpcpool.WithChainID(cfg.ChainID()),
+			rpcpool.WithRateLimit(cfg.Scan().RPCRateLimit, cfg.Scan().RPCBurst),
+		)
+		if err != nil {
+			return nil, errors.New("Setting RPC pool: " + err.Error())
+		}
+		go pool.Run(ctx, poolCheckInterval)
+		cl = pool
+	}
+	cl = &instrumentedBackend{Backend: cl, errors: o.metrics.RPCErrors, onErrors: o.notifyRPCErrors}
+	if cfg.StartupChecks() {
+		if err := config.CheckChain(ctx, cfg, cl); err != nil {
+			var problems config.ValidationError
+			if errors.As(err, &problems) {
+				return nil, fatal(errors.New("Checking chain: " + err.Error()))
+			}
+			return nil, errors.New("Checking chain: " + err.Error())
+		}
+	}
+	comptroller, err := contracts.NewComptrollerCore(cfg.ContractComptrollerAddress(), cl)
 	if err != nil {
-		return nil, nil, errors.New("Setting ethclient: " + err.Error())
+		return nil, fatal(errors.New("Setting comptroller: " + err.Error()))
+	}
+
+	oracleAddress, err := comptroller.Oracle(&bind.CallOpts{Context: ctx})
+	if errors.Is(err, bind.ErrNoCode) {
+		return nil, fatal(errors.New("Getting oracle: comptroller " + err.Error()))
 	}
-	ctoken, err := contracts.NewCToken(o.cfg.ContractCusdcAddress(), cl)
 	if err != nil {
-		return nil, nil, errors.New("Setting ctoken: " + err.Error())
+		return nil, errors.New("Getting oracle: " + err.Error())
 	}
-	comptroller, err := contracts.NewComptroller(o.cfg.ContractComptrollerAddress(), cl)
+	oracle, err := contracts.NewPriceOracle(oracleAddress, cl)
 	if err != nil {
-		return nil, nil, errors.New("Setting comptroller: " + err.Error())
+		return nil, fatal(errors.New("Setting oracle: " + err.Error()))
 	}
+	level.Info(o.logger).Log("msg", "✅ SUCCESS COMPTROLLER CALL", "oracle", oracleAddress.Hex())
 
-	return comptroller, ctoken, nil
+	return &sources{
+		client:      cl,
+		pool:        pool,
+		comptroller: comptroller,
+		oracle:      oracle,
+	}, nil
 }
 
 const (
 	updatePriceTimeout = time.Second * 10
+	poolCheckInterval  = time.Second * 15
 )
@@ -0,0 +1,201 @@
+package liqbot_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/go-kit/kit/log"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/contracts"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/liqbot"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/simulated"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/store"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/subgraph"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/subgraph/subgraphtest"
+)
+
+// TestLiquidation opens a borrow on the simulated Compound deployment, drops the collateral price
+// to create a shortfall and runs the bot until it liquidates the borrower and serves the outcome in its PnL ledger
+func TestLiquidation(t *testing.T) {
+	h, err := simulated.NewHarness()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Backend.Close()
+
+	if err := h.OpenBorrow(); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.SetPrice(h.CDAI, 0.9); err != nil {
+		t.Fatal(err)
+	}
+
+	shortfall, err := h.Shortfall(h.Borrower())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if shortfall.Sign() == 0 {
+		t.Fatal("borrower has no shortfall after price drop")
+	}
+
+	server := subgraphtest.NewServer(h)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	dir, err := ioutil.TempDir("", "liqbot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	listen, err := freeAddress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := h.Config()
+	cfg.StoreSettings.Path = filepath.Join(dir, "liqbot.db")
+	cfg.HTTPSettings.Listen = listen
+
+	bot := liqbot.New(log.NewNopLogger(), cfg,
+		liqbot.WithBackend(h.Backend),
+		liqbot.WithAccountSource(subgraph.New(server.URL, server.Client())),
+	)
+	done := make(chan error, 1)
+	go func() {
+		done <- bot.Start(ctx)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	collateral, err := contracts.NewCTokenCaller(h.CDAI, h.Backend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	usdc, err := contracts.NewErc20Caller(h.USDC, h.Backend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inventory, err := usdc.BalanceOf(&bind.CallOpts{}, h.Liquidator())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	waitFor(ctx, t, "borrower was not liquidated", func() bool {
+		seized, err := collateral.BalanceOf(&bind.CallOpts{}, h.Liquidator())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return seized.Sign() > 0
+	})
+
+	after, err := h.Shortfall(h.Borrower())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after.Cmp(shortfall) >= 0 {
+		t.Errorf("shortfall did not decrease after liquidation, %s before, %s after", shortfall, after)
+	}
+	left, err := usdc.BalanceOf(&bind.CallOpts{}, h.Liquidator())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if left.Cmp(inventory) >= 0 {
+		t.Errorf("liquidator repaid nothing, %s USDC before, %s after", inventory, left)
+	}
+
+	// the outcome is recorded by a later scan, the store is read through the bot while it holds it
+	waitFor(ctx, t, "liquidation not in the PnL ledger", func() bool {
+		resp, err := http.Get("http://" + listen + "/pnl")
+		if err != nil {
+			return false
+		}
+		defer resp.Body.Close()
+		ledger := new(store.Ledger)
+		if resp.StatusCode != http.StatusOK || json.NewDecoder(resp.Body).Decode(ledger) != nil {
+			return false
+		}
+		return ledger.Total.Liquidations == 1 && ledger.Total.RepayUSD > 0
+	})
+}
+
+// waitFor polls done until it returns true, the test fails with msg when ctx is done first
+func waitFor(ctx context.Context, t *testing.T, msg string, done func() bool) {
+	t.Helper()
+	for !done() {
+		select {
+		case <-time.After(50 * time.Millisecond):
+		case <-ctx.Done():
+			t.Fatal(msg + ": " + ctx.Err().Error())
+		}
+	}
+}
+
+// freeAddress returns a local address nothing listens on
+func freeAddress() (string, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", err
+	}
+	defer l.Close()
+	return l.Addr().String(), nil
+}
+
+// TestScenarios shocks the collateral price of a healthy borrow, only the larger drop puts the borrower in shortfall
+func TestScenarios(t *testing.T) {
+	h, err := simulated.NewHarness()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Backend.Close()
+
+	// 750 USD of collateral against 700 USD borrowed, in shortfall under 0.9333 USD a DAI
+	if err := h.OpenBorrow(); err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []liqbot.Scenario{
+		{Name: "cDAI=-5", Shocks: []liqbot.Shock{{Market: h.CDAI, Change: -0.05}}},
+		{Name: "cDAI=-10", Shocks: []liqbot.Shock{{Market: h.CDAI, Change: -0.1}}},
+	}
+	results, err := liqbot.AnalyzeScenarios(context.Background(), log.NewNopLogger(), h.Config(), scenarios,
+		liqbot.WithBackend(h.Backend),
+		liqbot.WithAccountSource(h.AccountSource()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("%d results, want current and 2 scenarios", len(results))
+	}
+
+	current, mild, severe := results[0], results[1], results[2]
+	if current.Scenario.Name != "current" || current.Accounts != 0 {
+		t.Errorf("current prices: %d accounts in shortfall, want 0", current.Accounts)
+	}
+	if mild.Accounts != 0 {
+		t.Errorf("%s: %d accounts in shortfall, want 0", mild.Scenario.Name, mild.Accounts)
+	}
+	if severe.Accounts != 1 || severe.NewAccounts != 1 {
+		t.Fatalf("%s: %d accounts in shortfall, %d new, want 1 and 1", severe.Scenario.Name, severe.Accounts, severe.NewAccounts)
+	}
+	// 700 borrowed against 1000 DAI at 0.90 and 75%
+	if severe.ShortfallUSD < 24.99 || severe.ShortfallUSD > 25.01 {
+		t.Errorf("shortfall %.4f USD, want 25", severe.ShortfallUSD)
+	}
+	if len(severe.Repay) != 1 || severe.Repay[0].Market.Address != h.CUSDC || severe.Repay[0].Liquidations != 1 {
+		t.Errorf("repay %+v, want one cUSDC liquidation", severe.Repay)
+	}
+	if severe.SeizeUSD <= severe.RepayUSD {
+		t.Errorf("seized %.2f USD for %.2f USD repaid, no incentive", severe.SeizeUSD, severe.RepayUSD)
+	}
+}
@@ -0,0 +1,47 @@
+package liqbot
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net"
+	"sync"
+)
+
+// nonces hands out the nonces of the liquidator transactions, approvals and liquidations take them in turn
+// so a transaction the node does not count in its pending nonce yet never shares its nonce with the next one
+type nonces struct {
+	mu sync.Mutex
+	// next is the nonce after the last one handed out, unset until the first one or after a release
+	next *uint64
+}
+
+// take returns the nonce of the next transaction, the pending nonce of the node unless a higher one was handed out
+func (n *nonces) take(ctx context.Context, src *sources) (*big.Int, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	nonce, err := src.client.PendingNonceAt(ctx, src.txOpts.From)
+	if err != nil {
+		return nil, err
+	}
+	if n.next != nil && *n.next > nonce {
+		nonce = *n.next
+	}
+	next := nonce + 1
+	n.next = &next
+	return new(big.Int).SetUint64(nonce), nil
+}
+
+// release forgets the nonces handed out after a transaction was not sent, the next one is the pending nonce
+// of the node. Nothing is released on network errors as the node may have received the transaction.
+func (n *nonces) release(sendErr error) {
+	var netErr net.Error
+	if errors.As(sendErr, &netErr) {
+		return
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.next = nil
+}
@@ -0,0 +1,57 @@
+package liqbot
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// laggingBackend reports a pending nonce that does not count the transactions just sent
+type laggingBackend struct {
+	Backend
+	pending uint64
+}
+
+func (b *laggingBackend) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return b.pending, nil
+}
+
+// timeoutError is a network error, the node may have received the transaction
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestNonces(t *testing.T) {
+	backend := &laggingBackend{pending: 7}
+	src := &sources{client: backend, txOpts: &bind.TransactOpts{}}
+	n := &nonces{}
+
+	take := func(want uint64) {
+		t.Helper()
+		nonce, err := n.take(context.Background(), src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if nonce.Uint64() != want {
+			t.Errorf("nonce %d, want %d", nonce, want)
+		}
+	}
+
+	// an approval then a liquidation before the node counts the approval
+	take(7)
+	take(8)
+	// the node counted both and a transaction sent by another process
+	backend.pending = 10
+	take(10)
+	// the node may have the transaction
+	n.release(timeoutError{})
+	take(11)
+	// the node rejected it
+	n.release(errors.New("insufficient funds for gas * price + value"))
+	take(10)
+}
@@ -0,0 +1,320 @@
+package liqbot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/go-kit/kit/log/level"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/config"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/contracts"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/liquidity"
+)
+
+// marketParams holds comptroller and oracle values read once per scan
+type marketParams struct {
+	block       uint64
+	closeFactor *big.Int
+	incentive   *big.Int
+	// prices are oracle underlying prices by cToken, scaled by 1e(36 - underlying decimals)
+	prices map[common.Address]*big.Int
+	// collateralFactors are comptroller collateral factors of the configured markets
+	collateralFactors map[common.Address]*big.Int
+	// accruals are the interest states of the configured markets, borrows are projected with them
+	accruals map[common.Address]*liquidity.Accrual
+	gasPrice *big.Int
+	// inventory caps repay amounts, nil when they are not capped
+	inventory *inventory
+}
+
+// opportunity represents liquidation of one borrow of an account against one of its collaterals
+type opportunity struct {
+	borrower    common.Address
+	repay       config.Market
+	collateral  config.Market
+	shortfall   *big.Int
+	repayAmount *big.Int
+	seizeTokens *big.Int
+	// collateralRate is the collateral exchange rate seized tokens are valued at
+	collateralRate *big.Int
+	repayUSD       float64
+	seizeUSD       float64
+	gasUSD         float64
+	profitUSD      float64
+	gasPrice       *big.Int
+	gasLimit       uint64
+	// ethPrice prices gas, nil when no cETH market is configured
+	ethPrice *big.Int
+	block    uint64
+	// simulationErr is kept for dry run records, liquidations failing simulation are never sent
+	simulationErr error
+}
+
+func (o *liqbot) getMarketParams(ctx context.Context, src *sources, cfg config.Config) (*marketParams, error) {
+	block, err := src.client.BlockNumber(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting block number: %v", err)
+	}
+
+	params := &marketParams{
+		block:             block,
+		prices:            map[common.Address]*big.Int{},
+		collateralFactors: map[common.Address]*big.Int{},
+		accruals:          map[common.Address]*liquidity.Accrual{},
+	}
+	opts := params.callOpts(ctx)
+
+	params.closeFactor, err = src.comptroller.CloseFactorMantissa(opts)
+	if err != nil {
+		return nil, fmt.Errorf("getting close factor: %v", err)
+	}
+
+	params.incentive, err = src.comptroller.LiquidationIncentiveMantissa(opts)
+	if err != nil {
+		return nil, fmt.Errorf("getting liquidation incentive: %v", err)
+	}
+
+	priced := append([]common.Address{}, cfg.Profit().ETHMarket)
+	for _, market := range cfg.Markets() {
+		priced = append(priced, market.Address)
+	}
+	for _, market := range priced {
+		if market == (common.Address{}) {
+			continue
+		}
+		price, err := src.oracle.GetUnderlyingPrice(opts, market)
+		if err != nil {
+			return nil, fmt.Errorf("getting %s price: %v", market.Hex(), err)
+		}
+		params.prices[market] = price
+	}
+
+	for _, market := range cfg.Markets() {
+		listing, err := src.comptroller.Markets(opts, market.Address)
+		if err != nil {
+			return nil, fmt.Errorf("getting %s collateral factor: %v", market.Name, err)
+		}
+		params.collateralFactors[market.Address] = listing.CollateralFactorMantissa
+
+		params.accruals[market.Address], err = getAccrual(opts, src, market.Address)
+		if err != nil {
+			return nil, fmt.Errorf("getting %s interest: %v", market.Name, err)
+		}
+	}
+
+	params.gasPrice, err = o.getGasPrice(ctx, src, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return params, nil
+}
+
+// getAccrual reads the interest state of market
+func getAccrual(opts *bind.CallOpts, src *sources, market common.Address) (*liquidity.Accrual, error) {
+	ctoken, err := contracts.NewCTokenCaller(market, src.client)
+	if err != nil {
+		return nil, err
+	}
+	rate, err := ctoken.BorrowRatePerBlock(opts)
+	if err != nil {
+		return nil, err
+	}
+	index, err := ctoken.BorrowIndex(opts)
+	if err != nil {
+		return nil, err
+	}
+	accrualBlock, err := ctoken.AccrualBlockNumber(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &liquidity.Accrual{BorrowRatePerBlock: rate, BorrowIndex: index, AccrualBlock: accrualBlock.Uint64()}, nil
+}
+
+// markets returns the values local liquidity is computed with
+func (p *marketParams) markets() map[common.Address]liquidity.Market {
+	markets := map[common.Address]liquidity.Market{}
+	for market, collateralFactor := range p.collateralFactors {
+		markets[market] = liquidity.Market{CollateralFactor: collateralFactor, Price: p.prices[market]}
+	}
+	return markets
+}
+
+func (p *marketParams) callOpts(ctx context.Context) *bind.CallOpts {
+	return &bind.CallOpts{
+		Context:     ctx,
+		BlockNumber: new(big.Int).SetUint64(p.block),
+	}
+}
+
+// getGasPrice returns suggested gas price scaled by the configured multiplier and capped by the ceiling
+func (o *liqbot) getGasPrice(ctx context.Context, src *sources, cfg config.Config) (*big.Int, error) {
+	suggested, err := src.client.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting gas price: %v", err)
+	}
+
+	gasPrice, _ := new(big.Float).Mul(new(big.Float).SetInt(suggested), big.NewFloat(cfg.Gas().PriceMultiplier)).Int(nil)
+
+	if maxPrice := cfg.Gas().MaxPrice; maxPrice != nil && gasPrice.Cmp(maxPrice) > 0 {
+		level.Warn(o.logger).Log("msg", "gas price capped", "suggested", gasPrice, "max", maxPrice)
+		gasPrice = new(big.Int).Set(maxPrice)
+	}
+
+	return gasPrice, nil
+}
+
+func (op *opportunity) updateProfit() {
+	op.gasUSD = 0
+	if op.ethPrice != nil {
+		gasCost := new(big.Int).Mul(op.gasPrice, new(big.Int).SetUint64(op.gasLimit))
+		op.gasUSD = toUSD(mulExp(gasCost, op.ethPrice))
+	}
+	op.profitUSD = op.seizeUSD - op.repayUSD - op.gasUSD
+}
+
+// simulate runs liquidateBorrow as eth_call from the liquidator account and estimates its gas
+func (o *liqbot) simulate(ctx context.Context, src *sources, cfg config.Config, op *opportunity) error {
+	ctokenABI, err := contracts.CTokenMetaData.GetAbi()
+	if err != nil {
+		return err
+	}
+
+	data, err := ctokenABI.Pack("liquidateBorrow", op.borrower, op.repayAmount, op.collateral.Address)
+	if err != nil {
+		return err
+	}
+
+	msg := ethereum.CallMsg{
+		From:     src.txOpts.From,
+		To:       &op.repay.Address,
+		GasPrice: op.gasPrice,
+		Data:     data,
+	}
+
+	out, err := src.client.CallContract(ctx, msg, nil)
+	if err != nil {
+		return err
+	}
+
+	// compound returns error codes instead of reverting for most failures
+	results, err := ctokenABI.Unpack("liquidateBorrow", out)
+	if err != nil {
+		return fmt.Errorf("decoding liquidateBorrow result: %v", err)
+	}
+	if len(results) != 1 {
+		return errors.New("unexpected liquidateBorrow result")
+	}
+	if code, ok := results[0].(*big.Int); !ok || code.Sign() != 0 {
+		return fmt.Errorf("liquidateBorrow returned error code %v", results[0])
+	}
+
+	if cfg.Gas().Limit == 0 {
+		gas, err := src.client.EstimateGas(ctx, msg)
+		if err != nil {
+			return fmt.Errorf("estimating gas: %v", err)
+		}
+		op.gasLimit = gas * gasLimitMarginPercent / 100
+	}
+
+	return nil
+}
+
+// liquidate simulates the liquidation and sends it, or records it when dry run is enabled.
+// The signed transaction is stored before it is sent so a restart never sends a second one.
+func (o *liqbot) liquidate(ctx context.Context, src *sources, cfg config.Config, op *opportunity) (*types.Transaction, error) {
+	dryRun := cfg.DryRun()
+
+	if err := o.simulate(ctx, src, cfg, op); err != nil {
+		if !dryRun.Enabled {
+			return nil, fmt.Errorf("simulating liquidateBorrow: %v", err)
+		}
+		op.simulationErr = err
+	}
+
+	// the estimated gas limit replaces the default one
+	op.updateProfit()
+	if op.profitUSD < cfg.Profit().MinUSD {
+		return nil, fmt.Errorf("not profitable after gas estimation, expected profit %.2f USD", op.profitUSD)
+	}
+
+	ctoken, err := contracts.NewCToken(op.repay.Address, src.client)
+	if err != nil {
+		return nil, err
+	}
+
+	txOpts := *src.txOpts
+	txOpts.Context = ctx
+	txOpts.GasPrice = op.gasPrice
+	txOpts.GasLimit = op.gasLimit
+	txOpts.NoSend = true
+	// a dry run sends nothing, it signs with the pending nonce of the node
+	if !dryRun.Enabled {
+		if txOpts.Nonce, err = o.nonces.take(ctx, src); err != nil {
+			return nil, fmt.Errorf("getting nonce: %v", err)
+		}
+	}
+
+	tx, err := ctoken.LiquidateBorrow(&txOpts, op.borrower, op.repayAmount, op.collateral.Address)
+	if err != nil {
+		o.nonces.release(err)
+		return nil, err
+	}
+
+	if dryRun.Enabled {
+		if err := recordDryRun(dryRun.Output, op, tx); err != nil {
+			return nil, fmt.Errorf("recording dry run: %v", err)
+		}
+		return tx, nil
+	}
+
+	if err := recordAttempt(src.store, op, tx); err != nil {
+		o.nonces.release(err)
+		return nil, fmt.Errorf("recording attempt: %v", err)
+	}
+
+	if err := src.client.SendTransaction(ctx, tx); err != nil {
+		recordSendError(src.store, tx, err)
+		o.nonces.release(err)
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// mulExp returns a * b / 1e18
+func mulExp(a, b *big.Int) *big.Int {
+	product := new(big.Int).Mul(a, b)
+	return product.Div(product, expScale)
+}
+
+// divExp returns a * 1e18 / b
+func divExp(a, b *big.Int) *big.Int {
+	scaled := new(big.Int).Mul(a, expScale)
+	return scaled.Div(scaled, b)
+}
+
+// toUSD converts 1e18 scaled USD value
+func toUSD(mantissa *big.Int) float64 {
+	usd, _ := new(big.Float).Quo(new(big.Float).SetInt(mantissa), new(big.Float).SetInt(expScale)).Float64()
+	return usd
+}
+
+var (
+	expScale = big.NewInt(1e18)
+
+	// keep seized value 0.1% under the collateral to absorb rounding
+	collateralSafetyNumerator   = big.NewInt(999)
+	collateralSafetyDenominator = big.NewInt(1000)
+)
+
+const (
+	// defaultLiquidationGasLimit prices gas before the liquidation is simulated
+	defaultLiquidationGasLimit = 700000
+	gasLimitMarginPercent      = 120
+)
@@ -0,0 +1,85 @@
+package liqbot
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/config"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/liquidity"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/subgraph"
+)
+
+// position is a borrower position read at block
+type position struct {
+	account liquidity.Account
+	block   uint64
+	// fingerprint is the subgraph view of the account balances when read, it changes when the borrower acts
+	fingerprint string
+	// crossing is the block the account is projected in shortfall at, 0 when not within the lookahead
+	crossing uint64
+}
+
+// positionCache holds borrower positions between scans so accounts are evaluated locally on new prices
+type positionCache struct {
+	mu        sync.Mutex
+	positions map[common.Address]*position
+}
+
+func (c *positionCache) get(borrower common.Address) *position {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.positions[borrower]
+}
+
+func (c *positionCache) put(borrower common.Address, p *position) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.positions == nil {
+		c.positions = map[common.Address]*position{}
+	}
+	c.positions[borrower] = p
+}
+
+// keep forgets the positions of borrowers not in accounts
+func (c *positionCache) keep(accounts []subgraph.Account) {
+	current := map[common.Address]bool{}
+	for _, a := range accounts {
+		current[common.HexToAddress(a.Id)] = true
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for borrower := range c.positions {
+		if !current[borrower] {
+			delete(c.positions, borrower)
+		}
+	}
+}
+
+// fresh reports whether p can be evaluated at block for an account with fingerprint, it must
+// have been read for the markets, which change on reload, and not be approaching shortfall
+func (p *position) fresh(fingerprint string, block, maxAge uint64, markets []config.Market) bool {
+	if p == nil || p.fingerprint != fingerprint || p.crossing != 0 || block < p.block || block-p.block > maxAge {
+		return false
+	}
+	for _, market := range markets {
+		if _, ok := p.account.Snapshots[market.Address]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// fingerprint describes the subgraph account balances, they only change with the account events unlike its health
+func fingerprint(a subgraph.Account) string {
+	var b strings.Builder
+	for _, token := range a.Tokens {
+		b.WriteString(token.Id + ":" + token.CTokenBalance + ":" + token.StoredBorrowBalance)
+		if token.EnteredMarket {
+			b.WriteString(":entered")
+		}
+		b.WriteString("|")
+	}
+	return b.String()
+}
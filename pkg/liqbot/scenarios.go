@@ -0,0 +1,192 @@
+package liqbot
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/go-kit/kit/log"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/config"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/liquidity"
+)
+
+// Shock is a relative move of a market underlying price, -0.1 is a 10% drop
+type Shock struct {
+	Market common.Address
+	Change float64
+}
+
+// Scenario is a named set of price shocks applied together
+type Scenario struct {
+	Name   string
+	Shocks []Shock
+}
+
+// ScenarioResult reports the liquidations a scenario opens, one per borrower at the close factor,
+// the price impact of the liquidations themselves is not modelled
+type ScenarioResult struct {
+	Scenario Scenario
+	// Accounts is the number of borrowers in shortfall, NewAccounts the ones that are not at current prices
+	Accounts    int
+	NewAccounts int
+	// Skipped is the number of borrowers that could not be evaluated, e.g. in markets that are not configured
+	Skipped      int
+	ShortfallUSD float64
+	// RepayUSD, SeizeUSD and ProfitUSD total the liquidations the bot would send, ProfitUSD is net of gas
+	RepayUSD  float64
+	SeizeUSD  float64
+	ProfitUSD float64
+	// Profitable is the number of liquidations reaching the configured minimum profit
+	Profitable int
+	// Repay is the repay liquidity needed in each configured market
+	Repay []MarketRepay
+}
+
+// MarketRepay is the borrow to repay in a market under a scenario
+type MarketRepay struct {
+	Market       config.Market
+	Liquidations int
+	USD          float64
+}
+
+// AnalyzeScenarios evaluates every borrower under each scenario from the current positions and market data,
+// nothing is signed or sent. The first result is the current prices, scenario "current".
+func AnalyzeScenarios(ctx context.Context, logger log.Logger, cfg config.Config, scenarios []Scenario, opts ...Option) ([]*ScenarioResult, error) {
+	o := New(logger, cfg, opts...).(*liqbot)
+	src, err := o.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer src.close()
+
+	accountSource := o.accountSource
+	if accountSource == nil {
+		accountSource = newSubgraph(cfg)
+	}
+	accounts, err := accountSource.GetAccounts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fetching accounts: %v", err)
+	}
+
+	params, err := o.getMarketParams(ctx, src, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("getting market parameters: %v", err)
+	}
+
+	var evaluations []*evaluation
+	for _, a := range accounts {
+		if liquidable, err := a.IsLiquidable(); err != nil || !liquidable {
+			continue
+		}
+		evaluations = append(evaluations, &evaluation{borrower: common.HexToAddress(a.Id), fingerprint: fingerprint(a)})
+	}
+	o.readPositions(params.callOpts(ctx), newBatch(src, cfg), cfg, params, evaluations)
+	for _, e := range evaluations {
+		if e.err == nil {
+			e.account = liquidity.Project(&e.position.account, params.accruals, params.block)
+		}
+	}
+
+	current, inShortfall := o.analyzeScenario(cfg, params, Scenario{Name: "current"}, evaluations, nil)
+	results := []*ScenarioResult{current}
+	for _, scenario := range scenarios {
+		result, _ := o.analyzeScenario(cfg, params, scenario, evaluations, inShortfall)
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// analyzeScenario evaluates the positions of evaluations at the scenario prices, before holds the borrowers
+// in shortfall at current prices, the borrowers in shortfall in the scenario are returned
+func (o *liqbot) analyzeScenario(cfg config.Config, params *marketParams, scenario Scenario, evaluations []*evaluation,
+	before map[common.Address]bool) (*ScenarioResult, map[common.Address]bool) {
+	shocked := params.shock(scenario.Shocks)
+	markets := shocked.markets()
+	var priced []config.Market
+	for _, market := range cfg.Markets() {
+		if price := shocked.prices[market.Address]; price != nil && price.Sign() != 0 {
+			priced = append(priced, market)
+		}
+	}
+
+	result := &ScenarioResult{Scenario: scenario}
+	inShortfall := map[common.Address]bool{}
+	repay := map[common.Address]*MarketRepay{}
+	for _, e := range evaluations {
+		if e.err != nil {
+			result.Skipped++
+			continue
+		}
+		liq, err := liquidity.AccountLiquidity(e.account, markets)
+		if err != nil {
+			result.Skipped++
+			continue
+		}
+		if liq.Shortfall.Sign() == 0 {
+			continue
+		}
+
+		inShortfall[e.borrower] = true
+		result.Accounts++
+		if before != nil && !before[e.borrower] {
+			result.NewAccounts++
+		}
+		result.ShortfallUSD += toUSD(liq.Shortfall)
+
+		op, err := o.size(cfg, shocked, &evaluation{borrower: e.borrower, account: e.account, shortfall: liq.Shortfall}, priced)
+		if err != nil || op == nil {
+			continue
+		}
+		op.seizeTokens, err = liquidity.SeizeTokens(op.repayAmount, shocked.incentive, shocked.prices[op.repay.Address],
+			shocked.prices[op.collateral.Address], op.collateralRate)
+		if err != nil {
+			continue
+		}
+		op.seizeUSD = toUSD(mulExp(mulExp(op.seizeTokens, op.collateralRate), shocked.prices[op.collateral.Address]))
+		op.updateProfit()
+
+		result.RepayUSD += op.repayUSD
+		result.SeizeUSD += op.seizeUSD
+		result.ProfitUSD += op.profitUSD
+		if op.profitUSD >= cfg.Profit().MinUSD {
+			result.Profitable++
+		}
+
+		r := repay[op.repay.Address]
+		if r == nil {
+			r = &MarketRepay{Market: op.repay}
+			repay[op.repay.Address] = r
+		}
+		r.Liquidations++
+		r.USD += op.repayUSD
+	}
+
+	for _, market := range cfg.Markets() {
+		if r := repay[market.Address]; r != nil {
+			result.Repay = append(result.Repay, *r)
+		}
+	}
+	return result, inShortfall
+}
+
+// shock returns a copy of p with the prices moved by shocks
+func (p *marketParams) shock(shocks []Shock) *marketParams {
+	shocked := *p
+	shocked.prices = map[common.Address]*big.Int{}
+	for market, price := range p.prices {
+		shocked.prices[market] = price
+	}
+	for _, s := range shocks {
+		price := shocked.prices[s.Market]
+		if price == nil {
+			continue
+		}
+		moved, _ := new(big.Float).Mul(new(big.Float).SetInt(price), big.NewFloat(1+s.Change)).Int(nil)
+		if moved.Sign() < 0 {
+			moved.SetInt64(0)
+		}
+		shocked.prices[s.Market] = moved
+	}
+	return &shocked
+}
@@ -0,0 +1,60 @@
+package liqbot
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/config"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/subgraph"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/subgraph/subgraphtest"
+)
+
+// subgraphConfig points the subgraph endpoint at a test server
+type subgraphConfig struct {
+	config.Config
+	endpoint *url.URL
+	maxAge   time.Duration
+}
+
+func (c subgraphConfig) Endpoints() config.Endpoints {
+	return config.Endpoints{Subgraph: c.endpoint}
+}
+
+func (c subgraphConfig) Health() config.Health {
+	return config.Health{MaxSubgraphAge: c.maxAge}
+}
+
+func TestNewSubgraphStaleness(t *testing.T) {
+	fixture := &subgraphtest.Fixture{}
+	fixture.Meta.Block.Number = 100
+	fixture.Meta.Block.Timestamp = time.Now().Add(-time.Hour).Unix()
+	server := subgraphtest.NewServer(subgraphtest.Static(fixture))
+	defer server.Close()
+	endpoint, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range []struct {
+		name   string
+		maxAge time.Duration
+		stale  bool
+	}{
+		{"stale", 10 * time.Minute, true},
+		{"within limit", 2 * time.Hour, false},
+		{"not checked", 0, false},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := newSubgraph(subgraphConfig{endpoint: endpoint, maxAge: c.maxAge}).GetAccounts(context.Background())
+			if stale := errors.Is(err, subgraph.ErrStale); stale != c.stale {
+				t.Errorf("got %v, stale %v expected", err, c.stale)
+			}
+			if !c.stale && err != nil {
+				t.Errorf("unexpected error %v", err)
+			}
+		})
+	}
+}
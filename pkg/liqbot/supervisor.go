@@ -0,0 +1,145 @@
+package liqbot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"github.com/go-kit/kit/log/level"
+)
+
+// fatalError is an error retrying does not fix, e.g. a config not matching the chain
+type fatalError struct {
+	err error
+}
+
+func (e *fatalError) Error() string {
+	return e.err.Error()
+}
+
+func (e *fatalError) Unwrap() error {
+	return e.err
+}
+
+// fatal marks err as not worth retrying
+func fatal(err error) error {
+	return &fatalError{err: err}
+}
+
+func isFatal(err error) bool {
+	var f *fatalError
+	return errors.As(err, &f)
+}
+
+// backoff doubles the delay after each failure up to max
+type backoff struct {
+	min, max time.Duration
+	current  time.Duration
+}
+
+func newBackoff(min, max time.Duration) *backoff {
+	return &backoff{min: min, max: max}
+}
+
+// next returns the delay before the next attempt
+func (b *backoff) next() time.Duration {
+	if b.current == 0 {
+		b.current = b.min
+	} else if b.current *= 2; b.current > b.max {
+		b.current = b.max
+	}
+	return b.current
+}
+
+// reset starts the delays over after a success
+func (b *backoff) reset() {
+	b.current = 0
+}
+
+// supervise runs the bot until ctx is done, it connects again with backoff after transient errors
+// and returns fatal ones
+func (o *liqbot) supervise(ctx context.Context) error {
+	retry := newBackoff(minRestartDelay, maxRestartDelay)
+	for {
+		err := o.run(ctx, retry)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if isFatal(err) {
+			return err
+		}
+
+		delay := retry.next()
+		level.Warn(o.logger).Log("msg", "🔁 reconnecting after error", "err", err, "retry_in", delay)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// run connects and scans until ctx is done or scans keep failing, scans run every update interval
+// and on every new block while the watchlist is on
+func (o *liqbot) run(ctx context.Context, retry *backoff) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	src, err := o.getInitialSources(ctx)
+	if err != nil {
+		return err
+	}
+	o.src.Store(src)
+	defer func() {
+		o.src.Store((*sources)(nil))
+		src.close()
+	}()
+	o.updatePnLMetrics(src.store)
+
+	// the watchlist is checked on every new block, whatever the update interval
+	heads := make(chan uint64, 1)
+	go o.pollHeads(ctx, src, headPollInterval, heads)
+
+	failures := 0
+	for {
+		select {
+		case <-time.After(o.getConfig().UpdateInterval()):
+		case <-heads:
+		case <-ctx.Done():
+			return nil
+		}
+
+		if err := o.safeScan(ctx, src); err != nil {
+			failures++
+			level.Error(o.logger).Log("msg", "❌ scan failed", "failures", failures, "err", err)
+			if failures >= maxScanFailures {
+				return fmt.Errorf("%d consecutive scans failed: %v", failures, err)
+			}
+			continue
+		}
+		failures = 0
+		retry.reset()
+	}
+}
+
+// safeScan scans and turns a panic into an error so the supervisor reconnects
+func (o *liqbot) safeScan(ctx context.Context, src *sources) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			level.Error(o.logger).Log("msg", "❌ scan panicked", "panic", r, "stack", string(debug.Stack()))
+			err = fmt.Errorf("scan panicked: %v", r)
+		}
+	}()
+	return o.scan(ctx, src)
+}
+
+const (
+	minRestartDelay = time.Second
+	maxRestartDelay = time.Minute
+	// headPollInterval is how often the head is polled for new blocks while the watchlist is on
+	headPollInterval = time.Second
+	// maxScanFailures is the number of consecutive failed scans before the chain connection is rebuilt
+	maxScanFailures = 3
+)
@@ -0,0 +1,365 @@
+package liqbot
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/go-kit/kit/log"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/config"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/contracts"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/store"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/subgraph"
+)
+
+var (
+	stubComptroller = common.HexToAddress("0xC0")
+	stubOracle      = common.HexToAddress("0x0C")
+)
+
+// stubBackend is a node with a comptroller at stubComptroller answering oracle() only,
+// the first oracleFailures oracle() calls and every block number fail like a node going away
+type stubBackend struct {
+	Backend
+	chainErr       error
+	noCode         bool
+	oracleFailures int32
+
+	oracleCalls int32
+	blockCalls  int32
+}
+
+func (b *stubBackend) ChainID(ctx context.Context) (*big.Int, error) {
+	if b.chainErr != nil {
+		return nil, b.chainErr
+	}
+	return big.NewInt(1337), nil
+}
+
+func (b *stubBackend) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	if b.noCode || contract != stubComptroller {
+		return nil, nil
+	}
+	return []byte{1}, nil
+}
+
+func (b *stubBackend) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	if b.noCode {
+		return nil, nil
+	}
+	comptrollerABI, err := contracts.ComptrollerCoreMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	oracle := comptrollerABI.Methods["oracle"]
+	if *call.To != stubComptroller || len(call.Data) < 4 || string(call.Data[:4]) != string(oracle.ID) {
+		return nil, errors.New("connection refused")
+	}
+	if atomic.AddInt32(&b.oracleCalls, 1) <= b.oracleFailures {
+		return nil, errors.New("connection refused")
+	}
+	return oracle.Outputs.Pack(stubOracle)
+}
+
+func (b *stubBackend) BlockNumber(ctx context.Context) (uint64, error) {
+	atomic.AddInt32(&b.blockCalls, 1)
+	return 0, errors.New("connection reset by peer")
+}
+
+// stubConfig signs with key and scans every interval without the watchlist
+type stubConfig struct {
+	config.Config
+	key           *ecdsa.PrivateKey
+	address       common.Address
+	account       config.Account
+	storePath     string
+	startupChecks bool
+	interval      time.Duration
+}
+
+func newStubConfig(t *testing.T) *stubConfig {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &stubConfig{key: key, address: crypto.PubkeyToAddress(key.PublicKey), interval: time.Hour}
+}
+
+func (c *stubConfig) AccountKey() *ecdsa.PrivateKey              { return c.key }
+func (c *stubConfig) AccountAddress() common.Address             { return c.address }
+func (c *stubConfig) Account() config.Account                    { return c.account }
+func (c *stubConfig) Store() config.Store                        { return config.Store{Path: c.storePath} }
+func (c *stubConfig) StartupChecks() bool                        { return c.startupChecks }
+func (c *stubConfig) UpdateInterval() time.Duration              { return c.interval }
+func (c *stubConfig) ChainID() *big.Int                          { return big.NewInt(1) }
+func (c *stubConfig) ContractComptrollerAddress() common.Address { return stubComptroller }
+func (c *stubConfig) ContractCusdcAddress() common.Address       { return common.Address{} }
+func (c *stubConfig) Markets() []config.Market                   { return nil }
+func (c *stubConfig) Scan() config.Scan                          { return config.Scan{} }
+func (c *stubConfig) Notify() config.Notify                      { return config.Notify{} }
+
+// stubAccounts has no borrowers, or panics
+type stubAccounts struct {
+	panics bool
+}
+
+func (s stubAccounts) GetAccounts(ctx context.Context) ([]subgraph.Account, error) {
+	if s.panics {
+		panic("index out of range")
+	}
+	return nil, nil
+}
+
+func newStubBot(cfg config.Config, backend Backend, accounts AccountSource) *liqbot {
+	return New(log.NewNopLogger(), cfg, WithBackend(backend), WithAccountSource(accounts)).(*liqbot)
+}
+
+func TestIsFatal(t *testing.T) {
+	cause := errors.New("no contract code at given address")
+	tests := []struct {
+		name  string
+		err   error
+		fatal bool
+	}{
+		{"plain", cause, false},
+		{"fatal", fatal(cause), true},
+		{"wrapped fatal", fmt.Errorf("connecting: %w", fatal(cause)), true},
+		{"fatal message only", errors.New(fatal(cause).Error()), false},
+		{"store locked", fmt.Errorf("Opening store: %w", store.ErrLocked), false},
+	}
+	for _, tt := range tests {
+		if got := isFatal(tt.err); got != tt.fatal {
+			t.Errorf("%s: fatal %v, want %v", tt.name, got, tt.fatal)
+		}
+	}
+}
+
+func TestInitialSourcesErrors(t *testing.T) {
+	lockedPath := filepath.Join(t.TempDir(), "locked.db")
+	locked, err := store.Open(lockedPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer locked.Close()
+
+	tests := []struct {
+		name    string
+		backend *stubBackend
+		config  func(cfg *stubConfig)
+		fatal   bool
+	}{
+		{
+			name:    "oracle unreachable",
+			backend: &stubBackend{oracleFailures: 1},
+		},
+		{
+			name:    "chain id unreachable",
+			backend: &stubBackend{chainErr: errors.New("i/o timeout")},
+		},
+		{
+			name:    "comptroller without code",
+			backend: &stubBackend{noCode: true},
+			fatal:   true,
+		},
+		{
+			name:    "startup checks unreachable",
+			backend: &stubBackend{chainErr: errors.New("i/o timeout")},
+			config:  func(cfg *stubConfig) { cfg.startupChecks = true },
+		},
+		{
+			name:    "startup checks failed",
+			backend: &stubBackend{},
+			config:  func(cfg *stubConfig) { cfg.startupChecks = true },
+			fatal:   true,
+		},
+		{
+			name:    "no key",
+			backend: &stubBackend{},
+			config:  func(cfg *stubConfig) { cfg.key = nil },
+			fatal:   true,
+		},
+		{
+			// a websocket signer is dialed right away
+			name:    "remote signer down",
+			backend: &stubBackend{},
+			config: func(cfg *stubConfig) {
+				cfg.key = nil
+				cfg.account = config.Account{SignerURL: &url.URL{Scheme: "ws", Host: "127.0.0.1:1"}, Address: cfg.address}
+			},
+		},
+		{
+			name:    "signer address mismatch",
+			backend: &stubBackend{},
+			config:  func(cfg *stubConfig) { cfg.address = common.HexToAddress("0x1") },
+			fatal:   true,
+		},
+		{
+			name:    "store locked",
+			backend: &stubBackend{},
+			config:  func(cfg *stubConfig) { cfg.storePath = lockedPath },
+		},
+		{
+			name:    "store unopenable",
+			backend: &stubBackend{},
+			config:  func(cfg *stubConfig) { cfg.storePath = filepath.Join(t.TempDir(), "missing", "liqbot.db") },
+			fatal:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newStubConfig(t)
+			if tt.config != nil {
+				tt.config(cfg)
+			}
+			o := newStubBot(cfg, tt.backend, stubAccounts{})
+
+			src, err := o.getInitialSources(context.Background())
+			if err == nil {
+				src.close()
+				t.Fatal("connected")
+			}
+			if got := isFatal(err); got != tt.fatal {
+				t.Errorf("%v: fatal %v, want %v", err, got, tt.fatal)
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	b := newBackoff(time.Second, 10*time.Second)
+	for i, want := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second} {
+		if got := b.next(); got != want {
+			t.Errorf("attempt %d: delay %v, want %v", i+1, got, want)
+		}
+	}
+	b.reset()
+	if got := b.next(); got != time.Second {
+		t.Errorf("delay after reset %v, want %v", got, time.Second)
+	}
+}
+
+func TestSafeScan(t *testing.T) {
+	o := newStubBot(newStubConfig(t), &stubBackend{}, stubAccounts{panics: true})
+
+	err := o.safeScan(context.Background(), &sources{client: &stubBackend{}})
+	if err == nil || !strings.Contains(err.Error(), "scan panicked: index out of range") {
+		t.Errorf("got %v, want the panic", err)
+	}
+	if id := o.scanID(); id != "" {
+		t.Errorf("scan id %v kept after the panic", id)
+	}
+}
+
+func TestRunScanFailures(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		accounts stubAccounts
+		err      string
+	}{
+		{"chain read fails", stubAccounts{}, "getting market parameters"},
+		{"scan panics", stubAccounts{panics: true}, "scan panicked"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newStubConfig(t)
+			cfg.interval = 10 * time.Millisecond
+			backend := &stubBackend{}
+			o := newStubBot(cfg, backend, tt.accounts)
+
+			retry := newBackoff(minRestartDelay, maxRestartDelay)
+			retry.next()
+			err := o.run(context.Background(), retry)
+			if err == nil || !strings.Contains(err.Error(), fmt.Sprintf("%d consecutive scans failed", maxScanFailures)) || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("got %v, want %d failed scans with %q", err, maxScanFailures, tt.err)
+			}
+			if o.currentSources() != nil {
+				t.Error("sources kept after the connection is given up")
+			}
+			// failed scans do not reset the reconnect delays
+			if got := retry.next(); got != 2*minRestartDelay {
+				t.Errorf("next reconnect in %v, want %v", got, 2*minRestartDelay)
+			}
+		})
+	}
+}
+
+func TestSupervise(t *testing.T) {
+	waitFor := func(what string, cond func() bool) {
+		t.Helper()
+		deadline := time.Now().Add(10 * time.Second)
+		for !cond() {
+			if time.Now().After(deadline) {
+				t.Fatalf("timed out waiting for %s", what)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	t.Run("connects after failures", func(t *testing.T) {
+		backend := &stubBackend{oracleFailures: 2}
+		o := newStubBot(newStubConfig(t), backend, stubAccounts{})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() { done <- o.supervise(ctx) }()
+
+		// two reconnects, after 1s and 2s
+		waitFor("connection", func() bool { return o.currentSources() != nil })
+		if calls := atomic.LoadInt32(&backend.oracleCalls); calls != 3 {
+			t.Errorf("%d connection attempts, want 3", calls)
+		}
+
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("stopped with %v", err)
+		}
+	})
+
+	t.Run("reconnects after failed scans", func(t *testing.T) {
+		cfg := newStubConfig(t)
+		cfg.interval = 10 * time.Millisecond
+		backend := &stubBackend{}
+		o := newStubBot(cfg, backend, stubAccounts{})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() { done <- o.supervise(ctx) }()
+
+		waitFor("reconnect", func() bool { return atomic.LoadInt32(&backend.oracleCalls) >= 2 })
+		if scans := atomic.LoadInt32(&backend.blockCalls); scans < maxScanFailures {
+			t.Errorf("reconnected after %d scans, want %d", scans, maxScanFailures)
+		}
+
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("stopped with %v", err)
+		}
+	})
+
+	t.Run("fatal error", func(t *testing.T) {
+		backend := &stubBackend{noCode: true}
+		o := newStubBot(newStubConfig(t), backend, stubAccounts{})
+
+		done := make(chan error, 1)
+		go func() { done <- o.supervise(context.Background()) }()
+		select {
+		case err := <-done:
+			if !isFatal(err) {
+				t.Errorf("got %v, want a fatal error", err)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("fatal error retried")
+		}
+	})
+}
@@ -0,0 +1,360 @@
+package liqbot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/go-kit/kit/log/level"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/config"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/contracts"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/notify"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/store"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/subgraph"
+)
+
+// evaluationRetention is how many blocks evaluations are kept, about a week of 12s blocks
+const evaluationRetention = 50400
+
+// recordEvaluations stores borrower health seen at block by a scan in one transaction, only the changes
+// are kept and full scans prune the evaluations older than evaluationRetention. Nothing is stored without a store.
+func (o *liqbot) recordEvaluations(st *store.Store, accounts []subgraph.Account, evaluations []*evaluation, block uint64, full bool) {
+	if st == nil {
+		return
+	}
+
+	var records []*store.Evaluation
+	for i, e := range evaluations {
+		if e.shortfall == nil {
+			continue
+		}
+		health, _ := strconv.ParseFloat(accounts[i].Health, 64)
+		record := &store.Evaluation{
+			Borrower:    e.borrower,
+			Block:       block,
+			Time:        time.Now().UTC(),
+			Health:      health,
+			Shortfall:   e.shortfall,
+			Opportunity: e.op != nil,
+		}
+		if e.op != nil {
+			record.ExpectedProfitUSD = e.op.profitUSD
+		}
+		records = append(records, record)
+	}
+
+	var pruneBefore uint64
+	if full && block > evaluationRetention {
+		pruneBefore = block - evaluationRetention
+	}
+	if len(records) == 0 && pruneBefore == 0 {
+		return
+	}
+
+	if err := st.PutEvaluations(records, pruneBefore); err != nil {
+		level.Error(o.logger).Log("msg", "❌ Error storing evaluations", "count", len(records), "err", err)
+	}
+}
+
+// recordAttempt stores signed liquidation as pending for the borrower
+func recordAttempt(st *store.Store, op *opportunity, tx *types.Transaction) error {
+	if st == nil {
+		return nil
+	}
+
+	rawTx, err := tx.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	return st.PutAttempt(&store.Attempt{
+		TxHash:            tx.Hash(),
+		Borrower:          op.borrower,
+		RepayMarket:       op.repay.Name,
+		RepayCToken:       op.repay.Address,
+		CollateralMarket:  op.collateral.Name,
+		CollateralCToken:  op.collateral.Address,
+		RepayAmount:       op.repayAmount,
+		SeizeTokens:       op.seizeTokens,
+		Nonce:             tx.Nonce(),
+		GasPrice:          tx.GasPrice(),
+		GasLimit:          tx.Gas(),
+		ExpectedProfitUSD: op.profitUSD,
+		Block:             op.block,
+		Time:              time.Now().UTC(),
+		RawTx:             rawTx,
+	})
+}
+
+// recordSendError ends the attempt when the node rejected the transaction,
+// it stays pending on network errors as the node may have received it
+func recordSendError(st *store.Store, tx *types.Transaction, sendErr error) {
+	var netErr net.Error
+	if st == nil || errors.As(sendErr, &netErr) {
+		return
+	}
+
+	st.PutOutcome(&store.Outcome{
+		TxHash: tx.Hash(),
+		Status: store.StatusNotSent,
+		Error:  sendErr.Error(),
+		Time:   time.Now().UTC(),
+	})
+}
+
+// trackPending records outcomes of mined attempts and rebroadcasts the ones the node lost
+func (o *liqbot) trackPending(ctx context.Context, src *sources, cfg config.Config) {
+	if src.store == nil {
+		return
+	}
+
+	attempts, err := src.store.PendingAttempts()
+	if err != nil {
+		level.Error(o.logger).Log("msg", "❌ Error reading pending attempts", "err", err)
+		return
+	}
+
+	mined := false
+	defer func() {
+		if mined {
+			o.updatePnLMetrics(src.store)
+		}
+	}()
+
+	for _, attempt := range attempts {
+		receipt, err := src.client.TransactionReceipt(ctx, attempt.TxHash)
+		if err == nil {
+			outcome := o.outcomeFromReceipt(ctx, src, cfg, attempt, receipt)
+			if err := src.store.PutOutcome(outcome); err != nil {
+				level.Error(o.logger).Log("msg", "❌ Error storing outcome", "tx", attempt.TxHash.Hex(), "err", err)
+				continue
+			}
+			level.Info(o.logger).Log("msg", "liquidation mined", "tx", attempt.TxHash.Hex(), "status", outcome.Status,
+				"block", outcome.Block, "seize_tokens", outcome.SeizeTokens, "profit_usd", outcome.ProfitUSD)
+			o.recordMined(attempt, outcome)
+			mined = true
+			continue
+		}
+		if !errors.Is(err, ethereum.NotFound) {
+			level.Error(o.logger).Log("msg", "❌ Error getting receipt", "tx", attempt.TxHash.Hex(), "err", err)
+			continue
+		}
+
+		if _, _, err := src.client.TransactionByHash(ctx, attempt.TxHash); err == nil {
+			continue
+		}
+
+		tx := new(types.Transaction)
+		if err := tx.UnmarshalBinary(attempt.RawTx); err != nil {
+			level.Error(o.logger).Log("msg", "❌ Error decoding stored transaction", "tx", attempt.TxHash.Hex(), "err", err)
+			continue
+		}
+
+		err = src.client.SendTransaction(ctx, tx)
+		switch {
+		case err == nil:
+			level.Info(o.logger).Log("msg", "liquidation rebroadcast", "tx", attempt.TxHash.Hex(), "nonce", attempt.Nonce)
+		case strings.Contains(err.Error(), "nonce too low"):
+			src.store.PutOutcome(&store.Outcome{
+				TxHash: attempt.TxHash,
+				Status: store.StatusDropped,
+				Error:  err.Error(),
+				Time:   time.Now().UTC(),
+			})
+			level.Warn(o.logger).Log("msg", "liquidation dropped, nonce used by another transaction", "tx", attempt.TxHash.Hex())
+		default:
+			level.Error(o.logger).Log("msg", "❌ Error rebroadcasting liquidation", "tx", attempt.TxHash.Hex(), "err", err)
+		}
+	}
+}
+
+// outcomeFromReceipt reads realized amounts from the receipt and prices them at the mined block
+func (o *liqbot) outcomeFromReceipt(ctx context.Context, src *sources, cfg config.Config, attempt *store.Attempt, receipt *types.Receipt) *store.Outcome {
+	outcome := &store.Outcome{
+		TxHash:   attempt.TxHash,
+		Status:   store.StatusSucceeded,
+		Block:    receipt.BlockNumber.Uint64(),
+		GasUsed:  receipt.GasUsed,
+		GasPrice: receipt.EffectiveGasPrice,
+		Time:     time.Now().UTC(),
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		outcome.Status = store.StatusReverted
+	}
+	if outcome.GasPrice == nil {
+		outcome.GasPrice = attempt.GasPrice
+	}
+
+	outcome.BlockTime = outcome.Time
+	if header, err := src.client.HeaderByNumber(ctx, receipt.BlockNumber); err == nil {
+		outcome.BlockTime = time.Unix(int64(header.Time), 0).UTC()
+	}
+
+	if outcome.Status == store.StatusSucceeded {
+		o.readLiquidation(src, attempt, receipt, outcome)
+	}
+
+	if err := o.priceOutcome(ctx, src, cfg, attempt, receipt.BlockNumber, outcome); err != nil {
+		level.Warn(o.logger).Log("msg", "liquidation PnL not priced", "tx", attempt.TxHash.Hex(), "err", err)
+	}
+
+	return outcome
+}
+
+// readLiquidation sets repaid and seized amounts from the LiquidateBorrow event, the collateral
+// Transfer to the liquidator is preferred for seized tokens as it excludes the protocol seize share
+func (o *liqbot) readLiquidation(src *sources, attempt *store.Attempt, receipt *types.Receipt, outcome *store.Outcome) {
+	filterer, err := contracts.NewCTokenFilterer(attempt.RepayCToken, src.client)
+	if err != nil {
+		return
+	}
+
+	for _, log := range receipt.Logs {
+		if log.Address != attempt.RepayCToken {
+			continue
+		}
+		event, err := filterer.ParseLiquidateBorrow(*log)
+		if err != nil {
+			continue
+		}
+		outcome.RepayAmount = event.RepayAmount
+		outcome.SeizeTokens = event.SeizeTokens
+		outcome.CollateralCToken = event.CTokenCollateral
+	}
+
+	if outcome.SeizeTokens == nil {
+		return
+	}
+
+	received := new(big.Int)
+	for _, log := range receipt.Logs {
+		if log.Address != outcome.CollateralCToken {
+			continue
+		}
+		transfer, err := filterer.ParseTransfer(*log)
+		if err != nil || transfer.To != src.txOpts.From {
+			continue
+		}
+		received.Add(received, transfer.Amount)
+	}
+	if received.Sign() > 0 {
+		outcome.SeizeTokens = received
+	}
+}
+
+// priceOutcome values the outcome with oracle prices and exchange rate at block, latest ones when the node has no state for it
+func (o *liqbot) priceOutcome(ctx context.Context, src *sources, cfg config.Config, attempt *store.Attempt, block *big.Int, outcome *store.Outcome) error {
+	opts := &bind.CallOpts{Context: ctx, BlockNumber: block}
+	if _, err := src.oracle.GetUnderlyingPrice(opts, attempt.RepayCToken); err != nil {
+		opts.BlockNumber = nil
+	}
+
+	if ethMarket := cfg.Profit().ETHMarket; ethMarket != (common.Address{}) {
+		ethPrice, err := src.oracle.GetUnderlyingPrice(opts, ethMarket)
+		if err != nil {
+			return fmt.Errorf("getting ETH price: %v", err)
+		}
+		gasCost := new(big.Int).Mul(outcome.GasPrice, new(big.Int).SetUint64(outcome.GasUsed))
+		outcome.GasUSD = toUSD(mulExp(gasCost, ethPrice))
+	}
+	outcome.ProfitUSD = -outcome.GasUSD
+
+	if outcome.RepayAmount == nil || outcome.SeizeTokens == nil {
+		return nil
+	}
+
+	repayPrice, err := src.oracle.GetUnderlyingPrice(opts, attempt.RepayCToken)
+	if err != nil {
+		return fmt.Errorf("getting repay price: %v", err)
+	}
+	collateralPrice, err := src.oracle.GetUnderlyingPrice(opts, outcome.CollateralCToken)
+	if err != nil {
+		return fmt.Errorf("getting collateral price: %v", err)
+	}
+	collateral, err := contracts.NewCTokenCaller(outcome.CollateralCToken, src.client)
+	if err != nil {
+		return err
+	}
+	exchangeRate, err := collateral.ExchangeRateStored(opts)
+	if err != nil {
+		return fmt.Errorf("getting exchange rate: %v", err)
+	}
+
+	outcome.SeizedUnderlying = mulExp(outcome.SeizeTokens, exchangeRate)
+	outcome.RepayUSD = toUSD(mulExp(outcome.RepayAmount, repayPrice))
+	outcome.SeizedUSD = toUSD(mulExp(outcome.SeizedUnderlying, collateralPrice))
+	outcome.ProfitUSD = outcome.SeizedUSD - outcome.RepayUSD - outcome.GasUSD
+	return nil
+}
+
+// recordMined counts mined liquidation and the gas it paid
+func (o *liqbot) recordMined(attempt *store.Attempt, outcome *store.Outcome) {
+	o.metrics.LiquidationsMined.Add(1)
+	if outcome.Status == store.StatusReverted {
+		o.metrics.LiquidationsReverted.Add(1)
+		o.notifier.Notify(notify.Event{
+			Kind:     notify.KindReverted,
+			Severity: notify.SeverityError,
+			Text: fmt.Sprintf("liquidation of %s reverted in block %d, gas %.2f USD, tx %s",
+				attempt.Borrower.Hex(), outcome.Block, outcome.GasUSD, attempt.TxHash.Hex()),
+			Fields: map[string]string{
+				"account": attempt.Borrower.Hex(),
+				"market":  attempt.RepayMarket,
+				"tx":      attempt.TxHash.Hex(),
+				"block":   fmt.Sprint(outcome.Block),
+				"gas_usd": fmt.Sprintf("%.2f", outcome.GasUSD),
+			},
+		})
+	} else {
+		o.notifier.Notify(notify.Event{
+			Kind: notify.KindLiquidation,
+			Text: fmt.Sprintf("liquidated %s repaying %s for %s collateral, profit %.2f USD, tx %s",
+				attempt.Borrower.Hex(), attempt.RepayMarket, attempt.CollateralMarket, outcome.ProfitUSD, attempt.TxHash.Hex()),
+			Fields: map[string]string{
+				"account":    attempt.Borrower.Hex(),
+				"market":     attempt.RepayMarket,
+				"collateral": attempt.CollateralMarket,
+				"tx":         attempt.TxHash.Hex(),
+				"block":      fmt.Sprint(outcome.Block),
+				"profit_usd": fmt.Sprintf("%.2f", outcome.ProfitUSD),
+			},
+		})
+	}
+	if outcome.GasPrice != nil {
+		gasCost := new(big.Int).Mul(outcome.GasPrice, new(big.Int).SetUint64(outcome.GasUsed))
+		eth, _ := new(big.Float).Quo(new(big.Float).SetInt(gasCost), new(big.Float).SetInt(expScale)).Float64()
+		o.metrics.GasSpentETH.Add(eth)
+	}
+}
+
+// updatePnLMetrics sets PnL gauges from the store ledger
+func (o *liqbot) updatePnLMetrics(st *store.Store) {
+	if st == nil {
+		return
+	}
+
+	ledger, err := st.Ledger()
+	if err != nil {
+		level.Error(o.logger).Log("msg", "❌ Error reading PnL ledger", "err", err)
+		return
+	}
+
+	o.metrics.RealizedProfitUSD.Set(ledger.Total.ProfitUSD)
+	for _, entry := range ledger.ByMarket {
+		o.metrics.PnLLiquidations.With("market", entry.Key).Set(float64(entry.Liquidations))
+		o.metrics.PnLReverted.With("market", entry.Key).Set(float64(entry.Reverted))
+		o.metrics.PnLRepayUSD.With("market", entry.Key).Set(entry.RepayUSD)
+		o.metrics.PnLSeizedUSD.With("market", entry.Key).Set(entry.SeizedUSD)
+		o.metrics.PnLGasUSD.With("market", entry.Key).Set(entry.GasUSD)
+		o.metrics.PnLProfitUSD.With("market", entry.Key).Set(entry.ProfitUSD)
+	}
+}
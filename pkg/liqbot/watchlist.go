@@ -0,0 +1,96 @@
+package liqbot
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/config"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/subgraph"
+)
+
+// watchlist holds the accounts close to liquidation, checked on every block,
+// the other accounts are only checked by full scans
+type watchlist struct {
+	mu       sync.Mutex
+	accounts map[common.Address]subgraph.Account
+	// lastFull is the time of the last full scan with a subgraph fetch
+	lastFull time.Time
+	// block is the last block the watchlist was checked at
+	block uint64
+}
+
+// fullDue reports whether the next scan checks every account
+func (w *watchlist) fullDue(cfg config.Config, now time.Time) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return cfg.Scan().WatchBand == 0 || w.lastFull.IsZero() || now.Sub(w.lastFull) >= cfg.Scan().FullScanInterval
+}
+
+// newBlock reports whether the watchlist was not checked at block yet
+func (w *watchlist) newBlock(block uint64) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return block > w.block
+}
+
+// list returns the watched accounts
+func (w *watchlist) list() []subgraph.Account {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	accounts := make([]subgraph.Account, 0, len(w.accounts))
+	for _, a := range w.accounts {
+		accounts = append(accounts, a)
+	}
+	return accounts
+}
+
+// set replaces the watched accounts after a scan at block, full when every account was checked
+func (w *watchlist) set(accounts map[common.Address]subgraph.Account, block uint64, full bool, now time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.accounts = accounts
+	w.block = block
+	if full {
+		w.lastFull = now
+	}
+}
+
+// watches reports whether the evaluated account belongs on the watchlist: in shortfall or close to it,
+// approaching shortfall with accrued interest or not evaluated
+func watches(cfg config.Config, e *evaluation) bool {
+	if e.err != nil || e.position.crossing != 0 {
+		return true
+	}
+	return e.health < 1+cfg.Scan().WatchBand
+}
+
+// pollHeads sends the head block number to heads every time it changes, the head is polled every interval
+// while the watchlist is on. A head is dropped when the scan has not taken the previous one, scans read the
+// latest head themselves.
+func (o *liqbot) pollHeads(ctx context.Context, src *sources, interval time.Duration, heads chan<- uint64) {
+	var last uint64
+	for {
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return
+		}
+		if o.getConfig().Scan().WatchBand == 0 {
+			continue
+		}
+
+		// scans report the node failures
+		head, err := src.client.BlockNumber(ctx)
+		if err != nil || head <= last {
+			continue
+		}
+		last = head
+
+		select {
+		case heads <- head:
+		default:
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package liqbot
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/config"
+)
+
+// headBackend reports head as the block number
+type headBackend struct {
+	Backend
+	head uint64
+}
+
+func (b *headBackend) BlockNumber(ctx context.Context) (uint64, error) {
+	return atomic.LoadUint64(&b.head), nil
+}
+
+// watchConfig enables the watchlist
+type watchConfig struct {
+	config.Config
+}
+
+func (watchConfig) Scan() config.Scan {
+	return config.Scan{WatchBand: 0.1, FullScanInterval: time.Hour}
+}
+
+func TestPollHeads(t *testing.T) {
+	o := &liqbot{}
+	o.cfg.Store(config.Config(watchConfig{}))
+	backend := &headBackend{head: 100}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	heads := make(chan uint64, 1)
+	go o.pollHeads(ctx, &sources{client: backend}, 10*time.Millisecond, heads)
+
+	next := func() (uint64, bool) {
+		select {
+		case head := <-heads:
+			return head, true
+		case <-time.After(200 * time.Millisecond):
+			return 0, false
+		}
+	}
+
+	if head, ok := next(); !ok || head != 100 {
+		t.Fatalf("head %d, want 100", head)
+	}
+	if head, ok := next(); ok {
+		t.Fatalf("head %d sent again without a new block", head)
+	}
+	atomic.StoreUint64(&backend.head, 101)
+	if head, ok := next(); !ok || head != 101 {
+		t.Fatalf("head %d, want 101", head)
+	}
+}
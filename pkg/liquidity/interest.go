@@ -0,0 +1,88 @@
+package liquidity
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Accrual is the interest state of a market, read from CToken borrowRatePerBlock, borrowIndex and accrualBlockNumber
+type Accrual struct {
+	BorrowRatePerBlock *big.Int
+	BorrowIndex        *big.Int
+	AccrualBlock       uint64
+}
+
+// BorrowIndexAt mirrors CToken.accrueInterest: the borrow index once interest is accrued at block.
+// The rate is the one read, it changes with the market utilization at the next accrual.
+func (a *Accrual) BorrowIndexAt(block uint64) *big.Int {
+	if block <= a.AccrualBlock {
+		return new(big.Int).Set(a.BorrowIndex)
+	}
+	simpleInterestFactor := new(big.Int).Mul(a.BorrowRatePerBlock, new(big.Int).SetUint64(block-a.AccrualBlock))
+	return mulScalarTruncateAdd(simpleInterestFactor, a.BorrowIndex, a.BorrowIndex)
+}
+
+// BorrowBalanceAt projects balance, a borrowBalanceStored read while the market borrow index was index,
+// to block. The account principal and interest index are not exposed, so the projection scales the
+// stored balance and can differ from CToken.borrowBalanceStored by the truncation of the stored balance.
+func (a *Accrual) BorrowBalanceAt(balance, index *big.Int, block uint64) *big.Int {
+	if balance.Sign() == 0 || index == nil || index.Sign() == 0 {
+		return new(big.Int).Set(balance)
+	}
+	projected := new(big.Int).Mul(balance, a.BorrowIndexAt(block))
+	return projected.Quo(projected, index)
+}
+
+// Project returns account with the borrow balances of markets in accruals projected to block, BorrowIndex
+// of the snapshots is the index their balance was stored with, markets without accrual are kept
+func Project(account *Account, accruals map[common.Address]*Accrual, block uint64) *Account {
+	projected := &Account{
+		AssetsIn:  account.AssetsIn,
+		Snapshots: make(map[common.Address]Snapshot, len(account.Snapshots)),
+	}
+	for market, snapshot := range account.Snapshots {
+		if accrual := accruals[market]; accrual != nil {
+			snapshot.BorrowBalance = accrual.BorrowBalanceAt(snapshot.BorrowBalance, snapshot.BorrowIndex, block)
+		}
+		projected.Snapshots[market] = snapshot
+	}
+	return projected
+}
+
+// ShortfallBlock returns the first block after from, up to to, at which account solvent at from is in shortfall
+// with its borrows projected, and false when it stays solvent until to. Liquidity only decreases with accrued
+// interest, the block is searched by bisection.
+func ShortfallBlock(account *Account, markets map[common.Address]Market, accruals map[common.Address]*Accrual, from, to uint64) (uint64, bool, error) {
+	inShortfall := func(block uint64) (bool, error) {
+		result, err := AccountLiquidity(Project(account, accruals, block), markets)
+		if err != nil {
+			return false, err
+		}
+		return result.Shortfall.Sign() > 0, nil
+	}
+
+	if to <= from {
+		return 0, false, nil
+	}
+	last, err := inShortfall(to)
+	if err != nil || !last {
+		return 0, false, err
+	}
+
+	// invariant: solvent at low, in shortfall at high
+	low, high := from, to
+	for high-low > 1 {
+		mid := low + (high-low)/2
+		shortfall, err := inShortfall(mid)
+		if err != nil {
+			return 0, false, err
+		}
+		if shortfall {
+			high = mid
+		} else {
+			low = mid
+		}
+	}
+	return high, true, nil
+}
@@ -0,0 +1,171 @@
+package liquidity_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/contracts"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/liquidity"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/simulated"
+)
+
+// borrowRate is the cUSDC borrow rate per block, under the CToken maximum, about 0.05% every 100 blocks
+var borrowRate = big.NewInt(4567891234567)
+
+// newBorrowHarness creates harness with the borrower holding 1000 DAI and 700 USDC borrowed at borrowRate
+func newBorrowHarness(t *testing.T) *simulated.Harness {
+	h, err := simulated.NewHarness()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { h.Backend.Close() })
+
+	if err := h.OpenBorrow(); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.SetBorrowRate(h.CUSDC, borrowRate); err != nil {
+		t.Fatal(err)
+	}
+	return h
+}
+
+func readAccrual(t *testing.T, h *simulated.Harness, market common.Address) *liquidity.Accrual {
+	opts := &bind.CallOpts{}
+	ctoken, err := contracts.NewCTokenCaller(market, h.Backend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rate, err := ctoken.BorrowRatePerBlock(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	index, err := ctoken.BorrowIndex(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	accrualBlock, err := ctoken.AccrualBlockNumber(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &liquidity.Accrual{BorrowRatePerBlock: rate, BorrowIndex: index, AccrualBlock: accrualBlock.Uint64()}
+}
+
+func headBlock(t *testing.T, h *simulated.Harness) uint64 {
+	head, err := h.Backend.BlockNumber(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return head
+}
+
+func TestBorrowIndexAt(t *testing.T) {
+	h := newBorrowHarness(t)
+	ctoken, err := contracts.NewCTokenCaller(h.CUSDC, h.Backend)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	accrual := readAccrual(t, h, h.CUSDC)
+	if got := accrual.BorrowIndexAt(accrual.AccrualBlock); got.Cmp(accrual.BorrowIndex) != 0 {
+		t.Errorf("index at the accrual block %s, want %s", got, accrual.BorrowIndex)
+	}
+	if got := accrual.BorrowIndexAt(accrual.AccrualBlock - 1); got.Cmp(accrual.BorrowIndex) != 0 {
+		t.Errorf("index before the accrual block %s, want %s", got, accrual.BorrowIndex)
+	}
+
+	for _, blocks := range []int{1, 2, 13, 100} {
+		account, _ := readAccount(t, h, h.Borrower())
+		snapshot := account.Snapshots[h.CUSDC]
+		snapshot.BorrowIndex = accrual.BorrowIndex
+		account.Snapshots[h.CUSDC] = snapshot
+
+		// the accrual is the next block after the blocks mined
+		target := headBlock(t, h) + uint64(blocks)
+		h.Mine(blocks - 1)
+		if err := h.AccrueInterest(h.CUSDC); err != nil {
+			t.Fatal(err)
+		}
+
+		accrued := readAccrual(t, h, h.CUSDC)
+		if accrued.AccrualBlock != target {
+			t.Fatalf("accrued at %d, want %d", accrued.AccrualBlock, target)
+		}
+		if want := accrual.BorrowIndexAt(target); accrued.BorrowIndex.Cmp(want) != 0 {
+			t.Errorf("%d blocks: borrow index %s, projected %s", blocks, accrued.BorrowIndex, want)
+		}
+
+		stored, err := ctoken.BorrowBalanceStored(&bind.CallOpts{}, h.Borrower())
+		if err != nil {
+			t.Fatal(err)
+		}
+		projected := liquidity.Project(account, map[common.Address]*liquidity.Accrual{h.CUSDC: accrual}, target)
+		// scaling the truncated stored balance loses less than the index growth plus one wei
+		diff := new(big.Int).Sub(stored, projected.Snapshots[h.CUSDC].BorrowBalance)
+		if diff.Sign() < 0 || diff.Cmp(big.NewInt(2)) > 0 {
+			t.Errorf("%d blocks: projected borrow %s, stored %s", blocks, projected.Snapshots[h.CUSDC].BorrowBalance, stored)
+		}
+		if got := projected.Snapshots[h.CDAI]; got.BorrowBalance.Cmp(account.Snapshots[h.CDAI].BorrowBalance) != 0 {
+			t.Errorf("market without accrual projected to %s", got.BorrowBalance)
+		}
+
+		accrual = accrued
+	}
+}
+
+func TestShortfallBlock(t *testing.T) {
+	h := newBorrowHarness(t)
+	// 700.5 USD of weighted collateral, the 700 USD borrowed crosses it after about 156 blocks
+	if err := h.SetPrice(h.CDAI, 0.934); err != nil {
+		t.Fatal(err)
+	}
+
+	accrual := readAccrual(t, h, h.CUSDC)
+	accruals := map[common.Address]*liquidity.Accrual{h.CUSDC: accrual}
+	account, markets := readAccount(t, h, h.Borrower())
+	snapshot := account.Snapshots[h.CUSDC]
+	snapshot.BorrowIndex = accrual.BorrowIndex
+	account.Snapshots[h.CUSDC] = snapshot
+
+	from := headBlock(t, h)
+	crossing, ok, err := liquidity.ShortfallBlock(account, markets, accruals, from, from+1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("no shortfall within 1000 blocks")
+	}
+	if crossing <= from+2 {
+		t.Fatalf("shortfall at %d, from %d", crossing, from)
+	}
+
+	if _, ok, _ := liquidity.ShortfallBlock(account, markets, accruals, from, crossing-1); ok {
+		t.Error("shortfall found before the crossing block")
+	}
+	if _, ok, _ := liquidity.ShortfallBlock(account, markets, accruals, from, from); ok {
+		t.Error("shortfall found in an empty range")
+	}
+	if block, ok, _ := liquidity.ShortfallBlock(account, markets, accruals, from, crossing); !ok || block != crossing {
+		t.Errorf("range ending at the crossing: %d %v, want %d", block, ok, crossing)
+	}
+
+	// accrue at the block before the crossing and at the crossing, the comptroller reads stored balances
+	h.Mine(int(crossing - 2 - from))
+	for _, block := range []uint64{crossing - 1, crossing} {
+		if err := h.AccrueInterest(h.CUSDC); err != nil {
+			t.Fatal(err)
+		}
+		if accrued := readAccrual(t, h, h.CUSDC).AccrualBlock; accrued != block {
+			t.Fatalf("accrued at %d, want %d", accrued, block)
+		}
+		shortfall, err := h.Shortfall(h.Borrower())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := shortfall.Sign() > 0, block == crossing; got != want {
+			t.Errorf("block %d: comptroller shortfall %s, want shortfall %v", block, shortfall, want)
+		}
+	}
+}
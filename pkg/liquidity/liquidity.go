@@ -0,0 +1,137 @@
+// Package liquidity computes Compound v2 account liquidity locally, with the Comptroller exp-scale math,
+// so accounts can be evaluated on every price change without calling the chain
+package liquidity
+
+import (
+	"errors"
+	"math"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Market holds the comptroller and oracle values of a market
+type Market struct {
+	// CollateralFactor is the comptroller collateral factor mantissa
+	CollateralFactor *big.Int
+	// Price is the oracle underlying price mantissa, scaled by 1e(36 - underlying decimals)
+	Price *big.Int
+}
+
+// Snapshot is the position of an account in a market, as returned by CToken.getAccountSnapshot
+type Snapshot struct {
+	CTokenBalance *big.Int
+	BorrowBalance *big.Int
+	ExchangeRate  *big.Int
+	// BorrowIndex is the market borrow index when the snapshot was read, nil when unknown, see Project
+	BorrowIndex *big.Int
+}
+
+// Account is the position of an account in the markets it entered, Comptroller.getAssetsIn
+type Account struct {
+	AssetsIn  []common.Address
+	Snapshots map[common.Address]Snapshot
+}
+
+// Result holds the sums Comptroller compares, in USD mantissa
+type Result struct {
+	// SumCollateral is the collateral value weighted by collateral factors
+	SumCollateral *big.Int
+	// SumBorrowPlusEffects is the borrowed value with the hypothetical redeem and borrow
+	SumBorrowPlusEffects *big.Int
+	Liquidity            *big.Int
+	Shortfall            *big.Int
+}
+
+// Health returns weighted collateral over borrows, under 1 when the account can be liquidated, +Inf without borrows
+func (r *Result) Health() float64 {
+	if r.SumBorrowPlusEffects.Sign() == 0 {
+		return math.Inf(1)
+	}
+	health, _ := new(big.Float).Quo(new(big.Float).SetInt(r.SumCollateral), new(big.Float).SetInt(r.SumBorrowPlusEffects)).Float64()
+	return health
+}
+
+var (
+	// ErrPrice is Comptroller PRICE_ERROR, an entered market has no oracle price
+	ErrPrice = errors.New("price error")
+	// ErrUnknownMarket is returned for an entered market without market values or snapshot
+	ErrUnknownMarket = errors.New("unknown market")
+)
+
+// AccountLiquidity mirrors Comptroller.getAccountLiquidity
+func AccountLiquidity(account *Account, markets map[common.Address]Market) (*Result, error) {
+	return HypotheticalAccountLiquidity(account, markets, common.Address{}, new(big.Int), new(big.Int))
+}
+
+// HypotheticalAccountLiquidity mirrors Comptroller.getHypotheticalAccountLiquidity: the liquidity or shortfall
+// of account after redeeming redeemTokens and borrowing borrowAmount of market modify
+func HypotheticalAccountLiquidity(account *Account, markets map[common.Address]Market, modify common.Address, redeemTokens, borrowAmount *big.Int) (*Result, error) {
+	sumCollateral := new(big.Int)
+	sumBorrowPlusEffects := new(big.Int)
+
+	for _, asset := range account.AssetsIn {
+		market, ok := markets[asset]
+		snapshot, found := account.Snapshots[asset]
+		if !ok || !found || market.CollateralFactor == nil {
+			return nil, ErrUnknownMarket
+		}
+		if market.Price == nil || market.Price.Sign() == 0 {
+			return nil, ErrPrice
+		}
+
+		// tokens to denom: collateral factor * exchange rate * price, each product truncated to exp scale
+		tokensToDenom := mulExp(mulExp(market.CollateralFactor, snapshot.ExchangeRate), market.Price)
+
+		sumCollateral = mulScalarTruncateAdd(tokensToDenom, snapshot.CTokenBalance, sumCollateral)
+		sumBorrowPlusEffects = mulScalarTruncateAdd(market.Price, snapshot.BorrowBalance, sumBorrowPlusEffects)
+
+		if asset == modify {
+			sumBorrowPlusEffects = mulScalarTruncateAdd(tokensToDenom, redeemTokens, sumBorrowPlusEffects)
+			sumBorrowPlusEffects = mulScalarTruncateAdd(market.Price, borrowAmount, sumBorrowPlusEffects)
+		}
+	}
+
+	result := &Result{
+		SumCollateral:        sumCollateral,
+		SumBorrowPlusEffects: sumBorrowPlusEffects,
+		Liquidity:            new(big.Int),
+		Shortfall:            new(big.Int),
+	}
+	if sumCollateral.Cmp(sumBorrowPlusEffects) > 0 {
+		result.Liquidity.Sub(sumCollateral, sumBorrowPlusEffects)
+	} else {
+		result.Shortfall.Sub(sumBorrowPlusEffects, sumCollateral)
+	}
+	return result, nil
+}
+
+// SeizeTokens mirrors Comptroller.liquidateCalculateSeizeTokens: the collateral cTokens seized for repayAmount
+// of the borrowed underlying, prices are oracle prices of the borrowed and collateral markets
+func SeizeTokens(repayAmount, incentive, priceBorrowed, priceCollateral, exchangeRate *big.Int) (*big.Int, error) {
+	if priceBorrowed == nil || priceBorrowed.Sign() == 0 || priceCollateral == nil || priceCollateral.Sign() == 0 {
+		return nil, ErrPrice
+	}
+	numerator := mulExp(incentive, priceBorrowed)
+	denominator := mulExp(priceCollateral, exchangeRate)
+	if denominator.Sign() == 0 {
+		return nil, errors.New("zero collateral exchange rate")
+	}
+	ratio := new(big.Int).Mul(numerator, expScale)
+	ratio.Quo(ratio, denominator)
+	return mulExp(ratio, repayAmount), nil
+}
+
+// mulExp returns a * b / 1e18, Exponential mul_(Exp, Exp)
+func mulExp(a, b *big.Int) *big.Int {
+	product := new(big.Int).Mul(a, b)
+	return product.Quo(product, expScale)
+}
+
+// mulScalarTruncateAdd returns a * scalar / 1e18 + addend, Exponential mul_ScalarTruncateAddUInt
+func mulScalarTruncateAdd(a, scalar, addend *big.Int) *big.Int {
+	product := mulExp(a, scalar)
+	return product.Add(product, addend)
+}
+
+var expScale = big.NewInt(1e18)
@@ -0,0 +1,337 @@
+package liquidity_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/contracts"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/liquidity"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/simulated"
+)
+
+// newHarness creates harness with the borrower in both markets: 1000 DAI and 333.333333 USDC supplied,
+// 700 USDC and 123.456789012345678901 DAI borrowed. A few blocks of interest leave exchange rates and
+// borrow balances off round numbers.
+func newHarness(t *testing.T) *simulated.Harness {
+	h, err := simulated.NewHarness()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { h.Backend.Close() })
+
+	if err := h.OpenBorrow(); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Supply(h.BorrowerKey, h.CUSDC, big.NewInt(333333333)); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Borrow(h.BorrowerKey, h.CUSDC, h.CDAI, mantissa("123456789012345678901")); err != nil {
+		t.Fatal(err)
+	}
+
+	rates := map[common.Address]*big.Int{h.CUSDC: big.NewInt(1234567891), h.CDAI: big.NewInt(987654321)}
+	for market, rate := range rates {
+		if err := h.SetBorrowRate(market, rate); err != nil {
+			t.Fatal(err)
+		}
+	}
+	h.Mine(7)
+	for market := range rates {
+		if err := h.SetBorrowRate(market, new(big.Int)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return h
+}
+
+// readAccount reads account and the market values of the markets it entered from the harness
+func readAccount(t *testing.T, h *simulated.Harness, account common.Address) (*liquidity.Account, map[common.Address]liquidity.Market) {
+	opts := &bind.CallOpts{}
+	comptroller, err := contracts.NewComptrollerCoreCaller(h.Comptroller, h.Backend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	oracle, err := contracts.NewPriceOracleCaller(h.Oracle, h.Backend)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assetsIn, err := comptroller.GetAssetsIn(opts, account)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := &liquidity.Account{AssetsIn: assetsIn, Snapshots: map[common.Address]liquidity.Snapshot{}}
+	markets := map[common.Address]liquidity.Market{}
+	for _, asset := range assetsIn {
+		ctoken, err := contracts.NewCTokenCaller(asset, h.Backend)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, balance, borrowBalance, exchangeRate, err := ctoken.GetAccountSnapshot(opts, account)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result.Snapshots[asset] = liquidity.Snapshot{CTokenBalance: balance, BorrowBalance: borrowBalance, ExchangeRate: exchangeRate}
+
+		market, err := comptroller.Markets(opts, asset)
+		if err != nil {
+			t.Fatal(err)
+		}
+		price, err := oracle.GetUnderlyingPrice(opts, asset)
+		if err != nil {
+			t.Fatal(err)
+		}
+		markets[asset] = liquidity.Market{CollateralFactor: market.CollateralFactorMantissa, Price: price}
+	}
+	return result, markets
+}
+
+// marketState is the oracle prices and collateral factors of both harness markets, as mantissas
+type marketState struct {
+	name       string
+	daiPrice   *big.Int
+	usdcPrice  *big.Int
+	daiFactor  *big.Int
+	usdcFactor *big.Int
+	shortfall  bool
+}
+
+// marketStates covers the borrower solvent and in shortfall, with prices and factors truncating at every product
+var marketStates = []marketState{
+	{
+		name:       "par",
+		daiPrice:   mantissa("1000000000000000000"),
+		usdcPrice:  mantissa("1000000000000000000000000000000"),
+		daiFactor:  mantissa("750000000000000000"),
+		usdcFactor: mantissa("750000000000000000"),
+	},
+	{
+		name:       "prices one off",
+		daiPrice:   mantissa("999999999999999999"),
+		usdcPrice:  mantissa("1000000000000000000000000000001"),
+		daiFactor:  mantissa("750000000000000000"),
+		usdcFactor: mantissa("750000000000000000"),
+	},
+	{
+		name:       "collateral drop",
+		daiPrice:   mantissa("600000000000000000"),
+		usdcPrice:  mantissa("1000000000000000000000000000000"),
+		daiFactor:  mantissa("750000000000000000"),
+		usdcFactor: mantissa("750000000000000000"),
+		shortfall:  true,
+	},
+	{
+		name:       "one collateral factor zero",
+		daiPrice:   mantissa("1000000000000000000"),
+		usdcPrice:  mantissa("1000000000000000000000000000000"),
+		daiFactor:  mantissa("900000000000000000"),
+		usdcFactor: new(big.Int),
+	},
+	{
+		name:       "odd factors and prices",
+		daiPrice:   mantissa("1234567890123456789"),
+		usdcPrice:  mantissa("999876543210987654321098765432"),
+		daiFactor:  mantissa("333333333333333333"),
+		usdcFactor: mantissa("899999999999999999"),
+		shortfall:  true,
+	},
+	{
+		name:       "both prices move",
+		daiPrice:   mantissa("850000000000000001"),
+		usdcPrice:  mantissa("1300000000000000000000000000007"),
+		daiFactor:  mantissa("750000000000000001"),
+		usdcFactor: mantissa("700000000000000000"),
+		shortfall:  true,
+	},
+}
+
+func (s *marketState) apply(t *testing.T, h *simulated.Harness) {
+	for _, step := range []struct {
+		market common.Address
+		price  *big.Int
+		factor *big.Int
+	}{
+		{h.CDAI, s.daiPrice, s.daiFactor},
+		{h.CUSDC, s.usdcPrice, s.usdcFactor},
+	} {
+		if err := h.SetPriceMantissa(step.market, step.price); err != nil {
+			t.Fatal(err)
+		}
+		if err := h.SetCollateralFactor(step.market, step.factor); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestAccountLiquidity(t *testing.T) {
+	h := newHarness(t)
+	comptroller, err := contracts.NewComptrollerCoreCaller(h.Comptroller, h.Backend)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, state := range marketStates {
+		t.Run(state.name, func(t *testing.T) {
+			state.apply(t, h)
+
+			code, wantLiquidity, wantShortfall, err := comptroller.GetAccountLiquidity(&bind.CallOpts{}, h.Borrower())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if code.Sign() != 0 {
+				t.Fatalf("comptroller error %s", code)
+			}
+
+			account, markets := readAccount(t, h, h.Borrower())
+			result, err := liquidity.AccountLiquidity(account, markets)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Liquidity.Cmp(wantLiquidity) != 0 || result.Shortfall.Cmp(wantShortfall) != 0 {
+				t.Errorf("liquidity %s shortfall %s, comptroller %s %s", result.Liquidity, result.Shortfall, wantLiquidity, wantShortfall)
+			}
+			if got := result.Shortfall.Sign() > 0; got != state.shortfall {
+				t.Errorf("shortfall %v, want %v", got, state.shortfall)
+			}
+			if got := result.Health() < 1; got != state.shortfall {
+				t.Errorf("health %v with shortfall %v", result.Health(), state.shortfall)
+			}
+		})
+	}
+
+	t.Run("no price", func(t *testing.T) {
+		if err := h.SetPriceMantissa(h.CDAI, new(big.Int)); err != nil {
+			t.Fatal(err)
+		}
+		code, _, _, err := comptroller.GetAccountLiquidity(&bind.CallOpts{}, h.Borrower())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if code.Sign() == 0 {
+			t.Fatal("comptroller accepted a zero price")
+		}
+
+		account, markets := readAccount(t, h, h.Borrower())
+		if _, err := liquidity.AccountLiquidity(account, markets); err != liquidity.ErrPrice {
+			t.Errorf("got %v, want %v", err, liquidity.ErrPrice)
+		}
+	})
+}
+
+func TestHypotheticalAccountLiquidity(t *testing.T) {
+	h := newHarness(t)
+	comptroller, err := contracts.NewComptrollerCoreCaller(h.Comptroller, h.Backend)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	effects := []struct {
+		name         string
+		modify       common.Address
+		redeemTokens *big.Int
+		borrowAmount *big.Int
+	}{
+		{"redeem one cDAI", h.CDAI, big.NewInt(1e8), new(big.Int)},
+		{"borrow one DAI wei", h.CDAI, new(big.Int), big.NewInt(1)},
+		{"redeem and borrow USDC", h.CUSDC, mantissa("12345678901"), big.NewInt(1234567)},
+		{"redeem one cUSDC wei", h.CUSDC, big.NewInt(1), new(big.Int)},
+		{"borrow 100 USDC", h.CUSDC, new(big.Int), big.NewInt(100000000)},
+		{"market not entered", h.DAI, mantissa("1000000000000000000000"), mantissa("1000000000000000000000")},
+	}
+
+	for _, state := range marketStates {
+		state.apply(t, h)
+		account, markets := readAccount(t, h, h.Borrower())
+
+		for _, effect := range effects {
+			t.Run(state.name+"/"+effect.name, func(t *testing.T) {
+				code, wantLiquidity, wantShortfall, err := comptroller.GetHypotheticalAccountLiquidity(&bind.CallOpts{},
+					h.Borrower(), effect.modify, effect.redeemTokens, effect.borrowAmount)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if code.Sign() != 0 {
+					t.Fatalf("comptroller error %s", code)
+				}
+
+				result, err := liquidity.HypotheticalAccountLiquidity(account, markets, effect.modify, effect.redeemTokens, effect.borrowAmount)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if result.Liquidity.Cmp(wantLiquidity) != 0 || result.Shortfall.Cmp(wantShortfall) != 0 {
+					t.Errorf("liquidity %s shortfall %s, comptroller %s %s", result.Liquidity, result.Shortfall, wantLiquidity, wantShortfall)
+				}
+			})
+		}
+	}
+}
+
+func TestSeizeTokens(t *testing.T) {
+	h := newHarness(t)
+	opts := &bind.CallOpts{}
+	comptroller, err := contracts.NewComptrollerCoreCaller(h.Comptroller, h.Backend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	incentive, err := comptroller.LiquidationIncentiveMantissa(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pairs := []struct {
+		name       string
+		borrowed   common.Address
+		collateral common.Address
+	}{
+		{"USDC for cDAI", h.CUSDC, h.CDAI},
+		{"DAI for cUSDC", h.CDAI, h.CUSDC},
+		{"USDC for cUSDC", h.CUSDC, h.CUSDC},
+	}
+	repayAmounts := []*big.Int{big.NewInt(1), big.NewInt(7), big.NewInt(123456789), mantissa("350000000000000000001")}
+
+	for _, state := range marketStates {
+		state.apply(t, h)
+		_, markets := readAccount(t, h, h.Borrower())
+
+		for _, pair := range pairs {
+			t.Run(state.name+"/"+pair.name, func(t *testing.T) {
+				ctoken, err := contracts.NewCTokenCaller(pair.collateral, h.Backend)
+				if err != nil {
+					t.Fatal(err)
+				}
+				exchangeRate, err := ctoken.ExchangeRateStored(opts)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				for _, repay := range repayAmounts {
+					code, want, err := comptroller.LiquidateCalculateSeizeTokens(opts, pair.borrowed, pair.collateral, repay)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if code.Sign() != 0 {
+						t.Fatalf("comptroller error %s", code)
+					}
+
+					got, err := liquidity.SeizeTokens(repay, incentive, markets[pair.borrowed].Price, markets[pair.collateral].Price, exchangeRate)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if got.Cmp(want) != 0 {
+						t.Errorf("repay %s: seize %s, comptroller %s", repay, got, want)
+					}
+				}
+			})
+		}
+	}
+}
+
+func mantissa(s string) *big.Int {
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		panic("invalid number " + s)
+	}
+	return n
+}
@@ -0,0 +1,135 @@
+package metrics
+
+import (
+	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/discard"
+	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+)
+
+const namespace = "liqbot"
+
+// Metrics represents bot metrics
+type Metrics struct {
+	// ScanDuration is the duration of a scan in seconds
+	ScanDuration metrics.Histogram
+	// AccountsScanned counts borrowers returned by discovery
+	AccountsScanned metrics.Counter
+	// Candidates counts profitable liquidations found
+	Candidates metrics.Counter
+	// LiquidationsSent, Mined and Reverted count liquidation transactions, mined ones include reverted ones
+	LiquidationsSent     metrics.Counter
+	LiquidationsMined    metrics.Counter
+	LiquidationsReverted metrics.Counter
+	// GasSpentETH counts gas paid by mined liquidations
+	GasSpentETH metrics.Counter
+	// RealizedProfitUSD is the total realized profit from the store ledger
+	RealizedProfitUSD metrics.Gauge
+	// AccountsApproaching is the number of solvent borrowers projected in shortfall within the lookahead
+	AccountsApproaching metrics.Gauge
+	// WatchlistAccounts is the number of accounts checked on every block
+	WatchlistAccounts metrics.Gauge
+	// InventoryUSD is the repay underlying the liquidator holds and approved, InventoryShortfallUSD what the last
+	// scan liquidations needed over it, labelled by "market"
+	InventoryUSD          metrics.Gauge
+	InventoryShortfallUSD metrics.Gauge
+	// SubgraphLag is the number of blocks the subgraph is behind the chain head
+	SubgraphLag metrics.Gauge
+	// RPCErrors counts failed RPC calls labelled by "method"
+	RPCErrors metrics.Counter
+
+	// realized PnL per collateral market, from the store ledger
+	PnLLiquidations metrics.Gauge
+	PnLReverted     metrics.Gauge
+	PnLRepayUSD     metrics.Gauge
+	PnLSeizedUSD    metrics.Gauge
+	PnLGasUSD       metrics.Gauge
+	PnLProfitUSD    metrics.Gauge
+}
+
+// NewPrometheus creates metrics registered with the default Prometheus registry, it must be called once
+func NewPrometheus() *Metrics {
+	pnl := func(name, help string) metrics.Gauge {
+		return kitprometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "pnl",
+			Name:      name,
+			Help:      help,
+		}, []string{"market"})
+	}
+	counter := func(name, help string, labels ...string) metrics.Counter {
+		c := kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      name,
+			Help:      help,
+		}, labels)
+		// unlabelled counters are exported from the start so rate() alerts see them
+		if len(labels) == 0 {
+			c.Add(0)
+		}
+		return c
+	}
+	gauge := func(name, help string, labels ...string) metrics.Gauge {
+		return kitprometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      name,
+			Help:      help,
+		}, labels)
+	}
+
+	return &Metrics{
+		ScanDuration: kitprometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "scan_duration_seconds",
+			Help:      "Duration of a scan.",
+			Buckets:   []float64{0.5, 1, 2, 5, 10, 20, 30, 60, 120},
+		}, []string{}),
+		AccountsScanned:       counter("accounts_scanned_total", "Borrowers returned by discovery."),
+		Candidates:            counter("candidates_total", "Profitable liquidations found."),
+		LiquidationsSent:      counter("liquidations_sent_total", "Liquidation transactions sent."),
+		LiquidationsMined:     counter("liquidations_mined_total", "Liquidation transactions mined, reverted ones included."),
+		LiquidationsReverted:  counter("liquidations_reverted_total", "Liquidation transactions mined and reverted."),
+		GasSpentETH:           counter("gas_spent_eth_total", "Gas paid by mined liquidations in ETH."),
+		RealizedProfitUSD:     gauge("realized_profit_usd", "Realized profit of mined liquidations in USD."),
+		SubgraphLag:           gauge("subgraph_lag_blocks", "Blocks the subgraph is behind the chain head."),
+		WatchlistAccounts:     gauge("watchlist_accounts", "Accounts close to liquidation checked on every block."),
+		AccountsApproaching:   gauge("accounts_approaching_shortfall", "Solvent borrowers projected in shortfall within the lookahead."),
+		InventoryUSD:          gauge("inventory_usd", "Repay underlying held and approved in USD by market.", "market"),
+		InventoryShortfallUSD: gauge("inventory_shortfall_usd", "Repay underlying needed over the inventory in USD by market.", "market"),
+		RPCErrors:             counter("rpc_errors_total", "Failed RPC calls by method.", "method"),
+
+		PnLLiquidations: pnl("liquidations", "Mined liquidations by collateral market."),
+		PnLReverted:     pnl("reverted", "Reverted liquidations by collateral market."),
+		PnLRepayUSD:     pnl("repay_usd", "Repaid borrows in USD by collateral market."),
+		PnLSeizedUSD:    pnl("seized_usd", "Seized collateral in USD by collateral market."),
+		PnLGasUSD:       pnl("gas_usd", "Gas spent in USD by collateral market."),
+		PnLProfitUSD:    pnl("profit_usd", "Realized profit in USD by collateral market."),
+	}
+}
+
+// NewDiscard creates metrics that are not recorded
+func NewDiscard() *Metrics {
+	return &Metrics{
+		ScanDuration:          discard.NewHistogram(),
+		AccountsScanned:       discard.NewCounter(),
+		Candidates:            discard.NewCounter(),
+		LiquidationsSent:      discard.NewCounter(),
+		LiquidationsMined:     discard.NewCounter(),
+		LiquidationsReverted:  discard.NewCounter(),
+		GasSpentETH:           discard.NewCounter(),
+		RealizedProfitUSD:     discard.NewGauge(),
+		SubgraphLag:           discard.NewGauge(),
+		AccountsApproaching:   discard.NewGauge(),
+		WatchlistAccounts:     discard.NewGauge(),
+		InventoryUSD:          discard.NewGauge(),
+		InventoryShortfallUSD: discard.NewGauge(),
+		RPCErrors:             discard.NewCounter(),
+
+		PnLLiquidations: discard.NewGauge(),
+		PnLReverted:     discard.NewGauge(),
+		PnLRepayUSD:     discard.NewGauge(),
+		PnLSeizedUSD:    discard.NewGauge(),
+		PnLGasUSD:       discard.NewGauge(),
+		PnLProfitUSD:    discard.NewGauge(),
+	}
+}
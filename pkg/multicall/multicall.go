@@ -0,0 +1,247 @@
+// Package multicall batches contract reads into Multicall3 aggregate3 calls
+package multicall
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/contracts"
+)
+
+// Address is the Multicall3 deployment, the same on mainnet and most EVM chains
+var Address = common.HexToAddress("0xcA11bde05977b3631167028862bE2a173976CA11")
+
+// Call represents a contract read
+type Call struct {
+	Target common.Address
+	ABI    *abi.ABI
+	Method string
+	Args   []interface{}
+}
+
+// Result holds the outputs of a call, or Err when it reverted or could not be sent
+type Result struct {
+	Values []interface{}
+	Err    error
+}
+
+// Option configures caller
+type Option func(*Caller)
+
+// WithAddress sets the Multicall3 contract address
+func WithAddress(address common.Address) Option {
+	return func(c *Caller) {
+		c.address = address
+	}
+}
+
+// WithChunkSize sets the number of calls aggregated in one eth_call
+func WithChunkSize(size int) Option {
+	return func(c *Caller) {
+		if size > 0 {
+			c.chunkSize = size
+		}
+	}
+}
+
+// WithConcurrency sets the number of chunks sent at once
+func WithConcurrency(n int) Option {
+	return func(c *Caller) {
+		if n > 0 {
+			c.concurrency = n
+		}
+	}
+}
+
+// Caller sends reads through Multicall3, or one by one when the contract is not deployed
+type Caller struct {
+	caller      bind.ContractCaller
+	address     common.Address
+	chunkSize   int
+	concurrency int
+}
+
+// New creates caller reading through caller
+func New(caller bind.ContractCaller, opts ...Option) *Caller {
+	c := &Caller{
+		caller:      caller,
+		address:     Address,
+		chunkSize:   defaultChunkSize,
+		concurrency: defaultConcurrency,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Call runs calls at opts and returns their results in the same order. Calls are aggregated in chunks
+// allowed to fail one by one, a chunk the node rejects as a whole is split and sent again.
+func (c *Caller) Call(opts *bind.CallOpts, calls []Call) []Result {
+	results := make([]Result, len(calls))
+
+	type chunk struct{ from, to int }
+	chunks := make(chan chunk)
+	var wg sync.WaitGroup
+	for i := 0; i < c.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ch := range chunks {
+				c.aggregate(opts, calls[ch.from:ch.to], results[ch.from:ch.to])
+			}
+		}()
+	}
+
+	for from := 0; from < len(calls); from += c.chunkSize {
+		to := from + c.chunkSize
+		if to > len(calls) {
+			to = len(calls)
+		}
+		chunks <- chunk{from, to}
+	}
+	close(chunks)
+	wg.Wait()
+
+	return results
+}
+
+// aggregate sends calls in one aggregate3 call and fills results
+func (c *Caller) aggregate(opts *bind.CallOpts, calls []Call, results []Result) {
+	multicallABI, err := contracts.Multicall3MetaData.GetAbi()
+	if err != nil {
+		fail(results, err)
+		return
+	}
+
+	batch := make([]contracts.Multicall3Call3, len(calls))
+	for i, call := range calls {
+		data, err := call.ABI.Pack(call.Method, call.Args...)
+		if err != nil {
+			// never sent, the chunk still needs a call at this index
+			results[i].Err = fmt.Errorf("packing %s: %v", call.Method, err)
+			batch[i] = contracts.Multicall3Call3{Target: call.Target, AllowFailure: true}
+			continue
+		}
+		batch[i] = contracts.Multicall3Call3{Target: call.Target, AllowFailure: true, CallData: data}
+	}
+
+	input, err := multicallABI.Pack("aggregate3", batch)
+	if err != nil {
+		fail(results, err)
+		return
+	}
+
+	ctx := ensureContext(opts.Context)
+	output, err := c.caller.CallContract(ctx, ethereum.CallMsg{From: opts.From, To: &c.address, Data: input}, opts.BlockNumber)
+	if err == nil && len(output) == 0 {
+		code, codeErr := c.caller.CodeAt(ctx, c.address, opts.BlockNumber)
+		if codeErr == nil && len(code) == 0 {
+			c.direct(opts, calls, results)
+			return
+		}
+		err = errors.New("empty aggregate3 result")
+	}
+	if err != nil {
+		if len(calls) > 1 && isRejected(err) {
+			half := len(calls) / 2
+			c.aggregate(opts, calls[:half], results[:half])
+			c.aggregate(opts, calls[half:], results[half:])
+			return
+		}
+		fail(results, fmt.Errorf("aggregate3: %v", err))
+		return
+	}
+
+	unpacked, err := multicallABI.Unpack("aggregate3", output)
+	if err != nil {
+		fail(results, fmt.Errorf("decoding aggregate3 result: %v", err))
+		return
+	}
+	returned := *abi.ConvertType(unpacked[0], new([]contracts.Multicall3Result)).(*[]contracts.Multicall3Result)
+	if len(returned) != len(calls) {
+		fail(results, fmt.Errorf("aggregate3 returned %d results for %d calls", len(returned), len(calls)))
+		return
+	}
+
+	for i, call := range calls {
+		if results[i].Err != nil {
+			continue
+		}
+		if !returned[i].Success {
+			results[i].Err = revertError(returned[i].ReturnData)
+			continue
+		}
+		results[i].Values, results[i].Err = unpack(call, returned[i].ReturnData)
+	}
+}
+
+// direct sends calls one by one, used when Multicall3 is not deployed
+func (c *Caller) direct(opts *bind.CallOpts, calls []Call, results []Result) {
+	for i, call := range calls {
+		if results[i].Err != nil {
+			continue
+		}
+		contract := bind.NewBoundContract(call.Target, *call.ABI, c.caller, nil, nil)
+		var values []interface{}
+		if err := contract.Call(opts, &values, call.Method, call.Args...); err != nil {
+			results[i].Err = err
+			continue
+		}
+		results[i].Values = values
+	}
+}
+
+func unpack(call Call, data []byte) ([]interface{}, error) {
+	if len(data) == 0 {
+		return nil, bind.ErrNoCode
+	}
+	values, err := call.ABI.Unpack(call.Method, data)
+	if err != nil {
+		return nil, fmt.Errorf("decoding %s result: %v", call.Method, err)
+	}
+	return values, nil
+}
+
+// revertError decodes the revert reason of a failed call
+func revertError(data []byte) error {
+	if reason, err := abi.UnpackRevert(data); err == nil {
+		return errors.New("execution reverted: " + reason)
+	}
+	return errors.New("execution reverted")
+}
+
+func fail(results []Result, err error) {
+	for i := range results {
+		if results[i].Err == nil {
+			results[i].Err = err
+		}
+	}
+}
+
+// isRejected reports whether the node answered the aggregate call with an error, e.g. out of gas or a
+// response too large, rather than failing to answer
+func isRejected(err error) bool {
+	var rpcErr rpc.Error
+	return errors.As(err, &rpcErr) || strings.Contains(err.Error(), "execution reverted")
+}
+
+func ensureContext(ctx context.Context) context.Context {
+	if ctx == nil {
+		return context.Background()
+	}
+	return ctx
+}
+
+const (
+	defaultChunkSize   = 100
+	defaultConcurrency = 4
+)
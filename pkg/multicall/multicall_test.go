@@ -0,0 +1,235 @@
+package multicall_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/crypto"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/contracts"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/multicall"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/simulated"
+)
+
+var tokenABI = mustABI(simulated.FaucetTokenABI)
+
+// expected is the result of a call, values printed or the error substring
+type expected struct {
+	values string
+	err    string
+}
+
+// tokenCalls returns reads of token, holder holds balance of it, and their results. The transferFrom
+// reverts without allowance, the balanceOf of noCode is sent to an account without code and the last
+// call cannot be packed.
+func tokenCalls(token, holder, noCode common.Address, balance *big.Int) ([]multicall.Call, []expected) {
+	calls := []multicall.Call{
+		{Target: token, ABI: tokenABI, Method: "balanceOf", Args: []interface{}{holder}},
+		{Target: token, ABI: tokenABI, Method: "decimals"},
+		{Target: token, ABI: tokenABI, Method: "transferFrom", Args: []interface{}{holder, noCode, big.NewInt(1)}},
+		{Target: token, ABI: tokenABI, Method: "symbol"},
+		{Target: noCode, ABI: tokenABI, Method: "balanceOf", Args: []interface{}{holder}},
+		{Target: token, ABI: tokenABI, Method: "balanceOf", Args: []interface{}{"not an address"}},
+		{Target: token, ABI: tokenABI, Method: "balanceOf", Args: []interface{}{noCode}},
+	}
+	results := []expected{
+		{values: fmt.Sprint([]interface{}{balance})},
+		{values: fmt.Sprint([]interface{}{uint8(6)})},
+		{err: "Insufficient allowance"},
+		{values: fmt.Sprint([]interface{}{"USDC"})},
+		{err: bind.ErrNoCode.Error()},
+		{err: "packing balanceOf"},
+		{values: fmt.Sprint([]interface{}{new(big.Int)})},
+	}
+	return calls, results
+}
+
+func checkResults(t *testing.T, results []multicall.Result, want []expected) {
+	t.Helper()
+	if len(results) != len(want) {
+		t.Fatalf("%d results, want %d", len(results), len(want))
+	}
+	for i, result := range results {
+		switch {
+		case want[i].err != "":
+			if result.Err == nil || !strings.Contains(result.Err.Error(), want[i].err) {
+				t.Errorf("call %d: error %v, want %q", i, result.Err, want[i].err)
+			}
+		case result.Err != nil:
+			t.Errorf("call %d: %v", i, result.Err)
+		case fmt.Sprint(result.Values) != want[i].values:
+			t.Errorf("call %d: %v, want %s", i, result.Values, want[i].values)
+		}
+	}
+}
+
+func TestCall(t *testing.T) {
+	h, err := simulated.NewHarness()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Backend.Close()
+
+	calls, want := tokenCalls(h.USDC, h.Liquidator(), h.Borrower(), big.NewInt(100000e6))
+	for _, size := range []int{1, 3, len(calls)} {
+		t.Run(fmt.Sprintf("chunks of %d", size), func(t *testing.T) {
+			counter := &batchCaller{ContractCaller: h.Backend}
+			results := multicall.New(counter, multicall.WithChunkSize(size), multicall.WithConcurrency(2)).Call(&bind.CallOpts{}, calls)
+			checkResults(t, results, want)
+
+			if chunks := (len(calls) + size - 1) / size; len(counter.sizes) != chunks {
+				t.Errorf("%d aggregate calls, want %d", len(counter.sizes), chunks)
+			}
+		})
+	}
+}
+
+func TestCallRejectedBatch(t *testing.T) {
+	h, err := simulated.NewHarness()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Backend.Close()
+
+	calls, want := tokenCalls(h.USDC, h.Liquidator(), h.Borrower(), big.NewInt(100000e6))
+
+	t.Run("split", func(t *testing.T) {
+		// the node rejects aggregate calls of more than 2 calls as out of gas
+		counter := &batchCaller{ContractCaller: h.Backend, maxCalls: 2, err: rpcError{}}
+		results := multicall.New(counter, multicall.WithChunkSize(len(calls))).Call(&bind.CallOpts{}, calls)
+		checkResults(t, results, want)
+
+		served := 0
+		for _, size := range counter.sizes {
+			if size <= 2 {
+				served += size
+			}
+		}
+		if served != len(calls) {
+			t.Errorf("aggregate calls of %v served %d calls, want %d", counter.sizes, served, len(calls))
+		}
+		if counter.sizes[0] != len(calls) {
+			t.Errorf("first aggregate call of %d calls, want the whole chunk", counter.sizes[0])
+		}
+	})
+
+	t.Run("single call rejected", func(t *testing.T) {
+		counter := &batchCaller{ContractCaller: h.Backend, maxCalls: 0, err: rpcError{}}
+		results := multicall.New(counter, multicall.WithChunkSize(2)).Call(&bind.CallOpts{}, calls[:2])
+		for i, result := range results {
+			if result.Err == nil || !strings.Contains(result.Err.Error(), "out of gas") {
+				t.Errorf("call %d: error %v, want the rejection", i, result.Err)
+			}
+		}
+		if !reflect.DeepEqual(counter.sizes, []int{2, 1, 1}) {
+			t.Errorf("aggregate calls of %v, want [2 1 1]", counter.sizes)
+		}
+	})
+
+	t.Run("node unreachable", func(t *testing.T) {
+		counter := &batchCaller{ContractCaller: h.Backend, maxCalls: 0, err: errors.New("connection refused")}
+		results := multicall.New(counter).Call(&bind.CallOpts{}, calls)
+		for i, result := range results {
+			if result.Err == nil {
+				t.Errorf("call %d succeeded", i)
+			}
+		}
+		if len(counter.sizes) != 1 {
+			t.Errorf("aggregate calls of %v, want one not split", counter.sizes)
+		}
+	})
+}
+
+func TestCallWithoutMulticall(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	deployer := crypto.PubkeyToAddress(key.PublicKey)
+	backend := backends.NewSimulatedBackend(core.GenesisAlloc{
+		deployer: {Balance: new(big.Int).Exp(big.NewInt(10), big.NewInt(21), nil)},
+	}, 30000000)
+	defer backend.Close()
+
+	opts, err := bind.NewKeyedTransactorWithChainID(key, backend.Blockchain().Config().ChainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, _, contract, err := bind.DeployContract(opts, *tokenABI, common.FromHex(simulated.FaucetTokenBin), backend, "USDC", "USDC", uint8(6))
+	if err != nil {
+		t.Fatal(err)
+	}
+	backend.Commit()
+	if _, err := contract.Transact(opts, "allocateTo", deployer, big.NewInt(5e6)); err != nil {
+		t.Fatal(err)
+	}
+	backend.Commit()
+
+	calls, want := tokenCalls(token, deployer, common.HexToAddress("0x1"), big.NewInt(5e6))
+	results := multicall.New(backend, multicall.WithChunkSize(3)).Call(&bind.CallOpts{}, calls)
+	checkResults(t, results, want)
+}
+
+// batchCaller records the number of calls of every aggregate3 call, and rejects those of more than maxCalls
+// with err when set
+type batchCaller struct {
+	bind.ContractCaller
+	maxCalls int
+	err      error
+
+	mu    sync.Mutex
+	sizes []int
+}
+
+func (c *batchCaller) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	method := multicallABI.Methods["aggregate3"]
+	if call.To != nil && *call.To == multicall.Address && len(call.Data) >= 4 && string(call.Data[:4]) == string(method.ID) {
+		args, err := method.Inputs.Unpack(call.Data[4:])
+		if err != nil {
+			return nil, err
+		}
+		size := reflect.ValueOf(args[0]).Len()
+
+		c.mu.Lock()
+		c.sizes = append(c.sizes, size)
+		c.mu.Unlock()
+
+		if c.err != nil && size > c.maxCalls {
+			return nil, c.err
+		}
+	}
+	return c.ContractCaller.CallContract(ctx, call, blockNumber)
+}
+
+// rpcError is the error a node answers an aggregate call running out of gas with
+type rpcError struct{}
+
+func (rpcError) Error() string  { return "out of gas" }
+func (rpcError) ErrorCode() int { return -32000 }
+
+var multicallABI = func() *abi.ABI {
+	parsed, err := contracts.Multicall3MetaData.GetAbi()
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}()
+
+func mustABI(definition string) *abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(definition))
+	if err != nil {
+		panic(err)
+	}
+	return &parsed
+}
@@ -0,0 +1,35 @@
+package notify
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/config"
+)
+
+// FromConfig creates notifier with the configured sinks, it has no sink when none is configured
+func FromConfig(logger log.Logger, cfg config.Config) *Notifier {
+	settings := cfg.Notify()
+	client := &http.Client{Timeout: defaultSendTimeout}
+
+	var sinks []Sink
+	if settings.WebhookURL != nil {
+		sinks = append(sinks, NewWebhook(settings.WebhookURL.String(), client))
+	}
+	if settings.SlackURL != nil {
+		sinks = append(sinks, NewSlack(settings.SlackURL.String(), client))
+	}
+	if settings.TelegramToken != "" {
+		apiURL := ""
+		if settings.TelegramAPI != nil {
+			apiURL = settings.TelegramAPI.String()
+		}
+		sinks = append(sinks, NewTelegram(apiURL, settings.TelegramToken, settings.TelegramChatID, client))
+	}
+	if settings.File != "" {
+		sinks = append(sinks, NewFile(settings.File))
+	}
+
+	return New(logger, sinks, WithDedupWindow(settings.Dedup), WithRateLimit(settings.RateLimit, time.Minute))
+}
@@ -0,0 +1,222 @@
+// Package notify sends bot events to webhooks, chat services and files
+package notify
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+)
+
+// Event kinds
+const (
+	// KindLiquidation is a mined liquidation with its realized profit
+	KindLiquidation = "liquidation"
+	// KindReverted is a mined liquidation that reverted
+	KindReverted = "reverted"
+	// KindLowBalance is a signer balance under the configured minimum
+	KindLowBalance = "low_balance"
+	// KindSubgraphStale is a subgraph that is stale or lagging behind the chain head
+	KindSubgraphStale = "subgraph_stale"
+	// KindRPCErrors is a run of failed RPC calls
+	KindRPCErrors = "rpc_errors"
+	// KindInventoryShort is a repay market liquidations needed more underlying of than the liquidator holds
+	KindInventoryShort = "inventory_short"
+)
+
+// Severities
+const (
+	SeverityInfo    = "info"
+	SeverityWarning = "warning"
+	SeverityError   = "error"
+)
+
+// Event represents something the operators are told about
+type Event struct {
+	Kind     string            `json:"kind"`
+	Severity string            `json:"severity"`
+	Text     string            `json:"text"`
+	Fields   map[string]string `json:"fields,omitempty"`
+	Time     time.Time         `json:"time"`
+	// Key deduplicates events, an event is not sent again while one with the same key was sent within the window.
+	// Events without key are deduplicated by kind and text.
+	Key string `json:"-"`
+	// Suppressed is the number of events dropped by the rate limit before this one
+	Suppressed int `json:"suppressed,omitempty"`
+}
+
+// Sink represents notification destination
+type Sink interface {
+	Name() string
+	Send(ctx context.Context, event *Event) error
+}
+
+// Option configures notifier
+type Option func(*Notifier)
+
+// WithDedupWindow sets how long an event key is not sent again
+func WithDedupWindow(window time.Duration) Option {
+	return func(n *Notifier) {
+		n.dedupWindow = window
+	}
+}
+
+// WithRateLimit sets the number of events sent per interval, the ones over it are dropped and counted
+func WithRateLimit(events int, interval time.Duration) Option {
+	return func(n *Notifier) {
+		n.rateEvents = events
+		n.rateInterval = interval
+	}
+}
+
+// WithSendTimeout sets the timeout of a single sink delivery
+func WithSendTimeout(timeout time.Duration) Option {
+	return func(n *Notifier) {
+		n.sendTimeout = timeout
+	}
+}
+
+// Notifier deduplicates and rate limits events and delivers them to every sink in the background
+type Notifier struct {
+	logger log.Logger
+	sinks  []Sink
+	queue  chan *Event
+
+	dedupWindow  time.Duration
+	rateEvents   int
+	rateInterval time.Duration
+	sendTimeout  time.Duration
+
+	mu         sync.Mutex
+	sent       map[string]time.Time
+	window     []time.Time
+	suppressed int
+}
+
+// New creates notifier delivering to sinks, Run it to deliver events
+func New(logger log.Logger, sinks []Sink, opts ...Option) *Notifier {
+	n := &Notifier{
+		logger:       logger,
+		sinks:        sinks,
+		queue:        make(chan *Event, queueSize),
+		dedupWindow:  defaultDedupWindow,
+		rateEvents:   defaultRateEvents,
+		rateInterval: defaultRateInterval,
+		sendTimeout:  defaultSendTimeout,
+		sent:         map[string]time.Time{},
+	}
+	for _, opt := range opts {
+		opt(n)
+	}
+	return n
+}
+
+// Notify queues event unless it is a duplicate or over the rate limit, it never blocks.
+// Notify on nil notifier does nothing.
+func (n *Notifier) Notify(event Event) {
+	if n == nil || len(n.sinks) == 0 {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now().UTC()
+	}
+	if event.Severity == "" {
+		event.Severity = SeverityInfo
+	}
+
+	if !n.admit(&event) {
+		return
+	}
+
+	select {
+	case n.queue <- &event:
+	default:
+		level.Warn(n.logger).Log("msg", "notification queue full, event dropped", "kind", event.Kind)
+	}
+}
+
+// admit applies deduplication and the rate limit, it sets the number of events suppressed before event
+func (n *Notifier) admit(event *Event) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	now := event.Time
+	key := event.Key
+	if key == "" {
+		key = event.Kind + "\x00" + event.Text
+	}
+	if last, ok := n.sent[key]; ok && now.Sub(last) < n.dedupWindow {
+		return false
+	}
+
+	if n.rateEvents > 0 {
+		recent := n.window[:0]
+		for _, t := range n.window {
+			if now.Sub(t) < n.rateInterval {
+				recent = append(recent, t)
+			}
+		}
+		n.window = recent
+		if len(n.window) >= n.rateEvents {
+			n.suppressed++
+			return false
+		}
+		n.window = append(n.window, now)
+	}
+
+	n.sent[key] = now
+	event.Suppressed, n.suppressed = n.suppressed, 0
+
+	for k, t := range n.sent {
+		if now.Sub(t) >= n.dedupWindow {
+			delete(n.sent, k)
+		}
+	}
+	return true
+}
+
+// Run delivers queued events until ctx is done
+func (n *Notifier) Run(ctx context.Context) {
+	if n == nil {
+		return
+	}
+	for {
+		select {
+		case event := <-n.queue:
+			n.deliver(ctx, event)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (n *Notifier) deliver(ctx context.Context, event *Event) {
+	for _, sink := range n.sinks {
+		sendCtx, cancel := context.WithTimeout(ctx, n.sendTimeout)
+		err := sink.Send(sendCtx, event)
+		cancel()
+		if err != nil {
+			level.Error(n.logger).Log("msg", "❌ Error sending notification", "sink", sink.Name(), "kind", event.Kind, "err", err)
+		}
+	}
+}
+
+// message formats event as a single chat message
+func message(event *Event) string {
+	text := fmt.Sprintf("[%s] %s", event.Severity, event.Text)
+	if event.Suppressed > 0 {
+		text += fmt.Sprintf(" (%d earlier notification(s) suppressed by rate limit)", event.Suppressed)
+	}
+	return text
+}
+
+const (
+	queueSize           = 100
+	defaultDedupWindow  = time.Hour
+	defaultRateEvents   = 10
+	defaultRateInterval = time.Minute
+	defaultSendTimeout  = 10 * time.Second
+)
@@ -0,0 +1,176 @@
+package notify_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/notify"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/notify/notifytest"
+)
+
+// waitTimeout bounds the wait for a notification to arrive
+const waitTimeout = 2 * time.Second
+
+var liquidation = notify.Event{
+	Kind:   notify.KindLiquidation,
+	Text:   "liquidated 0xb0 for 28.00 USD profit",
+	Fields: map[string]string{"profit_usd": "28.00"},
+}
+
+// deliver runs notifier with sink until n notifications reached server, it returns the requests received
+func deliver(t *testing.T, server *notifytest.Server, sink notify.Sink, events []notify.Event, n int, opts ...notify.Option) []notifytest.Request {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	notifier := notify.New(log.NewNopLogger(), []notify.Sink{sink}, opts...)
+	go notifier.Run(ctx)
+	for _, event := range events {
+		notifier.Notify(event)
+	}
+
+	receive(t, server, n)
+	return server.Requests()
+}
+
+// receive waits for n notifications to reach server, then leaves time for unexpected extra ones to arrive
+func receive(t *testing.T, server *notifytest.Server, n int) {
+	for i := 0; i < n; i++ {
+		select {
+		case <-server.Received():
+		case <-time.After(waitTimeout):
+			t.Fatalf("%d of %d notification(s) received", i, n)
+		}
+	}
+	time.Sleep(50 * time.Millisecond)
+}
+
+func TestWebhook(t *testing.T) {
+	server := notifytest.NewServer()
+	defer server.Close()
+
+	requests := deliver(t, server, notify.NewWebhook(server.URL+"/hook", server.Client()), []notify.Event{liquidation}, 1)
+	body := requests[0].Body
+	if requests[0].Path != "/hook" || body["kind"] != notify.KindLiquidation || body["text"] != liquidation.Text {
+		t.Errorf("unexpected request %s %v", requests[0].Path, body)
+	}
+	if fields, ok := body["fields"].(map[string]interface{}); !ok || fields["profit_usd"] != "28.00" {
+		t.Errorf("unexpected fields %v", body["fields"])
+	}
+}
+
+func TestSlack(t *testing.T) {
+	server := notifytest.NewServer()
+	defer server.Close()
+
+	requests := deliver(t, server, notify.NewSlack(server.URL, server.Client()), []notify.Event{liquidation}, 1)
+	if text, _ := requests[0].Body["text"].(string); !strings.Contains(text, liquidation.Text) {
+		t.Errorf("unexpected text %q", text)
+	}
+}
+
+func TestTelegram(t *testing.T) {
+	server := notifytest.NewServer()
+	defer server.Close()
+
+	sink := notify.NewTelegram(server.URL, "123:token", "-100", server.Client())
+	requests := deliver(t, server, sink, []notify.Event{liquidation}, 1)
+	if requests[0].Path != "/bot123:token/sendMessage" || requests[0].Body["chat_id"] != "-100" {
+		t.Errorf("unexpected request %s %v", requests[0].Path, requests[0].Body)
+	}
+	if text, _ := requests[0].Body["text"].(string); !strings.Contains(text, liquidation.Text) {
+		t.Errorf("unexpected text %q", text)
+	}
+}
+
+func TestFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notifications.jsonl")
+	sink := notify.NewFile(path)
+	for _, text := range []string{"first", "second"} {
+		event := liquidation
+		event.Text = text
+		if err := sink.Send(context.Background(), &event); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("%d lines written, 2 expected", len(lines))
+	}
+	var event notify.Event
+	if err := json.Unmarshal([]byte(lines[1]), &event); err != nil {
+		t.Fatal(err)
+	}
+	if event.Kind != notify.KindLiquidation || event.Text != "second" || event.Fields["profit_usd"] != "28.00" {
+		t.Errorf("unexpected event %+v", event)
+	}
+}
+
+func TestDedup(t *testing.T) {
+	server := notifytest.NewServer()
+	defer server.Close()
+
+	lowBalance := notify.Event{Kind: notify.KindLowBalance, Key: notify.KindLowBalance, Text: "balance 0.01 ETH"}
+	lowerBalance := notify.Event{Kind: notify.KindLowBalance, Key: notify.KindLowBalance, Text: "balance 0.009 ETH"}
+	events := []notify.Event{lowBalance, lowerBalance, liquidation, liquidation}
+
+	requests := deliver(t, server, notify.NewWebhook(server.URL, server.Client()), events, 2)
+	if len(requests) != 2 {
+		t.Fatalf("%d notifications sent, 2 expected", len(requests))
+	}
+	if requests[0].Body["text"] != lowBalance.Text || requests[1].Body["text"] != liquidation.Text {
+		t.Errorf("unexpected notifications %v", requests)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	server := notifytest.NewServer()
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	interval := 300 * time.Millisecond
+	notifier := notify.New(log.NewNopLogger(), []notify.Sink{notify.NewWebhook(server.URL, server.Client())},
+		notify.WithRateLimit(2, interval))
+	go notifier.Run(ctx)
+
+	for i := 0; i < 4; i++ {
+		notifier.Notify(notify.Event{Kind: notify.KindRPCErrors, Text: fmt.Sprintf("error %d", i)})
+	}
+	time.Sleep(interval + 50*time.Millisecond)
+	notifier.Notify(notify.Event{Kind: notify.KindRPCErrors, Text: "error after interval"})
+
+	receive(t, server, 3)
+	requests := server.Requests()
+	if len(requests) != 3 {
+		t.Fatalf("%d notifications sent, 3 expected", len(requests))
+	}
+	if suppressed, _ := requests[2].Body["suppressed"].(float64); suppressed != 2 {
+		t.Errorf("%v suppressed reported, 2 expected", requests[2].Body["suppressed"])
+	}
+}
+
+func TestFailedDelivery(t *testing.T) {
+	server := notifytest.NewServer()
+	defer server.Close()
+	server.FailNext(http.StatusInternalServerError)
+
+	events := []notify.Event{{Kind: notify.KindReverted, Text: "reverted 0x01"}, liquidation}
+	requests := deliver(t, server, notify.NewWebhook(server.URL, server.Client()), events, 1)
+	if len(requests) != 1 || requests[0].Body["kind"] != notify.KindLiquidation {
+		t.Errorf("unexpected requests %v", requests)
+	}
+}
@@ -0,0 +1,93 @@
+// Package notifytest provides an in-process stand-in for notification webhooks
+package notifytest
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+)
+
+// Request represents notification received by the server
+type Request struct {
+	Path string
+	Body map[string]interface{}
+}
+
+// Server is httptest server recording JSON posted to any path, it answers like a Slack webhook or the Telegram Bot API
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	failures []int
+	requests []Request
+	received chan struct{}
+}
+
+// NewServer creates and starts new fake webhook server, Close it when done
+func NewServer() *Server {
+	s := &Server{received: make(chan struct{}, 100)}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// FailNext makes the next request fail with HTTP status
+func (s *Server) FailNext(status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, status)
+}
+
+// Requests returns notifications received so far
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request{}, s.requests...)
+}
+
+// Received is signalled once per notification received
+func (s *Server) Received() <-chan struct{} {
+	return s.received
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "POST only", http.StatusMethodNotAllowed)
+		return
+	}
+
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	body := map[string]interface{}{}
+	if err := json.Unmarshal(data, &body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	status := http.StatusOK
+	if len(s.failures) > 0 {
+		status, s.failures = s.failures[0], s.failures[1:]
+	}
+	if status == http.StatusOK {
+		s.requests = append(s.requests, Request{Path: r.URL.Path, Body: body})
+	}
+	s.mu.Unlock()
+
+	if status != http.StatusOK {
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+
+	select {
+	case s.received <- struct{}{}:
+	default:
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{"ok":true}`))
+}
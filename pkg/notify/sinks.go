@@ -0,0 +1,144 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+// NewWebhook creates sink posting events as JSON to url
+func NewWebhook(url string, client *http.Client) Sink {
+	return &webhook{url: url, client: client}
+}
+
+type webhook struct {
+	url    string
+	client *http.Client
+}
+
+func (s *webhook) Name() string {
+	return "webhook"
+}
+
+func (s *webhook) Send(ctx context.Context, event *Event) error {
+	return postJSON(ctx, s.client, s.url, event)
+}
+
+// NewSlack creates sink posting events to Slack compatible incoming webhook url
+func NewSlack(url string, client *http.Client) Sink {
+	return &slack{url: url, client: client}
+}
+
+type slack struct {
+	url    string
+	client *http.Client
+}
+
+func (s *slack) Name() string {
+	return "slack"
+}
+
+func (s *slack) Send(ctx context.Context, event *Event) error {
+	return postJSON(ctx, s.client, s.url, map[string]string{"text": message(event)})
+}
+
+// NewTelegram creates sink sending events to chatID with Telegram Bot API compatible apiURL, https://api.telegram.org by default
+func NewTelegram(apiURL, token, chatID string, client *http.Client) Sink {
+	if apiURL == "" {
+		apiURL = TelegramAPI
+	}
+	return &telegram{
+		url:    strings.TrimRight(apiURL, "/") + "/bot" + token + "/sendMessage",
+		token:  token,
+		chatID: chatID,
+		client: client,
+	}
+}
+
+type telegram struct {
+	url    string
+	token  string
+	chatID string
+	client *http.Client
+}
+
+func (s *telegram) Name() string {
+	return "telegram"
+}
+
+func (s *telegram) Send(ctx context.Context, event *Event) error {
+	err := postJSON(ctx, s.client, s.url, map[string]string{"chat_id": s.chatID, "text": message(event)})
+	if err != nil && s.token != "" {
+		// HTTP client errors carry the URL, the token must not reach the logs
+		return errors.New(strings.Replace(err.Error(), s.token, "<token>", -1))
+	}
+	return err
+}
+
+// NewFile creates sink appending events to path as JSON lines
+func NewFile(path string) Sink {
+	return &file{path: path}
+}
+
+type file struct {
+	path string
+	mu   sync.Mutex
+}
+
+func (s *file) Name() string {
+	return "file"
+}
+
+func (s *file) Send(ctx context.Context, event *Event) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
+
+func postJSON(ctx context.Context, client *http.Client, url string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	ioutil.ReadAll(resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected http status code: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// TelegramAPI is the public Telegram Bot API
+const TelegramAPI = "https://api.telegram.org"
@@ -7,18 +7,21 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
+	"time"
 )
 
 // NewBitstampPriceAPI creates new Bitstamp price API
-func NewBitstampPriceAPI() PriceAPI {
+func NewBitstampPriceAPI(opts ...Option) PriceAPI {
 	return &bitstampPriceAPI{
-		client: &http.Client{},
+		httpOptions: newHTTPOptions(opts),
 	}
 }
 
 type bitstampPriceAPI struct {
-	client *http.Client
+	httpOptions
 }
 
 func (pa *bitstampPriceAPI) GetName() string {
@@ -26,11 +29,10 @@ func (pa *bitstampPriceAPI) GetName() string {
 }
 
 func (pa *bitstampPriceAPI) GetPrice(ctx context.Context) (float64, error) {
-	getReq, err := http.NewRequest("GET", bitstampGetPriceEndpoint, nil)
+	getReq, err := http.NewRequestWithContext(ctx, "GET", pa.endpoint(bitstampGetPriceEndpoint), nil)
 	if err != nil {
 		return 0, err
 	}
-	getReq.WithContext(ctx)
 
 	resp, err := pa.client.Do(getReq)
 	if err != nil {
@@ -65,6 +67,60 @@ func (pa *bitstampPriceAPI) GetPrice(ctx context.Context) (float64, error) {
 	return priceFloat, nil
 }
 
+func (pa *bitstampPriceAPI) GetPriceAt(ctx context.Context, asset string, at time.Time) (float64, error) {
+	start := at.Truncate(time.Minute)
+	query := url.Values{}
+	query.Set("step", "60")
+	query.Set("limit", "1")
+	query.Set("start", strconv.FormatInt(start.Unix(), 10))
+	pair := strings.ToLower(normalizeAsset(asset)) + "usd"
+	endpoint := fmt.Sprintf(bitstampGetOHLCEndpoint, url.PathEscape(pair)) + "?" + query.Encode()
+
+	bitstampResp := new(bitstampGetOHLCResponse)
+	if err := getJSON(ctx, pa.client, pa.endpoint(endpoint), bitstampResp); err != nil {
+		return 0, err
+	}
+
+	timestamps := make([]time.Time, 0, len(bitstampResp.Data.OHLC))
+	for _, candle := range bitstampResp.Data.OHLC {
+		timestamp, err := strconv.ParseInt(candle.Timestamp, 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		timestamps = append(timestamps, time.Unix(timestamp, 0))
+	}
+
+	closest := closestPoint(timestamps, start)
+	if closest == -1 {
+		return 0, fmt.Errorf("no price for %s at %s", asset, at.UTC().Format(time.RFC3339))
+	}
+
+	priceFloat, err := strconv.ParseFloat(bitstampResp.Data.OHLC[closest].Close, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	if priceFloat == 0.0 {
+		return 0, errors.New("currency rate is 0")
+	}
+
+	return priceFloat, nil
+}
+
+type bitstampGetOHLCResponse struct {
+	Data struct {
+		Pair string `json:"pair"`
+		OHLC []struct {
+			Timestamp string `json:"timestamp"`
+			Open      string `json:"open"`
+			High      string `json:"high"`
+			Low       string `json:"low"`
+			Close     string `json:"close"`
+			Volume    string `json:"volume"`
+		} `json:"ohlc"`
+	} `json:"data"`
+}
+
 type bitstampGetPriceResponse struct {
 	High      string `json:"high"`
 	Last      string `json:"last"`
@@ -79,4 +135,5 @@ type bitstampGetPriceResponse struct {
 
 const (
 	bitstampGetPriceEndpoint = "https://www.bitstamp.net/api/v2/ticker/btcusd/"
+	bitstampGetOHLCEndpoint  = "https://www.bitstamp.net/api/v2/ohlc/%s/"
 )
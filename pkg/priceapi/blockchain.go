@@ -10,14 +10,14 @@ import (
 )
 
 // NewBlockchainPriceAPI creates new Blockchain.com price API
-func NewBlockchainPriceAPI() PriceAPI {
+func NewBlockchainPriceAPI(opts ...Option) PriceAPI {
 	return &blockchainPriceAPI{
-		client: &http.Client{},
+		httpOptions: newHTTPOptions(opts),
 	}
 }
 
 type blockchainPriceAPI struct {
-	client *http.Client
+	httpOptions
 }
 
 func (pa *blockchainPriceAPI) GetName() string {
@@ -25,11 +25,10 @@ func (pa *blockchainPriceAPI) GetName() string {
 }
 
 func (pa *blockchainPriceAPI) GetPrice(ctx context.Context) (float64, error) {
-	getReq, err := http.NewRequest("GET", blockchainInfoGetPriceEndpoint, nil)
+	getReq, err := http.NewRequestWithContext(ctx, "GET", pa.endpoint(blockchainInfoGetPriceEndpoint), nil)
 	if err != nil {
 		return 0, err
 	}
-	getReq.WithContext(ctx)
 
 	resp, err := pa.client.Do(getReq)
 	if err != nil {
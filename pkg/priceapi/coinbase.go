@@ -7,18 +7,20 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"strconv"
+	"time"
 )
 
 // NewCoinbasePriceAPI creates new Coinbase price API
-func NewCoinbasePriceAPI() PriceAPI {
+func NewCoinbasePriceAPI(opts ...Option) PriceAPI {
 	return &coinbasePriceAPI{
-		client: &http.Client{},
+		httpOptions: newHTTPOptions(opts),
 	}
 }
 
 type coinbasePriceAPI struct {
-	client *http.Client
+	httpOptions
 }
 
 func (pa *coinbasePriceAPI) GetName() string {
@@ -26,11 +28,10 @@ func (pa *coinbasePriceAPI) GetName() string {
 }
 
 func (pa *coinbasePriceAPI) GetPrice(ctx context.Context) (float64, error) {
-	getReq, err := http.NewRequest("GET", coinbaseGetPriceEndpoint, nil)
+	getReq, err := http.NewRequestWithContext(ctx, "GET", pa.endpoint(coinbaseGetPriceEndpoint), nil)
 	if err != nil {
 		return 0, err
 	}
-	getReq.WithContext(ctx)
 
 	resp, err := pa.client.Do(getReq)
 	if err != nil {
@@ -65,6 +66,43 @@ func (pa *coinbasePriceAPI) GetPrice(ctx context.Context) (float64, error) {
 	return priceFloat, nil
 }
 
+func (pa *coinbasePriceAPI) GetPriceAt(ctx context.Context, asset string, at time.Time) (float64, error) {
+	start := at.Truncate(time.Minute)
+	query := url.Values{}
+	query.Set("granularity", "60")
+	query.Set("start", start.Add(-coinbaseHistoryWindow).UTC().Format(time.RFC3339))
+	query.Set("end", start.Add(coinbaseHistoryWindow).UTC().Format(time.RFC3339))
+	endpoint := fmt.Sprintf(coinbaseGetCandlesEndpoint, url.PathEscape(normalizeAsset(asset))) + "?" + query.Encode()
+
+	coinbaseResp := new(coinbaseGetCandlesResponse)
+	if err := getJSON(ctx, pa.client, pa.endpoint(endpoint), coinbaseResp); err != nil {
+		return 0, err
+	}
+
+	timestamps := make([]time.Time, 0, len(*coinbaseResp))
+	for _, candle := range *coinbaseResp {
+		if len(candle) < 5 {
+			return 0, fmt.Errorf("unexpected candle length: %d", len(candle))
+		}
+		timestamps = append(timestamps, time.Unix(int64(candle[0]), 0))
+	}
+
+	closest := closestPoint(timestamps, start)
+	if closest == -1 {
+		return 0, fmt.Errorf("no price for %s at %s", asset, at.UTC().Format(time.RFC3339))
+	}
+
+	// candle is [time, low, high, open, close, volume]
+	price := (*coinbaseResp)[closest][4]
+	if price == 0.0 {
+		return 0, errors.New("currency rate is 0")
+	}
+
+	return price, nil
+}
+
+type coinbaseGetCandlesResponse [][]float64
+
 type coinbaseGetPriceResponse struct {
 	Data struct {
 		Base     string `json:"base"`
@@ -74,5 +112,8 @@ type coinbaseGetPriceResponse struct {
 }
 
 const (
-	coinbaseGetPriceEndpoint = "https://api.coinbase.com/v2/prices/spot?currency=USD"
+	coinbaseGetPriceEndpoint   = "https://api.coinbase.com/v2/prices/spot?currency=USD"
+	coinbaseGetCandlesEndpoint = "https://api.exchange.coinbase.com/products/%s-USD/candles"
+
+	coinbaseHistoryWindow = time.Minute * 5
 )
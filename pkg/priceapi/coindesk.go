@@ -10,14 +10,14 @@ import (
 )
 
 // NewCoindeskPriceAPI creates new Coindesk price API
-func NewCoindeskPriceAPI() PriceAPI {
+func NewCoindeskPriceAPI(opts ...Option) PriceAPI {
 	return &coindeskPriceAPI{
-		client: &http.Client{},
+		httpOptions: newHTTPOptions(opts),
 	}
 }
 
 type coindeskPriceAPI struct {
-	client *http.Client
+	httpOptions
 }
 
 func (pa *coindeskPriceAPI) GetName() string {
@@ -25,11 +25,10 @@ func (pa *coindeskPriceAPI) GetName() string {
 }
 
 func (pa *coindeskPriceAPI) GetPrice(ctx context.Context) (float64, error) {
-	getReq, err := http.NewRequest("GET", coindeskGetPriceEndpoint, nil)
+	getReq, err := http.NewRequestWithContext(ctx, "GET", pa.endpoint(coindeskGetPriceEndpoint), nil)
 	if err != nil {
 		return 0, err
 	}
-	getReq.WithContext(ctx)
 
 	resp, err := pa.client.Do(getReq)
 	if err != nil {
@@ -7,17 +7,20 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
+	"strings"
+	"time"
 )
 
 // NewCoingeckoPriceAPI creates new Coindesk price API
-func NewCoingeckoPriceAPI() PriceAPI {
+func NewCoingeckoPriceAPI(opts ...Option) PriceAPI {
 	return &coingeckoPriceAPI{
-		client: &http.Client{},
+		httpOptions: newHTTPOptions(opts),
 	}
 }
 
 type coingeckoPriceAPI struct {
-	client *http.Client
+	httpOptions
 }
 
 func (pa *coingeckoPriceAPI) GetName() string {
@@ -25,11 +28,10 @@ func (pa *coingeckoPriceAPI) GetName() string {
 }
 
 func (pa *coingeckoPriceAPI) GetPrice(ctx context.Context) (float64, error) {
-	getReq, err := http.NewRequest("GET", coingeckoGetPriceEndpoint, nil)
+	getReq, err := http.NewRequestWithContext(ctx, "GET", pa.endpoint(coingeckoGetPriceEndpoint), nil)
 	if err != nil {
 		return 0, err
 	}
-	getReq.WithContext(ctx)
 
 	resp, err := pa.client.Do(getReq)
 	if err != nil {
@@ -64,6 +66,49 @@ func (pa *coingeckoPriceAPI) GetPrice(ctx context.Context) (float64, error) {
 	return coingeckoResp.CurrentPrice, nil
 }
 
+func (pa *coingeckoPriceAPI) GetPriceAt(ctx context.Context, asset string, at time.Time) (float64, error) {
+	query := url.Values{}
+	query.Set("vs_currency", "usd")
+	query.Set("from", fmt.Sprint(at.Add(-coingeckoHistoryWindow).Unix()))
+	query.Set("to", fmt.Sprint(at.Add(coingeckoHistoryWindow).Unix()))
+	endpoint := fmt.Sprintf(coingeckoGetPriceRangeEndpoint, url.PathEscape(coingeckoID(asset))) + "?" + query.Encode()
+
+	coingeckoResp := new(coingeckoGetPriceRangeResponse)
+	if err := getJSON(ctx, pa.client, pa.endpoint(endpoint), coingeckoResp); err != nil {
+		return 0, err
+	}
+
+	timestamps := make([]time.Time, 0, len(coingeckoResp.Prices))
+	for _, point := range coingeckoResp.Prices {
+		timestamps = append(timestamps, time.Unix(0, int64(point[0])*int64(time.Millisecond)))
+	}
+
+	closest := closestPoint(timestamps, at)
+	if closest == -1 {
+		return 0, fmt.Errorf("no price for %s at %s", asset, at.UTC().Format(time.RFC3339))
+	}
+
+	price := coingeckoResp.Prices[closest][1]
+	if price == 0.0 {
+		return 0, errors.New("currency rate is 0")
+	}
+
+	return price, nil
+}
+
+// coingeckoID maps ticker symbol to CoinGecko coin id
+func coingeckoID(asset string) string {
+	asset = normalizeAsset(asset)
+	if id, ok := coingeckoIDs[asset]; ok {
+		return id
+	}
+	return strings.ToLower(asset)
+}
+
+type coingeckoGetPriceRangeResponse struct {
+	Prices [][2]float64 `json:"prices"`
+}
+
 type coingeckoGetPriceResponse []struct {
 	ID           string  `json:"id"`
 	Symbol       string  `json:"symbol"`
@@ -73,5 +118,24 @@ type coingeckoGetPriceResponse []struct {
 }
 
 const (
-	coingeckoGetPriceEndpoint = "https://api.coingecko.com/api/v3/coins/markets?vs_currency=usd&ids=bitcoin"
+	coingeckoGetPriceEndpoint      = "https://api.coingecko.com/api/v3/coins/markets?vs_currency=usd&ids=bitcoin"
+	coingeckoGetPriceRangeEndpoint = "https://api.coingecko.com/api/v3/coins/%s/market_chart/range"
+
+	// ranges shorter than a day are returned with 5 minute granularity
+	coingeckoHistoryWindow = time.Minute * 30
 )
+
+var coingeckoIDs = map[string]string{
+	"BTC":  "bitcoin",
+	"WBTC": "wrapped-bitcoin",
+	"ETH":  "ethereum",
+	"WETH": "weth",
+	"USDC": "usd-coin",
+	"USDT": "tether",
+	"DAI":  "dai",
+	"COMP": "compound-governance-token",
+	"UNI":  "uniswap",
+	"LINK": "chainlink",
+	"BAT":  "basic-attention-token",
+	"ZRX":  "0x",
+}
@@ -0,0 +1,80 @@
+package priceapi
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// DEXPriceAPI represents price API backed by on-chain liquidity pool
+type DEXPriceAPI interface {
+	PriceAPI
+	// GetQuote returns spot price and price impact of selling amountIn of the base token
+	GetQuote(ctx context.Context, amountIn *big.Int) (*Quote, error)
+}
+
+// DEXPair describes liquidity pool and how its tokens map to the quoted price
+type DEXPair struct {
+	// Address is the pool contract address
+	Address common.Address
+	// BaseIsToken0 is true when the priced asset is the pool token0
+	BaseIsToken0 bool
+	// BaseDecimals is the priced asset decimals
+	BaseDecimals uint8
+	// QuoteDecimals is the decimals of the asset the price is expressed in
+	QuoteDecimals uint8
+}
+
+// Quote represents price of a trade against liquidity pool
+type Quote struct {
+	// SpotPrice is the marginal price before the trade, in quote units per base unit
+	SpotPrice float64
+	// ExecutionPrice is the average price of the trade, pool fee included
+	ExecutionPrice float64
+	// PriceImpact is the relative difference between spot and execution price, 0.01 is 1%
+	PriceImpact float64
+	// AmountIn is the sold base token amount, in base token smallest units
+	AmountIn *big.Int
+	// AmountOut is the received quote token amount, in quote token smallest units
+	AmountOut *big.Int
+}
+
+func newQuote(spotPrice float64, amountIn, amountOut *big.Int, pair DEXPair) (*Quote, error) {
+	if amountOut.Sign() <= 0 {
+		return nil, errors.New("trade output is 0")
+	}
+
+	executionPrice := ratio(amountOut, pair.QuoteDecimals, amountIn, pair.BaseDecimals)
+
+	return &Quote{
+		SpotPrice:      spotPrice,
+		ExecutionPrice: executionPrice,
+		PriceImpact:    1 - executionPrice/spotPrice,
+		AmountIn:       new(big.Int).Set(amountIn),
+		AmountOut:      amountOut,
+	}, nil
+}
+
+// ratio returns (num / 10^numDecimals) / (den / 10^denDecimals)
+func ratio(num *big.Int, numDecimals uint8, den *big.Int, denDecimals uint8) float64 {
+	n := new(big.Float).SetInt(num)
+	n.Mul(n, pow10(denDecimals))
+	d := new(big.Float).SetInt(den)
+	d.Mul(d, pow10(numDecimals))
+
+	r, _ := new(big.Float).Quo(n, d).Float64()
+	return r
+}
+
+func pow10(decimals uint8) *big.Float {
+	return new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil))
+}
+
+func validateAmountIn(amountIn *big.Int) error {
+	if amountIn == nil || amountIn.Sign() <= 0 {
+		return errors.New("trade amount must be positive")
+	}
+	return nil
+}
@@ -0,0 +1,298 @@
+package priceapi_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/contracts"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/priceapi"
+)
+
+// poolCaller answers pool calls with the outputs of their method, secondsAgos holds the observe argument
+type poolCaller struct {
+	abi         *abi.ABI
+	outputs     map[string][]interface{}
+	secondsAgos []uint32
+}
+
+func newPoolCaller(t *testing.T, metaData interface {
+	GetAbi() (*abi.ABI, error)
+}, outputs map[string][]interface{}) *poolCaller {
+	parsed, err := metaData.GetAbi()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &poolCaller{abi: parsed, outputs: outputs}
+}
+
+func (c *poolCaller) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return []byte{1}, nil
+}
+
+func (c *poolCaller) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	method, err := c.abi.MethodById(call.Data[:4])
+	if err != nil {
+		return nil, err
+	}
+	if method.Name == "observe" {
+		args, err := method.Inputs.Unpack(call.Data[4:])
+		if err != nil {
+			return nil, err
+		}
+		c.secondsAgos = args[0].([]uint32)
+	}
+	outputs, ok := c.outputs[method.Name]
+	if !ok {
+		return nil, errors.New("unexpected call " + method.Name)
+	}
+	return method.Outputs.Pack(outputs...)
+}
+
+func bigInt(s string) *big.Int {
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		panic("invalid number " + s)
+	}
+	return n
+}
+
+func near(got, want float64) bool {
+	return math.Abs(got-want) <= math.Abs(want)*1e-9
+}
+
+func TestUniswapV2(t *testing.T) {
+	tests := []struct {
+		name      string
+		pair      priceapi.DEXPair
+		reserve0  *big.Int
+		reserve1  *big.Int
+		amountIn  *big.Int
+		spot      float64
+		amountOut *big.Int
+	}{
+		{
+			// USDC/WETH: 2M USDC against 1000 WETH, ETH priced in USDC
+			name:      "base token1, 18 over 6 decimals",
+			pair:      priceapi.DEXPair{BaseIsToken0: false, BaseDecimals: 18, QuoteDecimals: 6},
+			reserve0:  bigInt("2000000000000"),
+			reserve1:  bigInt("1000000000000000000000"),
+			amountIn:  bigInt("1000000000000000000"),
+			spot:      2000,
+			amountOut: bigInt("1992013962"),
+		},
+		{
+			// DAI/USDC: 1M DAI against 1.001M USDC, DAI priced in USDC
+			name:      "base token0, 18 over 6 decimals",
+			pair:      priceapi.DEXPair{BaseIsToken0: true, BaseDecimals: 18, QuoteDecimals: 6},
+			reserve0:  bigInt("1000000000000000000000000"),
+			reserve1:  bigInt("1001000000000"),
+			amountIn:  bigInt("1000000000000000000000"),
+			spot:      1.001,
+			amountOut: bigInt("997002988"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			caller := newPoolCaller(t, contracts.UniswapV2PairMetaData, map[string][]interface{}{
+				"getReserves": {tt.reserve0, tt.reserve1, uint32(0)},
+			})
+			pa, err := priceapi.NewUniswapV2PriceAPI(caller, tt.pair)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			price, err := pa.GetPrice(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !near(price, tt.spot) {
+				t.Errorf("price %v, want %v", price, tt.spot)
+			}
+
+			quote, err := pa.GetQuote(context.Background(), tt.amountIn)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if quote.AmountOut.Cmp(tt.amountOut) != 0 {
+				t.Errorf("amount out %s, want %s", quote.AmountOut, tt.amountOut)
+			}
+			if !near(quote.SpotPrice, tt.spot) {
+				t.Errorf("spot price %v, want %v", quote.SpotPrice, tt.spot)
+			}
+			// the 0.3% fee and the reserves moving
+			if quote.PriceImpact <= 0.003 || quote.PriceImpact >= 0.01 {
+				t.Errorf("price impact %v, want over the 0.3%% fee", quote.PriceImpact)
+			}
+		})
+	}
+
+	caller := newPoolCaller(t, contracts.UniswapV2PairMetaData, map[string][]interface{}{
+		"getReserves": {new(big.Int), big.NewInt(1), uint32(0)},
+	})
+	pa, err := priceapi.NewUniswapV2PriceAPI(caller, priceapi.DEXPair{BaseDecimals: 18, QuoteDecimals: 6})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pa.GetPrice(context.Background()); err == nil {
+		t.Error("empty reserves priced")
+	}
+}
+
+func TestUniswapV3(t *testing.T) {
+	// USDC/WETH at 2000 USDC an ETH: token1/token0 is 5e8 in smallest units
+	ethPool := priceapi.DEXPair{BaseIsToken0: false, BaseDecimals: 18, QuoteDecimals: 6}
+	ethSqrtPrice := bigInt("1771595571142957102961017161607260")
+	// WBTC/WETH at 15 ETH a BTC: token1/token0 is 1.5e11 in smallest units
+	btcPool := priceapi.DEXPair{BaseIsToken0: true, BaseDecimals: 8, QuoteDecimals: 18}
+	btcSqrtPrice := bigInt("30684935396836052562870278196776490")
+
+	tests := []struct {
+		name      string
+		pair      priceapi.DEXPair
+		sqrtPrice *big.Int
+		liquidity *big.Int
+		fee       int64
+		amountIn  *big.Int
+		spot      float64
+		amountOut *big.Int
+	}{
+		{
+			name:      "base token1, one for zero",
+			pair:      ethPool,
+			sqrtPrice: ethSqrtPrice,
+			liquidity: bigInt("10000000000000000000000"),
+			fee:       500,
+			amountIn:  bigInt("1000000000000000000"),
+			spot:      2000,
+			amountOut: bigInt("1998999991"),
+		},
+		{
+			name:      "base token1, shallow liquidity",
+			pair:      ethPool,
+			sqrtPrice: ethSqrtPrice,
+			liquidity: bigInt("100000000000000000000"),
+			fee:       500,
+			amountIn:  bigInt("1000000000000000000"),
+			spot:      2000,
+			amountOut: bigInt("1998999106"),
+		},
+		{
+			name:      "base token0, zero for one",
+			pair:      btcPool,
+			sqrtPrice: btcSqrtPrice,
+			liquidity: bigInt("10000000000000000"),
+			fee:       3000,
+			amountIn:  big.NewInt(100000000),
+			spot:      15,
+			amountOut: bigInt("14897475418824949537"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			caller := newPoolCaller(t, contracts.UniswapV3PoolMetaData, map[string][]interface{}{
+				"slot0":     {tt.sqrtPrice, new(big.Int), uint16(0), uint16(1), uint16(1), uint8(0), true},
+				"liquidity": {tt.liquidity},
+				"fee":       {big.NewInt(tt.fee)},
+			})
+			pa, err := priceapi.NewUniswapV3PriceAPI(caller, tt.pair, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			price, err := pa.GetPrice(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !near(price, tt.spot) {
+				t.Errorf("spot price %v, want %v", price, tt.spot)
+			}
+
+			quote, err := pa.GetQuote(context.Background(), tt.amountIn)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if quote.AmountOut.Cmp(tt.amountOut) != 0 {
+				t.Errorf("amount out %s, want %s", quote.AmountOut, tt.amountOut)
+			}
+			if fee := float64(tt.fee) / 1e6; quote.PriceImpact < fee {
+				t.Errorf("price impact %v under the %v fee", quote.PriceImpact, fee)
+			}
+		})
+	}
+}
+
+func TestUniswapV3TWAP(t *testing.T) {
+	tests := []struct {
+		name        string
+		pair        priceapi.DEXPair
+		cumulatives []int64
+		window      time.Duration
+		price       float64
+	}{
+		{
+			// tick 200311.2 rounds down to 200311
+			name:        "base token1, mixed decimals",
+			pair:        priceapi.DEXPair{BaseIsToken0: false, BaseDecimals: 18, QuoteDecimals: 6},
+			cumulatives: []int64{1000000, 1000000 + 200311*600 + 120},
+			window:      10 * time.Minute,
+			price:       2000.0402896525002,
+		},
+		{
+			name:        "base token0, mixed decimals",
+			pair:        priceapi.DEXPair{BaseIsToken0: true, BaseDecimals: 8, QuoteDecimals: 18},
+			cumulatives: []int64{0, 257351 * 60},
+			window:      time.Minute,
+			price:       14.998683053790948,
+		},
+		{
+			// -100.1 rounds to negative infinity like OracleLibrary.consult
+			name:        "negative tick rounding",
+			pair:        priceapi.DEXPair{BaseIsToken0: true, BaseDecimals: 18, QuoteDecimals: 18},
+			cumulatives: []int64{0, -1001},
+			window:      10 * time.Second,
+			price:       0.9899513336078498,
+		},
+		{
+			name:        "negative tick exact",
+			pair:        priceapi.DEXPair{BaseIsToken0: true, BaseDecimals: 18, QuoteDecimals: 18},
+			cumulatives: []int64{0, -1000},
+			window:      10 * time.Second,
+			price:       0.9900503287412106,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			caller := newPoolCaller(t, contracts.UniswapV3PoolMetaData, map[string][]interface{}{
+				"observe": {
+					[]*big.Int{big.NewInt(tt.cumulatives[0]), big.NewInt(tt.cumulatives[1])},
+					[]*big.Int{new(big.Int), new(big.Int)},
+				},
+			})
+			pa, err := priceapi.NewUniswapV3PriceAPI(caller, tt.pair, tt.window)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			price, err := pa.GetPrice(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !near(price, tt.price) {
+				t.Errorf("price %v, want %v", price, tt.price)
+			}
+			if want := uint32(tt.window / time.Second); len(caller.secondsAgos) != 2 || caller.secondsAgos[0] != want || caller.secondsAgos[1] != 0 {
+				t.Errorf("observed %v, want [%d 0]", caller.secondsAgos, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package priceapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+)
+
+// NewFileStore creates historical price store persisted as JSON lines at path, one price per line,
+// the file is created on first write. A last line a crash left truncated is cut off the file so the
+// next price is appended after the last complete one.
+func NewFileStore(path string) (*FileStore, error) {
+	store := &FileStore{
+		path:   path,
+		prices: map[string]float64{},
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// complete is the length of the lines read
+	complete := 0
+	for line := 1; complete < len(data); line++ {
+		next := len(data)
+		if i := bytes.IndexByte(data[complete:], '\n'); i >= 0 {
+			next = complete + i + 1
+		}
+		text := bytes.TrimSpace(data[complete:next])
+		if len(text) == 0 {
+			complete = next
+			continue
+		}
+
+		var record fileStoreRecord
+		if err := json.Unmarshal(text, &record); err != nil {
+			// a crash while appending leaves the last line truncated
+			if next == len(data) {
+				break
+			}
+			return nil, fmt.Errorf("%s:%d: %v", path, line, err)
+		}
+		if data[next-1] != '\n' {
+			// complete record, its newline was not written
+			break
+		}
+		store.prices[record.Key] = record.Price
+		complete = next
+	}
+
+	if complete < len(data) {
+		if err := os.Truncate(path, int64(complete)); err != nil {
+			return nil, fmt.Errorf("%s: cutting truncated line: %v", path, err)
+		}
+	}
+
+	return store, nil
+}
+
+// FileStore keeps historical prices by provider, asset and minute
+type FileStore struct {
+	mu     sync.Mutex
+	path   string
+	prices map[string]float64
+}
+
+type fileStoreRecord struct {
+	Key   string  `json:"key"`
+	Price float64 `json:"price"`
+}
+
+// Get returns stored price
+func (s *FileStore) Get(provider, asset string, at time.Time) (float64, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	price, ok := s.prices[fileStoreKey(provider, asset, at)]
+	return price, ok
+}
+
+// Put stores price and appends it to the file, a price stored again replaces the previous one on load
+func (s *FileStore) Put(provider, asset string, at time.Time, price float64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record := fileStoreRecord{Key: fileStoreKey(provider, asset, at), Price: price}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	s.prices[record.Key] = price
+	return nil
+}
+
+func fileStoreKey(provider, asset string, at time.Time) string {
+	return fmt.Sprintf("%s/%s/%d", provider, normalizeAsset(asset), at.Truncate(time.Minute).Unix())
+}
+
+// NewCachedHistoricalPriceAPI creates historical price API reading through the store,
+// prices missing from the store are fetched from api and stored
+func NewCachedHistoricalPriceAPI(api HistoricalPriceAPI, store *FileStore) HistoricalPriceAPI {
+	return &cachedHistoricalPriceAPI{
+		api:   api,
+		store: store,
+	}
+}
+
+type cachedHistoricalPriceAPI struct {
+	api   HistoricalPriceAPI
+	store *FileStore
+}
+
+func (pa *cachedHistoricalPriceAPI) GetName() string {
+	return pa.api.GetName()
+}
+
+func (pa *cachedHistoricalPriceAPI) GetPriceAt(ctx context.Context, asset string, at time.Time) (float64, error) {
+	if price, ok := pa.store.Get(pa.api.GetName(), asset, at); ok {
+		return price, nil
+	}
+
+	price, err := pa.api.GetPriceAt(ctx, asset, at)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := pa.store.Put(pa.api.GetName(), asset, at, price); err != nil {
+		return 0, err
+	}
+
+	return price, nil
+}
@@ -0,0 +1,101 @@
+package priceapi
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileStoreReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prices.jsonl")
+	at := time.Date(2021, 5, 19, 12, 30, 0, 0, time.UTC)
+
+	store, err := NewFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := store.Put("coinbase", "ETH", at.Add(time.Duration(i)*time.Minute), float64(2000+i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// the last price stored for a minute wins
+	if err := store.Put("coinbase", "ETH", at, 1999); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded, err := NewFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for minute, want := range []float64{1999, 2001, 2002} {
+		got, ok := reloaded.Get("coinbase", "ETH", at.Add(time.Duration(minute)*time.Minute+10*time.Second))
+		if !ok || got != want {
+			t.Errorf("minute %d: got %v %v, want %v", minute, got, ok, want)
+		}
+	}
+	if _, ok := reloaded.Get("bitstamp", "ETH", at); ok {
+		t.Error("price of another provider found")
+	}
+}
+
+func TestFileStoreTruncated(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prices.jsonl")
+	data := `{"key":"coinbase/ETH/60","price":2000}` + "\n" + `{"key":"coinbase/ETH/120","pri`
+	if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := NewFileStore(path)
+	if err != nil {
+		t.Fatalf("truncated last line: %v", err)
+	}
+	if price, ok := store.Get("coinbase", "ETH", time.Unix(60, 0)); !ok || price != 2000 {
+		t.Errorf("got %v %v, want 2000", price, ok)
+	}
+
+	corrupt := `{"key":"coinbase/ETH/60","pri` + "\n" + `{"key":"coinbase/ETH/120","price":2000}` + "\n"
+	if err := ioutil.WriteFile(path, []byte(corrupt), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewFileStore(path); err == nil {
+		t.Error("corrupt line before the last one accepted")
+	}
+}
+
+func TestFileStorePutAfterTruncated(t *testing.T) {
+	for name, tail := range map[string]string{
+		"truncated record":       `{"key":"coinbase/ETH/120","pri`,
+		"record without newline": `{"key":"coinbase/ETH/120","price":2001}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "prices.jsonl")
+			data := `{"key":"coinbase/ETH/60","price":2000}` + "\n" + tail
+			if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			store, err := NewFileStore(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := store.Put("coinbase", "ETH", time.Unix(180, 0), 2002); err != nil {
+				t.Fatal(err)
+			}
+
+			reloaded, err := NewFileStore(path)
+			if err != nil {
+				t.Fatalf("reload after put: %v", err)
+			}
+			for at, want := range map[int64]float64{60: 2000, 180: 2002} {
+				if price, ok := reloaded.Get("coinbase", "ETH", time.Unix(at, 0)); !ok || price != want {
+					t.Errorf("%d: got %v %v, want %v", at, price, ok, want)
+				}
+			}
+			if _, ok := reloaded.Get("coinbase", "ETH", time.Unix(120, 0)); ok {
+				t.Error("price of the cut line found")
+			}
+		})
+	}
+}
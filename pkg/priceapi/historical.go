@@ -0,0 +1,71 @@
+package priceapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// HistoricalPriceAPI represents crypto price API able to return past prices
+type HistoricalPriceAPI interface {
+	// Get name returns crypto API name
+	GetName() string
+	// GetPriceAt returns asset USD price at the given time, asset is a ticker symbol such as BTC
+	GetPriceAt(ctx context.Context, asset string, at time.Time) (float64, error)
+}
+
+// providers supporting price history, their PriceAPI can be asserted to HistoricalPriceAPI
+var (
+	_ HistoricalPriceAPI = (*coingeckoPriceAPI)(nil)
+	_ HistoricalPriceAPI = (*coinbasePriceAPI)(nil)
+	_ HistoricalPriceAPI = (*bitstampPriceAPI)(nil)
+)
+
+func getJSON(ctx context.Context, client *http.Client, endpoint string, v interface{}) error {
+	getReq, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Do(getReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected http status code: %d", resp.StatusCode)
+	}
+
+	respData, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(respData, v)
+}
+
+// closestPoint returns index of the timestamp closest to at
+func closestPoint(timestamps []time.Time, at time.Time) int {
+	closest := -1
+	var closestDistance time.Duration
+	for i, t := range timestamps {
+		distance := t.Sub(at)
+		if distance < 0 {
+			distance = -distance
+		}
+		if closest == -1 || distance < closestDistance {
+			closest = i
+			closestDistance = distance
+		}
+	}
+	return closest
+}
+
+func normalizeAsset(asset string) string {
+	return strings.ToUpper(strings.TrimSpace(asset))
+}
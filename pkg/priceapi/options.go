@@ -0,0 +1,51 @@
+package priceapi
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Option configures HTTP price API
+type Option func(*httpOptions)
+
+// WithBaseURL sends requests to baseURL instead of the provider host, endpoint paths and queries are kept
+func WithBaseURL(baseURL string) Option {
+	return func(o *httpOptions) {
+		o.baseURL = strings.TrimRight(baseURL, "/")
+	}
+}
+
+// WithHTTPClient sets HTTP client used for requests, e.g. one with a timeout
+func WithHTTPClient(client *http.Client) Option {
+	return func(o *httpOptions) {
+		o.client = client
+	}
+}
+
+type httpOptions struct {
+	client  *http.Client
+	baseURL string
+}
+
+func newHTTPOptions(opts []Option) httpOptions {
+	o := httpOptions{
+		client: &http.Client{},
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// endpoint returns provider endpoint moved to the base URL when one is set
+func (o *httpOptions) endpoint(endpoint string) string {
+	if o.baseURL == "" {
+		return endpoint
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return endpoint
+	}
+	return o.baseURL + u.RequestURI()
+}
@@ -0,0 +1,93 @@
+package priceapi_test
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/priceapi"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/priceapi/priceapitest"
+)
+
+// clientTimeout is the HTTP client timeout the Timeout scenario runs into
+const clientTimeout = 200 * time.Millisecond
+
+type fetchFunc func(ctx context.Context, opts ...priceapi.Option) (float64, error)
+
+func spot(newAPI func(opts ...priceapi.Option) priceapi.PriceAPI) fetchFunc {
+	return func(ctx context.Context, opts ...priceapi.Option) (float64, error) {
+		return newAPI(opts...).GetPrice(ctx)
+	}
+}
+
+func history(t *testing.T, newAPI func(opts ...priceapi.Option) priceapi.PriceAPI) fetchFunc {
+	return func(ctx context.Context, opts ...priceapi.Option) (float64, error) {
+		api, ok := newAPI(opts...).(priceapi.HistoricalPriceAPI)
+		if !ok {
+			t.Fatal("provider has no price history")
+		}
+		return api.GetPriceAt(ctx, "BTC", priceapitest.HistoryTime)
+	}
+}
+
+func TestProviders(t *testing.T) {
+	server := priceapitest.NewServer()
+	defer server.Close()
+
+	client := &http.Client{Timeout: clientTimeout}
+	opts := []priceapi.Option{priceapi.WithBaseURL(server.URL), priceapi.WithHTTPClient(client)}
+
+	providers := []struct {
+		name     string
+		expected float64
+		fetch    fetchFunc
+	}{
+		{"CoinDesk GetPrice", priceapitest.CoindeskPrice, spot(priceapi.NewCoindeskPriceAPI)},
+		{"Blockchain.com GetPrice", priceapitest.BlockchainPrice, spot(priceapi.NewBlockchainPriceAPI)},
+		{"Coinbase GetPrice", priceapitest.CoinbasePrice, spot(priceapi.NewCoinbasePriceAPI)},
+		{"CoinGecko GetPrice", priceapitest.CoingeckoPrice, spot(priceapi.NewCoingeckoPriceAPI)},
+		{"Bitstamp GetPrice", priceapitest.BitstampPrice, spot(priceapi.NewBitstampPriceAPI)},
+		{"Coinbase GetPriceAt", priceapitest.CoinbaseHistoryPrice, history(t, priceapi.NewCoinbasePriceAPI)},
+		{"CoinGecko GetPriceAt", priceapitest.CoingeckoHistoryPrice, history(t, priceapi.NewCoingeckoPriceAPI)},
+		{"Bitstamp GetPriceAt", priceapitest.BitstampHistoryPrice, history(t, priceapi.NewBitstampPriceAPI)},
+	}
+
+	scenarios := []struct {
+		scenario priceapitest.Scenario
+		// errText is expected in the error, the price is expected when empty
+		errText string
+	}{
+		{priceapitest.OK, ""},
+		{priceapitest.ZeroPrice, "currency rate is 0"},
+		{priceapitest.MalformedJSON, "unexpected"},
+		{priceapitest.HTTPError, "unexpected http status code: 503"},
+		{priceapitest.Timeout, "Client.Timeout exceeded"},
+	}
+
+	for _, s := range scenarios {
+		server.SetScenario(s.scenario)
+		for _, p := range providers {
+			t.Run(s.scenario.String()+"/"+p.name, func(t *testing.T) {
+				price, err := p.fetch(context.Background(), opts...)
+				if s.errText == "" {
+					if err != nil {
+						t.Fatalf("unexpected error: %v", err)
+					}
+					if math.Abs(price-p.expected) > 1e-9 {
+						t.Errorf("got price %v, expected %v", price, p.expected)
+					}
+					return
+				}
+				if err == nil {
+					t.Fatalf("expected error containing %q, got price %v", s.errText, price)
+				}
+				if !strings.Contains(err.Error(), s.errText) {
+					t.Errorf("expected error containing %q, got %v", s.errText, err)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package priceapitest
+
+import "time"
+
+// HistoryTime is the time recorded history responses are centered on
+var HistoryTime = time.Date(2023, time.May, 1, 12, 0, 0, 0, time.UTC)
+
+// Expected prices in the recorded responses
+const (
+	CoindeskPrice         = 27019.447
+	BlockchainPrice       = 27010.5
+	CoinbasePrice         = 27005.36
+	CoingeckoPrice        = 27012
+	BitstampPrice         = 27011
+	CoinbaseHistoryPrice  = 29245.1
+	CoingeckoHistoryPrice = 29251.3
+	BitstampHistoryPrice  = 29248
+)
+
+type fixture struct {
+	ok   string
+	zero string
+}
+
+// fixtures are recorded provider responses keyed by request path, trimmed to the fields the providers read
+var fixtures = map[string]fixture{
+	"/v1/bpi/currentprice/USD.json": {
+		ok:   `{"time":{"updated":"May 1, 2023 12:00:00 UTC","updatedISO":"2023-05-01T12:00:00+00:00"},"disclaimer":"This data was produced from the CoinDesk Bitcoin Price Index (USD).","bpi":{"USD":{"code":"USD","rate":"27,019.4470","description":"United States Dollar","rate_float":27019.447}}}`,
+		zero: `{"time":{"updated":"May 1, 2023 12:00:00 UTC","updatedISO":"2023-05-01T12:00:00+00:00"},"bpi":{"USD":{"code":"USD","rate":"0.0000","description":"United States Dollar","rate_float":0}}}`,
+	},
+	"/ticker": {
+		ok:   `{"EUR":{"15m":24560.12,"last":24560.12,"buy":24560.12,"sell":24560.12,"symbol":"EUR"},"USD":{"15m":27010.5,"last":27010.5,"buy":27010.5,"sell":27010.5,"symbol":"USD"}}`,
+		zero: `{"USD":{"15m":0,"last":0,"buy":0,"sell":0,"symbol":"USD"}}`,
+	},
+	"/v2/prices/spot": {
+		ok:   `{"data":{"base":"BTC","currency":"USD","amount":"27005.36"}}`,
+		zero: `{"data":{"base":"BTC","currency":"USD","amount":"0.00"}}`,
+	},
+	"/products/BTC-USD/candles": {
+		ok:   `[[1682942460,29230.01,29262.4,29244.9,29255.5,3.21],[1682942400,29220.5,29250.2,29225.3,29245.1,4.13],[1682942340,29201.2,29231.7,29210.4,29224.9,2.08]]`,
+		zero: `[[1682942400,0,0,0,0,0]]`,
+	},
+	"/api/v3/coins/markets": {
+		ok:   `[{"id":"bitcoin","symbol":"btc","name":"Bitcoin","image":"https://assets.coingecko.com/coins/images/1/large/bitcoin.png","current_price":27012,"market_cap":523170359318,"total_volume":13612458349}]`,
+		zero: `[{"id":"bitcoin","symbol":"btc","name":"Bitcoin","image":"https://assets.coingecko.com/coins/images/1/large/bitcoin.png","current_price":0}]`,
+	},
+	"/api/v3/coins/bitcoin/market_chart/range": {
+		ok:   `{"prices":[[1682941020000,29210.7],[1682942170000,29240.2],[1682942473000,29251.3],[1682942771000,29262.8]],"market_caps":[[1682942473000,566530541612.1]],"total_volumes":[[1682942473000,13290511871.4]]}`,
+		zero: `{"prices":[[1682942473000,0]],"market_caps":[],"total_volumes":[]}`,
+	},
+	"/api/v2/ticker/btcusd/": {
+		ok:   `{"timestamp":"1682942400","open":"27002","high":"27330","low":"26880","last":"27011","volume":"1702.39421847","vwap":"27087","bid":"27010","ask":"27012","open_24":"27002","percent_change_24":"0.03"}`,
+		zero: `{"timestamp":"1682942400","open":"0","high":"0","low":"0","last":"0","volume":"0","vwap":"0","bid":"0","ask":"0"}`,
+	},
+	"/api/v2/ohlc/btcusd/": {
+		ok:   `{"data":{"pair":"BTC/USD","ohlc":[{"high":"29262","timestamp":"1682942400","volume":"1.50021","low":"29231","close":"29248","open":"29240"}]}}`,
+		zero: `{"data":{"pair":"BTC/USD","ohlc":[{"high":"0","timestamp":"1682942400","volume":"0","low":"0","close":"0","open":"0"}]}}`,
+	},
+}
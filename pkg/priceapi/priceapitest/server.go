@@ -0,0 +1,98 @@
+// Package priceapitest serves recorded price provider responses for offline checks
+package priceapitest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+)
+
+// Scenario selects how the server answers
+type Scenario int
+
+const (
+	// OK serves the recorded responses
+	OK Scenario = iota
+	// ZeroPrice serves responses quoting a zero price
+	ZeroPrice
+	// MalformedJSON serves truncated responses
+	MalformedJSON
+	// HTTPError answers 503 Service Unavailable
+	HTTPError
+	// Timeout answers after the client gave up, or after maxDelay
+	Timeout
+)
+
+func (s Scenario) String() string {
+	switch s {
+	case OK:
+		return "ok"
+	case ZeroPrice:
+		return "zero price"
+	case MalformedJSON:
+		return "malformed JSON"
+	case HTTPError:
+		return "HTTP error"
+	case Timeout:
+		return "timeout"
+	}
+	return "unknown"
+}
+
+// maxDelay bounds Timeout scenario responses so Close does not hang on clients without a timeout
+const maxDelay = 5 * time.Second
+
+// Server is httptest server answering every provider endpoint path, point providers at it with priceapi.WithBaseURL
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	scenario Scenario
+}
+
+// NewServer creates and starts new server serving the OK scenario, Close it when done
+func NewServer() *Server {
+	s := &Server{}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// SetScenario changes how the following requests are answered
+func (s *Server) SetScenario(scenario Scenario) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.scenario = scenario
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	scenario := s.scenario
+	s.mu.Unlock()
+
+	f, ok := fixtures[r.URL.Path]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	body := f.ok
+	switch scenario {
+	case ZeroPrice:
+		body = f.zero
+	case MalformedJSON:
+		body = f.ok[:len(f.ok)/2]
+	case HTTPError:
+		http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+		return
+	case Timeout:
+		select {
+		case <-r.Context().Done():
+			return
+		case <-time.After(maxDelay):
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(body))
+}
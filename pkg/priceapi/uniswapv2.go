@@ -0,0 +1,80 @@
+package priceapi
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/contracts"
+)
+
+// NewUniswapV2PriceAPI creates new Uniswap V2 pair price API
+func NewUniswapV2PriceAPI(caller bind.ContractCaller, pair DEXPair) (DEXPriceAPI, error) {
+	pairCaller, err := contracts.NewUniswapV2PairCaller(pair.Address, caller)
+	if err != nil {
+		return nil, err
+	}
+
+	return &uniswapV2PriceAPI{
+		pair:   pair,
+		caller: pairCaller,
+	}, nil
+}
+
+type uniswapV2PriceAPI struct {
+	pair   DEXPair
+	caller *contracts.UniswapV2PairCaller
+}
+
+func (pa *uniswapV2PriceAPI) GetName() string {
+	return "Uniswap V2"
+}
+
+func (pa *uniswapV2PriceAPI) GetPrice(ctx context.Context) (float64, error) {
+	reserveIn, reserveOut, err := pa.getReserves(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return ratio(reserveOut, pa.pair.QuoteDecimals, reserveIn, pa.pair.BaseDecimals), nil
+}
+
+func (pa *uniswapV2PriceAPI) GetQuote(ctx context.Context, amountIn *big.Int) (*Quote, error) {
+	if err := validateAmountIn(amountIn); err != nil {
+		return nil, err
+	}
+
+	reserveIn, reserveOut, err := pa.getReserves(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	spotPrice := ratio(reserveOut, pa.pair.QuoteDecimals, reserveIn, pa.pair.BaseDecimals)
+
+	// UniswapV2Library.getAmountOut, 0.3% fee
+	amountInWithFee := new(big.Int).Mul(amountIn, big.NewInt(997))
+	numerator := new(big.Int).Mul(amountInWithFee, reserveOut)
+	denominator := new(big.Int).Mul(reserveIn, big.NewInt(1000))
+	denominator.Add(denominator, amountInWithFee)
+	amountOut := numerator.Div(numerator, denominator)
+
+	return newQuote(spotPrice, amountIn, amountOut, pa.pair)
+}
+
+// getReserves returns pair reserves ordered as base, quote
+func (pa *uniswapV2PriceAPI) getReserves(ctx context.Context) (*big.Int, *big.Int, error) {
+	reserves, err := pa.caller.GetReserves(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if reserves.Reserve0.Sign() == 0 || reserves.Reserve1.Sign() == 0 {
+		return nil, nil, errors.New("pair reserves are 0")
+	}
+
+	if pa.pair.BaseIsToken0 {
+		return reserves.Reserve0, reserves.Reserve1, nil
+	}
+	return reserves.Reserve1, reserves.Reserve0, nil
+}
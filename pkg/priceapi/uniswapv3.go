@@ -0,0 +1,168 @@
+package priceapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/contracts"
+)
+
+// NewUniswapV3PriceAPI creates new Uniswap V3 pool price API.
+// GetPrice returns time weighted average price over twapWindow, or the spot price when twapWindow is 0.
+func NewUniswapV3PriceAPI(caller bind.ContractCaller, pair DEXPair, twapWindow time.Duration) (DEXPriceAPI, error) {
+	poolCaller, err := contracts.NewUniswapV3PoolCaller(pair.Address, caller)
+	if err != nil {
+		return nil, err
+	}
+
+	return &uniswapV3PriceAPI{
+		pair:       pair,
+		twapWindow: twapWindow,
+		caller:     poolCaller,
+	}, nil
+}
+
+type uniswapV3PriceAPI struct {
+	pair       DEXPair
+	twapWindow time.Duration
+	caller     *contracts.UniswapV3PoolCaller
+}
+
+func (pa *uniswapV3PriceAPI) GetName() string {
+	return "Uniswap V3"
+}
+
+func (pa *uniswapV3PriceAPI) GetPrice(ctx context.Context) (float64, error) {
+	opts := &bind.CallOpts{Context: ctx}
+
+	window := uint32(pa.twapWindow / time.Second)
+	if window == 0 {
+		slot0, err := pa.caller.Slot0(opts)
+		if err != nil {
+			return 0, err
+		}
+		return pa.sqrtPriceToPrice(slot0.SqrtPriceX96)
+	}
+
+	observation, err := pa.caller.Observe(opts, []uint32{window, 0})
+	if err != nil {
+		return 0, err
+	}
+
+	if len(observation.TickCumulatives) != 2 {
+		return 0, fmt.Errorf("unexpected observation length: %d", len(observation.TickCumulatives))
+	}
+
+	// OracleLibrary.consult, rounding to negative infinity
+	delta := new(big.Int).Sub(observation.TickCumulatives[1], observation.TickCumulatives[0])
+	tick, rem := new(big.Int).QuoRem(delta, big.NewInt(int64(window)), new(big.Int))
+	if delta.Sign() < 0 && rem.Sign() != 0 {
+		tick.Sub(tick, big.NewInt(1))
+	}
+
+	return pa.tickToPrice(tick.Int64()), nil
+}
+
+func (pa *uniswapV3PriceAPI) GetQuote(ctx context.Context, amountIn *big.Int) (*Quote, error) {
+	if err := validateAmountIn(amountIn); err != nil {
+		return nil, err
+	}
+
+	opts := &bind.CallOpts{Context: ctx}
+
+	slot0, err := pa.caller.Slot0(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	liquidity, err := pa.caller.Liquidity(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if liquidity.Sign() == 0 {
+		return nil, errors.New("pool has no in-range liquidity")
+	}
+
+	fee, err := pa.caller.Fee(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	spotPrice, err := pa.sqrtPriceToPrice(slot0.SqrtPriceX96)
+	if err != nil {
+		return nil, err
+	}
+
+	amountOut := swapWithinTick(slot0.SqrtPriceX96, liquidity, fee, amountIn, pa.pair.BaseIsToken0)
+
+	return newQuote(spotPrice, amountIn, amountOut, pa.pair)
+}
+
+// swapWithinTick returns swap output assuming the trade does not cross an initialized tick,
+// so the result is exact for trades that stay within current range and optimistic otherwise
+func swapWithinTick(sqrtPriceX96, liquidity, fee, amountIn *big.Int, zeroForOne bool) *big.Int {
+	amountInLessFee := new(big.Int).Sub(feeDenominator, fee)
+	amountInLessFee.Mul(amountInLessFee, amountIn)
+	amountInLessFee.Div(amountInLessFee, feeDenominator)
+
+	liquidityX96 := new(big.Int).Lsh(liquidity, 96)
+
+	if zeroForOne {
+		// SqrtPriceMath.getNextSqrtPriceFromAmount0RoundingUp
+		denominator := new(big.Int).Mul(amountInLessFee, sqrtPriceX96)
+		denominator.Add(denominator, liquidityX96)
+		sqrtPriceNextX96 := new(big.Int).Mul(liquidityX96, sqrtPriceX96)
+		sqrtPriceNextX96.Div(sqrtPriceNextX96, denominator)
+
+		// SqrtPriceMath.getAmount1Delta
+		amountOut := new(big.Int).Sub(sqrtPriceX96, sqrtPriceNextX96)
+		amountOut.Mul(amountOut, liquidity)
+		return amountOut.Rsh(amountOut, 96)
+	}
+
+	// SqrtPriceMath.getNextSqrtPriceFromAmount1RoundingDown
+	sqrtPriceNextX96 := new(big.Int).Lsh(amountInLessFee, 96)
+	sqrtPriceNextX96.Div(sqrtPriceNextX96, liquidity)
+	sqrtPriceNextX96.Add(sqrtPriceNextX96, sqrtPriceX96)
+
+	// SqrtPriceMath.getAmount0Delta
+	amountOut := new(big.Int).Sub(sqrtPriceNextX96, sqrtPriceX96)
+	amountOut.Mul(amountOut, liquidityX96)
+	amountOut.Div(amountOut, sqrtPriceNextX96)
+	return amountOut.Div(amountOut, sqrtPriceX96)
+}
+
+func (pa *uniswapV3PriceAPI) sqrtPriceToPrice(sqrtPriceX96 *big.Int) (float64, error) {
+	if sqrtPriceX96.Sign() == 0 {
+		return 0, errors.New("pool is not initialized")
+	}
+
+	sqrtPrice := new(big.Float).SetInt(sqrtPriceX96)
+	sqrtPrice.Quo(sqrtPrice, new(big.Float).SetInt(q96))
+	price, _ := new(big.Float).Mul(sqrtPrice, sqrtPrice).Float64()
+
+	return pa.adjustPrice(price), nil
+}
+
+func (pa *uniswapV3PriceAPI) tickToPrice(tick int64) float64 {
+	return pa.adjustPrice(math.Pow(1.0001, float64(tick)))
+}
+
+// adjustPrice converts raw token1/token0 pool price to base price in quote units
+func (pa *uniswapV3PriceAPI) adjustPrice(price float64) float64 {
+	if !pa.pair.BaseIsToken0 {
+		price = 1 / price
+	}
+	return price * math.Pow10(int(pa.pair.BaseDecimals)-int(pa.pair.QuoteDecimals))
+}
+
+var (
+	q96            = new(big.Int).Lsh(big.NewInt(1), 96)
+	feeDenominator = big.NewInt(1000000)
+)
@@ -0,0 +1,177 @@
+package rpcpool
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/go-kit/kit/log/level"
+)
+
+func (p *Pool) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) (code []byte, err error) {
+	err = p.read(ctx, func(ctx context.Context, client Client) (err error) {
+		code, err = client.CodeAt(ctx, contract, blockNumber)
+		return err
+	})
+	return code, err
+}
+
+func (p *Pool) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) (result []byte, err error) {
+	err = p.read(ctx, func(ctx context.Context, client Client) (err error) {
+		result, err = client.CallContract(ctx, call, blockNumber)
+		return err
+	})
+	return result, err
+}
+
+func (p *Pool) HeaderByNumber(ctx context.Context, number *big.Int) (header *types.Header, err error) {
+	err = p.read(ctx, func(ctx context.Context, client Client) (err error) {
+		header, err = client.HeaderByNumber(ctx, number)
+		return err
+	})
+	return header, err
+}
+
+func (p *Pool) PendingCodeAt(ctx context.Context, account common.Address) (code []byte, err error) {
+	err = p.read(ctx, func(ctx context.Context, client Client) (err error) {
+		code, err = client.PendingCodeAt(ctx, account)
+		return err
+	})
+	return code, err
+}
+
+func (p *Pool) PendingNonceAt(ctx context.Context, account common.Address) (nonce uint64, err error) {
+	err = p.read(ctx, func(ctx context.Context, client Client) (err error) {
+		nonce, err = client.PendingNonceAt(ctx, account)
+		return err
+	})
+	return nonce, err
+}
+
+func (p *Pool) SuggestGasPrice(ctx context.Context) (price *big.Int, err error) {
+	err = p.read(ctx, func(ctx context.Context, client Client) (err error) {
+		price, err = client.SuggestGasPrice(ctx)
+		return err
+	})
+	return price, err
+}
+
+func (p *Pool) SuggestGasTipCap(ctx context.Context) (tip *big.Int, err error) {
+	err = p.read(ctx, func(ctx context.Context, client Client) (err error) {
+		tip, err = client.SuggestGasTipCap(ctx)
+		return err
+	})
+	return tip, err
+}
+
+func (p *Pool) EstimateGas(ctx context.Context, call ethereum.CallMsg) (gas uint64, err error) {
+	err = p.read(ctx, func(ctx context.Context, client Client) (err error) {
+		gas, err = client.EstimateGas(ctx, call)
+		return err
+	})
+	return gas, err
+}
+
+func (p *Pool) FilterLogs(ctx context.Context, query ethereum.FilterQuery) (logs []types.Log, err error) {
+	err = p.read(ctx, func(ctx context.Context, client Client) (err error) {
+		logs, err = client.FilterLogs(ctx, query)
+		return err
+	})
+	return logs, err
+}
+
+// SubscribeFilterLogs subscribes with the best node, the subscription is not moved when the node fails
+func (p *Pool) SubscribeFilterLogs(ctx context.Context, query ethereum.FilterQuery, ch chan<- types.Log) (sub ethereum.Subscription, err error) {
+	candidates := p.candidates()
+	if len(candidates) == 0 {
+		return nil, errors.New("no RPC node connected")
+	}
+	return candidates[0].client.SubscribeFilterLogs(ctx, query, ch)
+}
+
+func (p *Pool) TransactionReceipt(ctx context.Context, txHash common.Hash) (receipt *types.Receipt, err error) {
+	err = p.read(ctx, func(ctx context.Context, client Client) (err error) {
+		receipt, err = client.TransactionReceipt(ctx, txHash)
+		return err
+	})
+	return receipt, err
+}
+
+func (p *Pool) BlockNumber(ctx context.Context) (number uint64, err error) {
+	err = p.read(ctx, func(ctx context.Context, client Client) (err error) {
+		number, err = client.BlockNumber(ctx)
+		return err
+	})
+	return number, err
+}
+
+func (p *Pool) ChainID(ctx context.Context) (chainID *big.Int, err error) {
+	err = p.read(ctx, func(ctx context.Context, client Client) (err error) {
+		chainID, err = client.ChainID(ctx)
+		return err
+	})
+	return chainID, err
+}
+
+func (p *Pool) TransactionByHash(ctx context.Context, hash common.Hash) (tx *types.Transaction, pending bool, err error) {
+	err = p.read(ctx, func(ctx context.Context, client Client) (err error) {
+		tx, pending, err = client.TransactionByHash(ctx, hash)
+		return err
+	})
+	return tx, pending, err
+}
+
+func (p *Pool) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (balance *big.Int, err error) {
+	err = p.read(ctx, func(ctx context.Context, client Client) (err error) {
+		balance, err = client.BalanceAt(ctx, account, blockNumber)
+		return err
+	})
+	return balance, err
+}
+
+// SendTransaction broadcasts tx to every connected node at once, unhealthy ones included, it succeeds when one node accepted it.
+// When none did the error of the best node is returned.
+func (p *Pool) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	candidates := p.candidates()
+	if len(candidates) == 0 {
+		return errors.New("no RPC node connected")
+	}
+
+	errs := make([]error, len(candidates))
+	var wg sync.WaitGroup
+	for i, c := range candidates {
+		wg.Add(1)
+		go func(i int, c candidate) {
+			defer wg.Done()
+			callCtx, cancel := context.WithTimeout(ctx, p.callTimeout)
+			defer cancel()
+			errs[i] = c.client.SendTransaction(callCtx, tx)
+		}(i, c)
+	}
+	wg.Wait()
+
+	accepted := false
+	for i, err := range errs {
+		if err == nil || isKnown(err) {
+			accepted = true
+			continue
+		}
+		// a rejection by one node is expected when another already propagated tx, health checks catch failing nodes
+		level.Debug(p.logger).Log("msg", "RPC node rejected transaction", "node", candidates[i].node.Name, "tx", tx.Hash().Hex(), "err", err)
+	}
+	if accepted {
+		return nil
+	}
+	return errs[0]
+}
+
+// isKnown reports whether the node rejected the transaction because it already has it
+func isKnown(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "already known") || strings.Contains(msg, "known transaction")
+}
@@ -0,0 +1,75 @@
+package rpcpool
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// limiter is a token bucket allowing rate requests per second and bursts of burst requests,
+// a nil limiter allows everything
+type limiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newLimiter(rate float64, burst int) *limiter {
+	if rate <= 0 {
+		return nil
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &limiter{rate: rate, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+// refill adds the tokens earned since the last call, l.mu is held
+func (l *limiter) refill() {
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+}
+
+// available returns the number of requests that can be sent without waiting,
+// negative when requests are already waiting
+func (l *limiter) available() float64 {
+	if l == nil {
+		return math.Inf(1)
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.refill()
+	return l.tokens
+}
+
+// wait takes a token, waiting until it is earned or ctx is done
+func (l *limiter) wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	l.refill()
+	l.tokens--
+	delay := time.Duration(-l.tokens / l.rate * float64(time.Second))
+	l.mu.Unlock()
+	if delay <= 0 {
+		return nil
+	}
+
+	select {
+	case <-time.After(delay):
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
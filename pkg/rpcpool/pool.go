@@ -0,0 +1,394 @@
+// Package rpcpool spreads chain access over several nodes: reads go to the fastest healthy one
+// and fail over to the next, transactions are broadcast to all of them
+package rpcpool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+)
+
+// Client represents chain access of a node, *ethclient.Client implements it
+type Client interface {
+	bind.ContractBackend
+	bind.DeployBackend
+	BlockNumber(ctx context.Context) (uint64, error)
+	ChainID(ctx context.Context) (*big.Int, error)
+	TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error)
+	BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error)
+}
+
+// Node represents pool member, Dial is called again when the node has no client
+type Node struct {
+	Name string
+	Dial func(ctx context.Context) (Client, error)
+}
+
+// URLNode returns node dialing rawURL with ethclient, named by its host
+func URLNode(rawURL string) Node {
+	return Node{
+		Name: hostOf(rawURL),
+		Dial: func(ctx context.Context) (Client, error) {
+			return ethclient.DialContext(ctx, rawURL)
+		},
+	}
+}
+
+// Option configures pool
+type Option func(*Pool)
+
+// WithChainID makes nodes on another chain unhealthy
+func WithChainID(chainID *big.Int) Option {
+	return func(p *Pool) {
+		p.chainID = chainID
+	}
+}
+
+// WithMaxLag sets the number of blocks a node may be behind the highest head and stay healthy
+func WithMaxLag(blocks uint64) Option {
+	return func(p *Pool) {
+		p.maxLag = blocks
+	}
+}
+
+// WithCallTimeout sets the time a node has to answer before the call fails over
+func WithCallTimeout(timeout time.Duration) Option {
+	return func(p *Pool) {
+		p.callTimeout = timeout
+	}
+}
+
+// WithRateLimit limits the reads sent to each node to rate per second with bursts of burst,
+// transactions and health checks are not limited
+func WithRateLimit(rate float64, burst int) Option {
+	return func(p *Pool) {
+		p.rate, p.burst = rate, burst
+	}
+}
+
+// Pool is a Client routing calls over nodes
+type Pool struct {
+	logger      log.Logger
+	nodes       []*node
+	chainID     *big.Int
+	maxLag      uint64
+	callTimeout time.Duration
+	rate        float64
+	burst       int
+}
+
+type node struct {
+	Node
+	// limit is nil without rate limit
+	limit *limiter
+
+	mu      sync.Mutex
+	client  Client
+	healthy bool
+	head    uint64
+	latency time.Duration
+	// checked is set once chain id matched
+	checked bool
+	lastErr error
+}
+
+// NodeStatus represents node state after the last health check
+type NodeStatus struct {
+	Name    string
+	Healthy bool
+	Head    uint64
+	Latency time.Duration
+	Err     error
+}
+
+// New creates pool of nodes and checks them, it fails when no node is healthy
+func New(ctx context.Context, logger log.Logger, nodes []Node, opts ...Option) (*Pool, error) {
+	if len(nodes) == 0 {
+		return nil, errors.New("no RPC node")
+	}
+
+	p := &Pool{
+		logger:      logger,
+		maxLag:      defaultMaxLag,
+		callTimeout: defaultCallTimeout,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	for _, n := range nodes {
+		p.nodes = append(p.nodes, &node{Node: n, limit: newLimiter(p.rate, p.burst)})
+	}
+
+	p.Check(ctx)
+	for _, status := range p.Status() {
+		if status.Healthy {
+			return p, nil
+		}
+	}
+
+	var problems []string
+	for _, status := range p.Status() {
+		problems = append(problems, fmt.Sprintf("%s: %v", status.Name, status.Err))
+	}
+	return nil, errors.New("no healthy RPC node: " + strings.Join(problems, "; "))
+}
+
+// Run checks nodes every interval until ctx is done
+func (p *Pool) Run(ctx context.Context, interval time.Duration) {
+	for {
+		select {
+		case <-time.After(interval):
+			p.Check(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Check dials nodes without client and measures head block and latency of every node,
+// nodes failing, on another chain or behind the highest head by more than max lag are unhealthy
+func (p *Pool) Check(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, n := range p.nodes {
+		wg.Add(1)
+		go func(n *node) {
+			defer wg.Done()
+			p.checkNode(ctx, n)
+		}(n)
+	}
+	wg.Wait()
+
+	var maxHead uint64
+	for _, n := range p.nodes {
+		n.mu.Lock()
+		if n.lastErr == nil && n.head > maxHead {
+			maxHead = n.head
+		}
+		n.mu.Unlock()
+	}
+
+	for _, n := range p.nodes {
+		n.mu.Lock()
+		healthy := n.lastErr == nil && n.head+p.maxLag >= maxHead
+		if healthy != n.healthy {
+			if healthy {
+				level.Info(p.logger).Log("msg", "RPC node healthy", "node", n.Name, "block", n.head, "latency", n.latency)
+			} else {
+				level.Warn(p.logger).Log("msg", "RPC node unhealthy", "node", n.Name, "block", n.head, "max_block", maxHead, "err", n.lastErr)
+			}
+		}
+		n.healthy = healthy
+		n.mu.Unlock()
+	}
+}
+
+func (p *Pool) checkNode(ctx context.Context, n *node) {
+	ctx, cancel := context.WithTimeout(ctx, p.callTimeout)
+	defer cancel()
+
+	n.mu.Lock()
+	client := n.client
+	n.mu.Unlock()
+
+	if client == nil {
+		dialed, err := n.Dial(ctx)
+		if err != nil {
+			p.setError(n, fmt.Errorf("dialing: %v", err))
+			return
+		}
+		client = dialed
+		n.mu.Lock()
+		n.client = client
+		n.mu.Unlock()
+	}
+
+	n.mu.Lock()
+	checked := n.checked
+	n.mu.Unlock()
+	if !checked && p.chainID != nil {
+		chainID, err := client.ChainID(ctx)
+		if err != nil {
+			p.setError(n, fmt.Errorf("getting chain id: %v", err))
+			return
+		}
+		if chainID.Cmp(p.chainID) != 0 {
+			p.setError(n, fmt.Errorf("chain id %v, expected %v", chainID, p.chainID))
+			return
+		}
+	}
+
+	begin := time.Now()
+	head, err := client.BlockNumber(ctx)
+	if err != nil {
+		p.setError(n, err)
+		return
+	}
+	latency := time.Since(begin)
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.checked = true
+	n.head = head
+	n.lastErr = nil
+	if n.latency == 0 {
+		n.latency = latency
+	} else {
+		// smooth out single slow answers
+		n.latency = (n.latency*7 + latency*3) / 10
+	}
+}
+
+func (p *Pool) setError(n *node, err error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.lastErr = err
+}
+
+// Close closes the node clients that can be closed
+func (p *Pool) Close() {
+	for _, n := range p.nodes {
+		n.mu.Lock()
+		if closer, ok := n.client.(interface{ Close() }); ok {
+			closer.Close()
+		}
+		n.client = nil
+		n.mu.Unlock()
+	}
+}
+
+// Status returns state of every node in configured order
+func (p *Pool) Status() []NodeStatus {
+	statuses := make([]NodeStatus, len(p.nodes))
+	for i, n := range p.nodes {
+		n.mu.Lock()
+		statuses[i] = NodeStatus{Name: n.Name, Healthy: n.healthy, Head: n.head, Latency: n.latency, Err: n.lastErr}
+		n.mu.Unlock()
+	}
+	return statuses
+}
+
+// candidate is a node client to try
+type candidate struct {
+	node   *node
+	client Client
+}
+
+// candidates returns healthy nodes by latency then the unhealthy ones, as a last resort.
+// When the fastest healthy node is rate limited the healthy node with the fewest waiting requests comes first.
+func (p *Pool) candidates() []candidate {
+	type ranked struct {
+		candidate
+		healthy bool
+		latency time.Duration
+	}
+
+	var nodes []ranked
+	for _, n := range p.nodes {
+		n.mu.Lock()
+		if n.client != nil {
+			nodes = append(nodes, ranked{candidate{n, n.client}, n.healthy, n.latency})
+		}
+		n.mu.Unlock()
+	}
+
+	sort.SliceStable(nodes, func(i, j int) bool {
+		if nodes[i].healthy != nodes[j].healthy {
+			return nodes[i].healthy
+		}
+		return nodes[i].latency < nodes[j].latency
+	})
+
+	if len(nodes) > 0 && nodes[0].healthy && nodes[0].node.limit.available() < 1 {
+		best, most := 0, nodes[0].node.limit.available()
+		for i := 1; i < len(nodes) && nodes[i].healthy; i++ {
+			if available := nodes[i].node.limit.available(); available > most {
+				best, most = i, available
+			}
+		}
+		first := nodes[best]
+		copy(nodes[1:best+1], nodes[:best])
+		nodes[0] = first
+	}
+
+	result := make([]candidate, len(nodes))
+	for i, n := range nodes {
+		result[i] = n.candidate
+	}
+	return result
+}
+
+// read calls fn with the best node and fails over to the next one while the node, not the request, fails
+func (p *Pool) read(ctx context.Context, fn func(ctx context.Context, client Client) error) error {
+	err := errors.New("no RPC node connected")
+	for _, c := range p.candidates() {
+		if err := c.node.limit.wait(ctx); err != nil {
+			return err
+		}
+		callCtx, cancel := context.WithTimeout(ctx, p.callTimeout)
+		err = fn(callCtx, c.client)
+		cancel()
+
+		if !isNodeError(ctx, err) {
+			return err
+		}
+		p.failed(c.node, err)
+	}
+	return err
+}
+
+// failed marks node unhealthy until its next successful check
+func (p *Pool) failed(n *node, err error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.healthy {
+		level.Warn(p.logger).Log("msg", "RPC node failed, failing over", "node", n.Name, "err", err)
+	}
+	n.healthy = false
+	n.lastErr = err
+}
+
+// isNodeError reports whether err is the node failing rather than an answer to the request
+func isNodeError(ctx context.Context, err error) bool {
+	if err == nil || ctx.Err() != nil || errors.Is(err, ethereum.NotFound) {
+		return false
+	}
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		return false
+	}
+	// execution reverted and no contract code errors come from the node answering
+	if strings.Contains(err.Error(), "execution reverted") || errors.Is(err, bind.ErrNoCode) {
+		return false
+	}
+	return true
+}
+
+// hostOf names node by host, paths and credentials often hold API keys
+func hostOf(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		// IPC socket path
+		return rawURL
+	}
+	return u.Host
+}
+
+const (
+	defaultMaxLag      = 3
+	defaultCallTimeout = 10 * time.Second
+)
@@ -0,0 +1,291 @@
+package rpcpool_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/go-kit/kit/log"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/rpcpool"
+)
+
+// fakeClient is a node answering reads with its name after delay, down makes every call fail like
+// an unreachable node
+type fakeClient struct {
+	rpcpool.Client
+	name    string
+	delay   time.Duration
+	head    uint64
+	sendErr error
+
+	mu      sync.Mutex
+	down    bool
+	callErr error
+	calls   int
+	sent    []common.Hash
+}
+
+func (c *fakeClient) wait(ctx context.Context) error {
+	select {
+	case <-time.After(c.delay):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.down {
+		return errors.New("dial tcp " + c.name + ": connection refused")
+	}
+	return nil
+}
+
+func (c *fakeClient) setDown(down bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.down = down
+}
+
+func (c *fakeClient) served() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.calls
+}
+
+func (c *fakeClient) BlockNumber(ctx context.Context) (uint64, error) {
+	if err := c.wait(ctx); err != nil {
+		return 0, err
+	}
+	return c.head, nil
+}
+
+func (c *fakeClient) ChainID(ctx context.Context) (*big.Int, error) {
+	if err := c.wait(ctx); err != nil {
+		return nil, err
+	}
+	return big.NewInt(1), nil
+}
+
+func (c *fakeClient) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	if err := c.wait(ctx); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls++
+	return []byte(c.name), c.callErr
+}
+
+func (c *fakeClient) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	c.mu.Lock()
+	c.sent = append(c.sent, tx.Hash())
+	c.mu.Unlock()
+	if err := c.wait(ctx); err != nil {
+		return err
+	}
+	return c.sendErr
+}
+
+func nodes(clients ...*fakeClient) []rpcpool.Node {
+	var result []rpcpool.Node
+	for _, c := range clients {
+		c := c
+		result = append(result, rpcpool.Node{Name: c.name, Dial: func(ctx context.Context) (rpcpool.Client, error) {
+			return c, nil
+		}})
+	}
+	return result
+}
+
+// read returns the name of the node serving a call
+func read(t *testing.T, pool *rpcpool.Pool) string {
+	t.Helper()
+	result, err := pool.CallContract(context.Background(), ethereum.CallMsg{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(result)
+}
+
+func healthy(pool *rpcpool.Pool) map[string]bool {
+	result := map[string]bool{}
+	for _, status := range pool.Status() {
+		result[status.Name] = status.Healthy
+	}
+	return result
+}
+
+// rpcError is a node answering the request with an error
+type rpcError struct{}
+
+func (rpcError) Error() string  { return "invalid argument" }
+func (rpcError) ErrorCode() int { return -32602 }
+
+func TestPoolRouting(t *testing.T) {
+	fast := &fakeClient{name: "fast", head: 100}
+	slow := &fakeClient{name: "slow", delay: 20 * time.Millisecond, head: 100}
+	failing := &fakeClient{name: "failing", head: 100, down: true}
+	lagging := &fakeClient{name: "lagging", head: 90}
+
+	pool, err := rpcpool.New(context.Background(), log.NewNopLogger(), nodes(slow, failing, lagging, fast),
+		rpcpool.WithChainID(big.NewInt(1)), rpcpool.WithCallTimeout(time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]bool{"fast": true, "slow": true, "failing": false, "lagging": false}
+	if got := healthy(pool); !equal(got, want) {
+		t.Fatalf("healthy %v, want %v", got, want)
+	}
+
+	// the fastest healthy node serves reads
+	for i := 0; i < 3; i++ {
+		if got := read(t, pool); got != "fast" {
+			t.Errorf("read served by %s, want fast", got)
+		}
+	}
+
+	// an error answering the request is returned without failing over
+	fast.mu.Lock()
+	fast.callErr = rpcError{}
+	fast.mu.Unlock()
+	if _, err := pool.CallContract(context.Background(), ethereum.CallMsg{}, nil); !errors.As(err, new(rpcError)) {
+		t.Errorf("got %v, want the request error", err)
+	}
+	if slow.served() != 0 || !healthy(pool)["fast"] {
+		t.Error("request error failed over")
+	}
+	fast.mu.Lock()
+	fast.callErr = nil
+	fast.mu.Unlock()
+
+	// the failing node fails over to the next healthy one and stays behind it until checked again
+	fast.setDown(true)
+	if got := read(t, pool); got != "slow" {
+		t.Errorf("read served by %s after fast failed, want slow", got)
+	}
+	if healthy(pool)["fast"] {
+		t.Error("failed node still healthy")
+	}
+	fast.setDown(false)
+	if got := read(t, pool); got != "slow" {
+		t.Errorf("read served by %s before fast is checked, want slow", got)
+	}
+
+	pool.Check(context.Background())
+	if got := read(t, pool); got != "fast" {
+		t.Errorf("read served by %s after fast recovered, want fast", got)
+	}
+	if failing.served() != 0 || lagging.served() != 0 {
+		t.Errorf("unhealthy nodes served %d and %d reads", failing.served(), lagging.served())
+	}
+
+	// the unhealthy nodes are the last resort
+	fast.setDown(true)
+	slow.setDown(true)
+	if got := read(t, pool); got != "lagging" {
+		t.Errorf("read served by %s with every healthy node down, want lagging", got)
+	}
+}
+
+func TestPoolTimeout(t *testing.T) {
+	stuck := &fakeClient{name: "stuck", head: 100}
+	backup := &fakeClient{name: "backup", delay: 10 * time.Millisecond, head: 100}
+
+	pool, err := rpcpool.New(context.Background(), log.NewNopLogger(), nodes(stuck, backup), rpcpool.WithCallTimeout(50*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the node stops answering in time, the call fails over once it times out
+	stuck.delay = time.Second
+	if got := read(t, pool); got != "backup" {
+		t.Errorf("read served by %s, want backup", got)
+	}
+}
+
+func TestPoolNoHealthyNode(t *testing.T) {
+	down := &fakeClient{name: "down", down: true}
+	undialable := rpcpool.Node{Name: "undialable", Dial: func(ctx context.Context) (rpcpool.Client, error) {
+		return nil, errors.New("no route to host")
+	}}
+
+	_, err := rpcpool.New(context.Background(), log.NewNopLogger(), append(nodes(down), undialable))
+	if err == nil {
+		t.Fatal("pool created without a healthy node")
+	}
+	for _, name := range []string{"down", "undialable"} {
+		if !strings.Contains(err.Error(), name) {
+			t.Errorf("error %q does not name %s", err, name)
+		}
+	}
+}
+
+func TestPoolSendTransaction(t *testing.T) {
+	tx := types.NewTx(&types.LegacyTx{Nonce: 7, GasPrice: big.NewInt(1), Gas: 21000, To: &common.Address{}})
+
+	tests := []struct {
+		name     string
+		sendErrs map[string]error
+		err      string
+	}{
+		{
+			name:     "one node rejects",
+			sendErrs: map[string]error{"rejecting": errors.New("nonce too low")},
+		},
+		{
+			name:     "already known",
+			sendErrs: map[string]error{"fast": errors.New("already known"), "slow": errors.New("replacement transaction underpriced"), "rejecting": errors.New("nonce too low")},
+		},
+		{
+			name:     "every node rejects",
+			sendErrs: map[string]error{"fast": errors.New("insufficient funds"), "slow": errors.New("nonce too low"), "rejecting": errors.New("nonce too low")},
+			err:      "insufficient funds",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fast := &fakeClient{name: "fast", head: 100, sendErr: tt.sendErrs["fast"]}
+			slow := &fakeClient{name: "slow", delay: 20 * time.Millisecond, head: 100, sendErr: tt.sendErrs["slow"]}
+			rejecting := &fakeClient{name: "rejecting", delay: 5 * time.Millisecond, head: 100, sendErr: tt.sendErrs["rejecting"]}
+			failing := &fakeClient{name: "failing", head: 100, down: true}
+
+			pool, err := rpcpool.New(context.Background(), log.NewNopLogger(), nodes(slow, rejecting, failing, fast))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = pool.SendTransaction(context.Background(), tx)
+			switch {
+			case tt.err == "" && err != nil:
+				t.Errorf("send failed: %v", err)
+			case tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)):
+				t.Errorf("got %v, want the error of the fastest node %q", err, tt.err)
+			}
+
+			// broadcast to every connected node, unhealthy ones included
+			for _, c := range []*fakeClient{fast, slow, rejecting, failing} {
+				if len(c.sent) != 1 || c.sent[0] != tx.Hash() {
+					t.Errorf("%s received %v, want %s", c.name, c.sent, tx.Hash().Hex())
+				}
+			}
+		})
+	}
+}
+
+func equal(a, b map[string]bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if bv, ok := b[k]; !ok || bv != v {
+			return false
+		}
+	}
+	return true
+}
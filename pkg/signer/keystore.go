@@ -0,0 +1,30 @@
+package signer
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+)
+
+// NewKeystoreSigner creates signer from go-ethereum encrypted JSON key file,
+// the passphrase is read from passwordFile and the key is decrypted once
+func NewKeystoreSigner(keyFile, passwordFile string) (Signer, error) {
+	keyJSON, err := ioutil.ReadFile(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("reading keystore: %v", err)
+	}
+
+	password, err := ioutil.ReadFile(passwordFile)
+	if err != nil {
+		return nil, fmt.Errorf("reading keystore password: %v", err)
+	}
+
+	key, err := keystore.DecryptKey(keyJSON, strings.TrimRight(string(password), "\r\n"))
+	if err != nil {
+		return nil, fmt.Errorf("decrypting keystore %s: %v", keyFile, err)
+	}
+
+	return NewPrivateKeySigner(key.PrivateKey), nil
+}
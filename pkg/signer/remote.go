@@ -0,0 +1,118 @@
+package signer
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	// ClefSignMethod is the Clef external API signing method
+	ClefSignMethod = "account_signTransaction"
+	// NodeSignMethod is the node API signing method, the account must be unlocked on the node
+	NodeSignMethod = "eth_signTransaction"
+)
+
+// NewRemoteSigner creates signer delegating to JSON-RPC signer at endpoint, such as Clef,
+// method is ClefSignMethod or NodeSignMethod, ClefSignMethod when empty
+func NewRemoteSigner(ctx context.Context, endpoint, method string, address common.Address) (Signer, error) {
+	if method == "" {
+		method = ClefSignMethod
+	}
+
+	if method != ClefSignMethod && method != NodeSignMethod {
+		return nil, fmt.Errorf("unsupported signer method %q", method)
+	}
+
+	client, err := rpc.DialContext(ctx, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("dialing signer: %v", err)
+	}
+
+	return &remoteSigner{
+		client:  client,
+		method:  method,
+		address: address,
+	}, nil
+}
+
+type remoteSigner struct {
+	client  *rpc.Client
+	method  string
+	address common.Address
+}
+
+func (s *remoteSigner) Address() common.Address {
+	return s.address
+}
+
+func (s *remoteSigner) Close() {
+	s.client.Close()
+}
+
+func (s *remoteSigner) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	args := signTxArgs{
+		From:    s.address,
+		To:      tx.To(),
+		Gas:     hexutil.Uint64(tx.Gas()),
+		Value:   (*hexutil.Big)(tx.Value()),
+		Nonce:   hexutil.Uint64(tx.Nonce()),
+		Data:    hexutil.Bytes(tx.Data()),
+		ChainID: (*hexutil.Big)(chainID),
+	}
+
+	if tx.Type() == types.DynamicFeeTxType {
+		args.MaxFeePerGas = (*hexutil.Big)(tx.GasFeeCap())
+		args.MaxPriorityFeePerGas = (*hexutil.Big)(tx.GasTipCap())
+	} else {
+		args.GasPrice = (*hexutil.Big)(tx.GasPrice())
+	}
+
+	result := new(signTxResult)
+	if err := s.client.CallContext(ctx, result, s.method, args); err != nil {
+		return nil, fmt.Errorf("%s: %v", s.method, err)
+	}
+
+	signed := new(types.Transaction)
+	if err := signed.UnmarshalBinary(result.Raw); err != nil {
+		return nil, fmt.Errorf("decoding signed transaction: %v", err)
+	}
+
+	// never broadcast something other than what was asked for
+	sender, err := types.Sender(types.LatestSignerForChainID(chainID), signed)
+	if err != nil {
+		return nil, fmt.Errorf("recovering signed transaction sender: %v", err)
+	}
+	if sender != s.address {
+		return nil, fmt.Errorf("transaction signed by %s, expected %s", sender.Hex(), s.address.Hex())
+	}
+	if signed.Nonce() != tx.Nonce() || signed.Gas() != tx.Gas() || signed.Value().Cmp(tx.Value()) != 0 ||
+		string(signed.Data()) != string(tx.Data()) || (signed.To() == nil) != (tx.To() == nil) ||
+		(tx.To() != nil && *signed.To() != *tx.To()) {
+		return nil, fmt.Errorf("signed transaction %s does not match request", signed.Hash().Hex())
+	}
+
+	return signed, nil
+}
+
+type signTxArgs struct {
+	From                 common.Address  `json:"from"`
+	To                   *common.Address `json:"to,omitempty"`
+	Gas                  hexutil.Uint64  `json:"gas"`
+	GasPrice             *hexutil.Big    `json:"gasPrice,omitempty"`
+	MaxFeePerGas         *hexutil.Big    `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas *hexutil.Big    `json:"maxPriorityFeePerGas,omitempty"`
+	Value                *hexutil.Big    `json:"value"`
+	Nonce                hexutil.Uint64  `json:"nonce"`
+	Data                 hexutil.Bytes   `json:"data"`
+	ChainID              *hexutil.Big    `json:"chainId,omitempty"`
+}
+
+type signTxResult struct {
+	Raw hexutil.Bytes `json:"raw"`
+}
@@ -0,0 +1,77 @@
+package signer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// clefAccount is a stand-in for the Clef account API signing with key
+type clefAccount struct {
+	key *ecdsa.PrivateKey
+}
+
+func (c *clefAccount) SignTransaction(args signTxArgs) (*signTxResult, error) {
+	tx := types.NewTx(&types.LegacyTx{
+		Nonce:    uint64(args.Nonce),
+		To:       args.To,
+		Gas:      uint64(args.Gas),
+		GasPrice: args.GasPrice.ToInt(),
+		Value:    args.Value.ToInt(),
+		Data:     args.Data,
+	})
+	signed, err := types.SignTx(tx, types.LatestSignerForChainID(args.ChainID.ToInt()), c.key)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := signed.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return &signTxResult{Raw: hexutil.Bytes(raw)}, nil
+}
+
+func TestRemoteSigner(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := rpc.NewServer()
+	if err := server.RegisterName("account", &clefAccount{key: key}); err != nil {
+		t.Fatal(err)
+	}
+	// websocket clients hold their connection until closed
+	ws := httptest.NewServer(server.WebsocketHandler([]string{"*"}))
+	defer ws.Close()
+
+	ctx := context.Background()
+	s, err := NewRemoteSigner(ctx, "ws"+strings.TrimPrefix(ws.URL, "http"), "", crypto.PubkeyToAddress(key.PublicKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	to := common.HexToAddress("0x39AA39c021dfbaE8faC545936693aC917d5E7563")
+	tx := types.NewTransaction(7, to, big.NewInt(0), 700000, big.NewInt(1e9), []byte{0xf5, 0xe3, 0xc4, 0x62})
+	signed, err := s.SignTx(ctx, tx, big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signed.Nonce() != 7 || *signed.To() != to {
+		t.Errorf("signed transaction does not match: nonce %d to %s", signed.Nonce(), signed.To().Hex())
+	}
+
+	// reconnects create a new signer, the connection of the previous one is released
+	s.Close()
+	if _, err := s.SignTx(ctx, tx, big.NewInt(1)); err == nil {
+		t.Error("closed signer still connected")
+	}
+}
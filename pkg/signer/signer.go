@@ -0,0 +1,77 @@
+package signer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/config"
+)
+
+// Signer represents liquidator account transaction signer
+type Signer interface {
+	// Address returns signing account address
+	Address() common.Address
+	// SignTx returns tx signed for chainID
+	SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+	// Close releases the connection to a remote signer
+	Close()
+}
+
+// FromConfig creates signer from the configured key source
+func FromConfig(ctx context.Context, cfg config.Config) (Signer, error) {
+	account := cfg.Account()
+
+	switch {
+	case account.Keystore != "":
+		return NewKeystoreSigner(account.Keystore, account.PasswordFile)
+	case account.SignerURL != nil:
+		return NewRemoteSigner(ctx, account.SignerURL.String(), account.SignerMethod, account.Address)
+	case cfg.AccountKey() != nil:
+		return NewPrivateKeySigner(cfg.AccountKey()), nil
+	}
+
+	return nil, errors.New("no account key source configured")
+}
+
+// TransactOpts creates transact opts signing with s
+func TransactOpts(ctx context.Context, s Signer, chainID *big.Int) *bind.TransactOpts {
+	return &bind.TransactOpts{
+		From:    s.Address(),
+		Context: ctx,
+		Signer: func(address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			if address != s.Address() {
+				return nil, bind.ErrNotAuthorized
+			}
+			return s.SignTx(ctx, tx, chainID)
+		},
+	}
+}
+
+// NewPrivateKeySigner creates signer holding plaintext private key
+func NewPrivateKeySigner(key *ecdsa.PrivateKey) Signer {
+	return &privateKeySigner{
+		key:     key,
+		address: crypto.PubkeyToAddress(key.PublicKey),
+	}
+}
+
+type privateKeySigner struct {
+	key     *ecdsa.PrivateKey
+	address common.Address
+}
+
+func (s *privateKeySigner) Address() common.Address {
+	return s.address
+}
+
+func (s *privateKeySigner) Close() {}
+
+func (s *privateKeySigner) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return types.SignTx(tx, types.LatestSignerForChainID(chainID), s.key)
+}
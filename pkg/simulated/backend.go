@@ -0,0 +1,67 @@
+package simulated
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Backend is go-ethereum simulated backend running the compiled Compound contracts of contracts/,
+// with the node methods the bot needs on top
+type Backend struct {
+	*backends.SimulatedBackend
+
+	autoCommit bool
+}
+
+// newBackend creates new simulated backend funding alloc, Multicall3 is placed at its canonical address
+func newBackend(alloc core.GenesisAlloc, autoCommit bool) *Backend {
+	alloc[MulticallAddress] = core.GenesisAccount{Code: common.FromHex(Multicall3Runtime), Balance: new(big.Int)}
+
+	return &Backend{
+		SimulatedBackend: backends.NewSimulatedBackend(alloc, blockGasLimit),
+		autoCommit:       autoCommit,
+	}
+}
+
+// BlockNumber returns the head block number
+func (b *Backend) BlockNumber(ctx context.Context) (uint64, error) {
+	return b.Blockchain().CurrentBlock().Number.Uint64(), nil
+}
+
+// ChainID returns the simulated chain id
+func (b *Backend) ChainID(ctx context.Context) (*big.Int, error) {
+	return new(big.Int).Set(b.Blockchain().Config().ChainID), nil
+}
+
+// SuggestGasPrice returns the head base fee plus the suggested tip like eth_gasPrice of a node,
+// the simulated backend suggests the pending base fee calls at the head block fail with
+func (b *Backend) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	tip, err := b.SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, err
+	}
+	head := b.Blockchain().CurrentBlock()
+	if head.BaseFee == nil {
+		return tip, nil
+	}
+	return new(big.Int).Add(head.BaseFee, tip), nil
+}
+
+// SendTransaction sends tx to the simulated chain, the block is committed right away when the backend auto commits
+func (b *Backend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	if err := b.SimulatedBackend.SendTransaction(ctx, tx); err != nil {
+		return err
+	}
+	if b.autoCommit {
+		b.Commit()
+	}
+	return nil
+}
+
+// blockGasLimit is the simulated chain block gas limit
+const blockGasLimit = 30000000
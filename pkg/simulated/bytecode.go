@@ -0,0 +1,39 @@
+// Code generated by contracts/compile.js with solc 0.8.21+commit.d9974bed.Emscripten.clang. DO NOT EDIT.
+
+package simulated
+
+// FaucetTokenABI is the ABI of contracts/FaucetToken.sol FaucetToken
+const FaucetTokenABI = "[{\"inputs\":[{\"internalType\":\"string\",\"name\":\"name_\",\"type\":\"string\"},{\"internalType\":\"string\",\"name\":\"symbol_\",\"type\":\"string\"},{\"internalType\":\"uint8\",\"name\":\"decimals_\",\"type\":\"uint8\"}],\"stateMutability\":\"nonpayable\",\"type\":\"constructor\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"owner\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"spender\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"amount\",\"type\":\"uint256\"}],\"name\":\"Approval\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"from\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"amount\",\"type\":\"uint256\"}],\"name\":\"Transfer\",\"type\":\"event\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"owner\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"value\",\"type\":\"uint256\"}],\"name\":\"allocateTo\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"name\":\"allowance\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"spender\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"amount\",\"type\":\"uint256\"}],\"name\":\"approve\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"name\":\"balanceOf\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"decimals\",\"outputs\":[{\"internalType\":\"uint8\",\"name\":\"\",\"type\":\"uint8\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"name\",\"outputs\":[{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"symbol\",\"outputs\":[{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"totalSupply\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"dst\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"amount\",\"type\":\"uint256\"}],\"name\":\"transfer\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"src\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"dst\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"amount\",\"type\":\"uint256\"}],\"name\":\"transferFrom\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"}]"
+
+// FaucetTokenBin is the creation code of FaucetToken
+const FaucetTokenBin = "0x60806040523480156200001157600080fd5b5060405162000a1238038062000a12833981016040819052620000349162000134565b600062000042848262000248565b50600162000051838262000248565b506002805460ff191660ff9290921691909117905550620003149050565b634e487b7160e01b600052604160045260246000fd5b600082601f8301126200009757600080fd5b81516001600160401b0380821115620000b457620000b46200006f565b604051601f8301601f19908116603f01168101908282118183101715620000df57620000df6200006f565b81604052838152602092508683858801011115620000fc57600080fd5b600091505b8382101562000120578582018301518183018401529082019062000101565b600093810190920192909252949350505050565b6000806000606084860312156200014a57600080fd5b83516001600160401b03808211156200016257600080fd5b620001708783880162000085565b945060208601519150808211156200018757600080fd5b50620001968682870162000085565b925050604084015160ff81168114620001ae57600080fd5b809150509250925092565b600181811c90821680620001ce57607f821691505b602082108103620001ef57634e487b7160e01b600052602260045260246000fd5b50919050565b601f8211156200024357600081815260208120601f850160051c810160208610156200021e5750805b601f850160051c820191505b818110156200023f578281556001016200022a565b5050505b505050565b81516001600160401b038111156200026457620002646200006f565b6200027c81620002758454620001b9565b84620001f5565b602080601f831160018114620002b457600084156200029b5750858301515b600019600386901b1c1916600185901b1785556200023f565b600085815260208120601f198616915b82811015620002e557888601518255948401946001909101908401620002c4565b5085821015620003045787850151600019600388901b60f8161c191681555b5050505050600190811b01905550565b6106ee80620003246000396000f3fe608060405234801561001057600080fd5b506004361061009e5760003560e01c8063313ce56711610066578063313ce5671461012357806370a082311461014257806395d89b4114610162578063a9059cbb1461016a578063dd62ed3e1461017d57600080fd5b806306fdde03146100a357806308bca566146100c1578063095ea7b3146100d657806318160ddd146100f957806323b872dd14610110575b600080fd5b6100ab6101a8565b6040516100b8919061051d565b60405180910390f35b6100d46100cf366004610587565b610236565b005b6100e96100e4366004610587565b6102c0565b60405190151581526020016100b8565b61010260035481565b6040519081526020016100b8565b6100e961011e3660046105b1565b61032d565b6002546101309060ff1681565b60405160ff90911681526020016100b8565b6101026101503660046105ed565b60046020526000908152604090205481565b6100ab6103ed565b6100e9610178366004610587565b6103fa565b61010261018b36600461060f565b600560209081526000928352604080842090915290825290205481565b600080546101b590610642565b80601f01602080910402602001604051908101604052809291908181526020018280546101e190610642565b801561022e5780601f106102035761010080835404028352916020019161022e565b820191906000526020600020905b81548152906001019060200180831161021157829003601f168201915b505050505081565b6001600160a01b0382166000908152600460205260408120805483929061025e908490610692565b9250508190555080600360008282546102779190610692565b90915550506040518181526001600160a01b0383169030907fddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef9060200160405180910390a35050565b3360008181526005602090815260408083206001600160a01b038716808552925280832085905551919290917f8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b9259061031b9086815260200190565b60405180910390a35060015b92915050565b6001600160a01b03831660009081526005602090815260408083203384529091528120548281101561039f5760405162461bcd60e51b8152602060048201526016602482015275496e73756666696369656e7420616c6c6f77616e636560501b60448201526064015b60405180910390fd5b60001981146103d7576103b283826106a5565b6001600160a01b03861660009081526005602090815260408083203384529091529020555b6103e2858585610410565b506001949350505050565b600180546101b590610642565b6000610407338484610410565b50600192915050565b6001600160a01b03831660009081526004602052604090205481111561046f5760405162461bcd60e51b8152602060048201526014602482015273496e73756666696369656e742062616c616e636560601b6044820152606401610396565b6001600160a01b038316600090815260046020526040812080548392906104979084906106a5565b90915550506001600160a01b038216600090815260046020526040812080548392906104c4908490610692565b92505081905550816001600160a01b0316836001600160a01b03167fddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef8360405161051091815260200190565b60405180910390a3505050565b600060208083528351808285015260005b8181101561054a5785810183015185820160400152820161052e565b506000604082860101526040601f19601f8301168501019250505092915050565b80356001600160a01b038116811461058257600080fd5b919050565b6000806040838503121561059a57600080fd5b6105a38361056b565b946020939093013593505050565b6000806000606084860312156105c657600080fd5b6105cf8461056b565b92506105dd6020850161056b565b9150604084013590509250925092565b6000602082840312156105ff57600080fd5b6106088261056b565b9392505050565b6000806040838503121561062257600080fd5b61062b8361056b565b91506106396020840161056b565b90509250929050565b600181811c9082168061065657607f821691505b60208210810361067657634e487b7160e01b600052602260045260246000fd5b50919050565b634e487b7160e01b600052601160045260246000fd5b808201808211156103275761032761067c565b818103818111156103275761032761067c56fea2646970667358221220cb15835e9e10bfedfa83855232b2bb5506de32e704a2e6e0d662e3121b19930464736f6c63430008150033"
+
+// SimplePriceOracleABI is the ABI of contracts/SimplePriceOracle.sol SimplePriceOracle
+const SimplePriceOracleABI = "[{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"address\",\"name\":\"asset\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"previousPriceMantissa\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"requestedPriceMantissa\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"newPriceMantissa\",\"type\":\"uint256\"}],\"name\":\"PricePosted\",\"type\":\"event\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"asset\",\"type\":\"address\"}],\"name\":\"assetPrices\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"cToken\",\"type\":\"address\"}],\"name\":\"getUnderlyingPrice\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"isPriceOracle\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"pure\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"cToken\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"underlyingPriceMantissa\",\"type\":\"uint256\"}],\"name\":\"setUnderlyingPrice\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"}]"
+
+// SimplePriceOracleBin is the creation code of SimplePriceOracle
+const SimplePriceOracleBin = "0x608060405234801561001057600080fd5b506102e5806100206000396000f3fe608060405234801561001057600080fd5b506004361061004c5760003560e01c8063127ffda0146100515780635e9a523c1461006657806366331bba146100a2578063fc57d4df146100b1575b600080fd5b61006461005f366004610242565b6100c4565b005b61008f61007436600461026e565b6001600160a01b031660009081526020819052604090205490565b6040519081526020015b60405180910390f35b60405160018152602001610099565b61008f6100bf36600461026e565b6101a3565b6000826001600160a01b0316636f307dc36040518163ffffffff1660e01b8152600401602060405180830381865afa158015610104573d6000803e3d6000fd5b505050506040513d601f19601f820116820180604052508101906101289190610292565b6001600160a01b03811660008181526020818152604091829020548251938452908301528101849052606081018490529091507fdd71a1d19fcba687442a1d5c58578f1e409af71a79d10fd95a4d66efd8fa9ae79060800160405180910390a16001600160a01b031660009081526020819052604090205550565b6000806000836001600160a01b0316636f307dc36040518163ffffffff1660e01b8152600401602060405180830381865afa1580156101e6573d6000803e3d6000fd5b505050506040513d601f19601f8201168201806040525081019061020a9190610292565b6001600160a01b0316815260208101919091526040016000205492915050565b6001600160a01b038116811461023f57600080fd5b50565b6000806040838503121561025557600080fd5b82356102608161022a565b946020939093013593505050565b60006020828403121561028057600080fd5b813561028b8161022a565b9392505050565b6000602082840312156102a457600080fd5b815161028b8161022a56fea26469706673582212209d5f49533925d4edc0691ca60764e1262d371f1f302a1e3019426631136bc43964736f6c63430008150033"
+
+// FixedRateModelABI is the ABI of contracts/FixedRateModel.sol FixedRateModel
+const FixedRateModelABI = "[{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"borrowRate_\",\"type\":\"uint256\"}],\"stateMutability\":\"nonpayable\",\"type\":\"constructor\"},{\"inputs\":[],\"name\":\"admin\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"borrowRate\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"name\":\"getBorrowRate\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"cash\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"borrows\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"reserves\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"reserveFactorMantissa\",\"type\":\"uint256\"}],\"name\":\"getSupplyRate\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"isInterestRateModel\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"pure\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"borrowRate_\",\"type\":\"uint256\"}],\"name\":\"setBorrowRate\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"}]"
+
+// FixedRateModelBin is the creation code of FixedRateModel
+const FixedRateModelBin = "0x608060405234801561001057600080fd5b5060405161038038038061038083398101604081905261002f91610049565b600080546001600160a01b03191633179055600155610062565b60006020828403121561005b57600080fd5b5051919050565b61030f806100716000396000f3fe608060405234801561001057600080fd5b50600436106100625760003560e01c806315f24053146100675780632191f92a14610092578063b8168816146100a1578063c914b437146100b4578063dd3eaf04146100bd578063f851a440146100d2575b600080fd5b61007f6100753660046101e7565b6001549392505050565b6040519081526020015b60405180910390f35b60405160018152602001610089565b61007f6100af366004610213565b6100fd565b61007f60015481565b6100d06100cb366004610245565b610197565b005b6000546100e5906001600160a01b031681565b6040516001600160a01b039091168152602001610089565b6000808361010b8688610274565b610115919061028d565b90508060000361012957600091505061018f565b6000670de0b6b3a764000061013e858261028d565b60015461014b91906102a0565b61015591906102b7565b9050670de0b6b3a7640000818361016c89846102a0565b61017691906102b7565b61018091906102a0565b61018a91906102b7565b925050505b949350505050565b6000546001600160a01b031633146101e25760405162461bcd60e51b815260206004820152600a60248201526937b7363c9030b236b4b760b11b604482015260640160405180910390fd5b600155565b6000806000606084860312156101fc57600080fd5b505081359360208301359350604090920135919050565b6000806000806080858703121561022957600080fd5b5050823594602084013594506040840135936060013592509050565b60006020828403121561025757600080fd5b5035919050565b634e487b7160e01b600052601160045260246000fd5b808201808211156102875761028761025e565b92915050565b818103818111156102875761028761025e565b80820281158282048414176102875761028761025e565b6000826102d457634e487b7160e01b600052601260045260246000fd5b50049056fea26469706673582212202f60f65d422cfa2cba618ffe397b301f6f0f297857f2a2d802c434a3cb8622eb64736f6c63430008150033"
+
+// ComptrollerABI is the ABI of contracts/Comptroller.sol Comptroller
+const ComptrollerABI = "[{\"inputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"constructor\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"error\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"info\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"detail\",\"type\":\"uint256\"}],\"name\":\"Failure\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"address\",\"name\":\"cToken\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"address\",\"name\":\"account\",\"type\":\"address\"}],\"name\":\"MarketEntered\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"address\",\"name\":\"cToken\",\"type\":\"address\"}],\"name\":\"MarketListed\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"oldCloseFactorMantissa\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"newCloseFactorMantissa\",\"type\":\"uint256\"}],\"name\":\"NewCloseFactor\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"address\",\"name\":\"cToken\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"oldCollateralFactorMantissa\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"newCollateralFactorMantissa\",\"type\":\"uint256\"}],\"name\":\"NewCollateralFactor\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"oldLiquidationIncentiveMantissa\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"newLiquidationIncentiveMantissa\",\"type\":\"uint256\"}],\"name\":\"NewLiquidationIncentive\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"address\",\"name\":\"oldPriceOracle\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"address\",\"name\":\"newPriceOracle\",\"type\":\"address\"}],\"name\":\"NewPriceOracle\",\"type\":\"event\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"newCloseFactorMantissa\",\"type\":\"uint256\"}],\"name\":\"_setCloseFactor\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"cToken\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"newCollateralFactorMantissa\",\"type\":\"uint256\"}],\"name\":\"_setCollateralFactor\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"newLiquidationIncentiveMantissa\",\"type\":\"uint256\"}],\"name\":\"_setLiquidationIncentive\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"contract PriceOracle\",\"name\":\"newOracle\",\"type\":\"address\"}],\"name\":\"_setPriceOracle\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"cToken\",\"type\":\"address\"}],\"name\":\"_supportMarket\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"name\":\"accountAssets\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"admin\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"name\":\"allMarkets\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"cToken\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"borrower\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"borrowAmount\",\"type\":\"uint256\"}],\"name\":\"borrowAllowed\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"account\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"cToken\",\"type\":\"address\"}],\"name\":\"checkMembership\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"closeFactorMantissa\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address[]\",\"name\":\"cTokens\",\"type\":\"address[]\"}],\"name\":\"enterMarkets\",\"outputs\":[{\"internalType\":\"uint256[]\",\"name\":\"\",\"type\":\"uint256[]\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"account\",\"type\":\"address\"}],\"name\":\"getAccountLiquidity\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"getAllMarkets\",\"outputs\":[{\"internalType\":\"address[]\",\"name\":\"\",\"type\":\"address[]\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"account\",\"type\":\"address\"}],\"name\":\"getAssetsIn\",\"outputs\":[{\"internalType\":\"address[]\",\"name\":\"\",\"type\":\"address[]\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"account\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"cTokenModify\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"redeemTokens\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"borrowAmount\",\"type\":\"uint256\"}],\"name\":\"getHypotheticalAccountLiquidity\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"isComptroller\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"pure\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"cTokenBorrowed\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"cTokenCollateral\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"borrower\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"repayAmount\",\"type\":\"uint256\"}],\"name\":\"liquidateBorrowAllowed\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"cTokenBorrowed\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"cTokenCollateral\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"actualRepayAmount\",\"type\":\"uint256\"}],\"name\":\"liquidateCalculateSeizeTokens\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"liquidationIncentiveMantissa\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"name\":\"markets\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"isListed\",\"type\":\"bool\"},{\"internalType\":\"uint256\",\"name\":\"collateralFactorMantissa\",\"type\":\"uint256\"},{\"internalType\":\"bool\",\"name\":\"isComped\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"cToken\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"name\":\"mintAllowed\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"oracle\",\"outputs\":[{\"internalType\":\"contract PriceOracle\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"cToken\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"name\":\"repayBorrowAllowed\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"cTokenCollateral\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"cTokenBorrowed\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"name\":\"seizeAllowed\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"}]"
+
+// ComptrollerBin is the creation code of Comptroller
+const ComptrollerBin = "0x608060405234801561001057600080fd5b50600080546001600160a01b03191633179055611cdc806100326000396000f3fe608060405234801561001057600080fd5b50600436106101725760003560e01c80638e8f294b116100de578063c488847b11610097578063dce1544911610071578063dce15449146103d4578063e4028eee146103e7578063e8755446146103fa578063f851a4401461040357600080fd5b8063c488847b14610386578063d02f7351146103ae578063da3d454c146103c157600080fd5b80638e8f294b14610297578063929fe9a1146102ea578063a76b3fda1461032b578063abfceffc1461033e578063b0772d0b1461035e578063c29982381461036657600080fd5b80634fd42e17116101305780634fd42e171461020d57806352d84d1e1461022057806355ee1fe11461024b5780635ec88c791461025e5780635fc7e71e146102715780637dc0d1d01461028457600080fd5b80627e3dd21461017757806324008a621461018f578063317b0b77146101b05780634ada90af146101c35780634e79238f146101cc5780634ef4c3e1146101fa575b600080fd5b60015b60405190151581526020015b60405180910390f35b6101a261019d366004611827565b610416565b604051908152602001610186565b6101a26101be366004611878565b61044a565b6101a260035481565b6101df6101da366004611891565b6104e4565b60408051938452602084019290925290820152606001610186565b6101a26102083660046118d7565b610524565b6101a261021b366004611878565b61055b565b61023361022e366004611878565b6105c2565b6040516001600160a01b039091168152602001610186565b6101a2610259366004611918565b6105ec565b6101df61026c366004611918565b610674565b6101a261027f366004611935565b6106af565b600154610233906001600160a01b031681565b6102cb6102a5366004611918565b60056020526000908152604090208054600182015460039092015460ff91821692911683565b6040805193151584526020840192909252151590820152606001610186565b61017a6102f8366004611999565b6001600160a01b038082166000908152600560209081526040808320938616835260029093019052205460ff1692915050565b6101a2610339366004611918565b610810565b61035161034c366004611918565b6109b3565b60405161018691906119d2565b610351610a29565b610379610374366004611a35565b610a8b565b6040516101869190611afa565b6103996103943660046118d7565b610b4b565b60408051928352602083019190915201610186565b6101a26103bc366004611935565b610d3e565b6101a26103cf3660046118d7565b610e79565b6102336103e2366004611b32565b611039565b6101a26103f5366004611b32565b611071565b6101a260025481565b600054610233906001600160a01b031681565b6001600160a01b03841660009081526005602052604081205460ff1661043e57506009610442565b5060005b949350505050565b600080546001600160a01b0316331461046f57610469600160046111d1565b92915050565b66b1a2bc2ec500008211158061048c5750670c7d713b49da000082115b1561049c576104696005806111d1565b60025460408051918252602082018490527f3b9670cf975d26958e754b57098eaa2ac914d8d2a31b83257997b9f346110fd9910160405180910390a160028290556000610469565b6000806000806000806104f98a8a8a8a61124a565b92509250925082601181111561051157610511611b5e565b95509093509150505b9450945094915050565b6001600160a01b03831660009081526005602052604081205460ff1661054e5760095b9050610554565b60005b90505b9392505050565b600080546001600160a01b0316331461057a576104696001600b6111d1565b60035460408051918252602082018490527faeba5a6c40a8ac138134bff1aaa65debf25971188a58804bad717f82f0ec1316910160405180910390a160038290556000610469565b600681815481106105d257600080fd5b6000918252602090912001546001600160a01b0316905081565b600080546001600160a01b0316331461060b57610469600160106111d1565b600154604080516001600160a01b03928316815291841660208301527fd52b2b9b7e9ee655fcb95d2e5b9e0c9f69e7ef2b8e9d2d0ea78402d576d22e22910160405180910390a1600180546001600160a01b0319166001600160a01b0384161790556000610469565b60008060008060008061068b87600080600061124a565b9250925092508260118111156106a3576106a3611b5e565b97919650945092505050565b6001600160a01b03851660009081526005602052604081205460ff1615806106f057506001600160a01b03851660009081526005602052604090205460ff16155b156106ff5760095b9050610807565b60008061071085600080600061124a565b9193509091506000905082601181111561072c5761072c611b5e565b1461074c5781601181111561074357610743611b5e565b92505050610807565b8060000361075b576003610743565b6040516395dd919360e01b81526001600160a01b038681166004830152600091908a16906395dd919390602401602060405180830381865afa1580156107a5573d6000803e3d6000fd5b505050506040513d601f19601f820116820180604052508101906107c99190611b74565b905060006107e760405180602001604052806002548152508361157c565b9050808611156107fe576011945050505050610807565b60009450505050505b95945050505050565b600080546001600160a01b0316331461082f57610469600160126111d1565b6001600160a01b03821660009081526005602052604090205460ff161561085c57610469600a60116111d1565b816001600160a01b031663fe9c44ae6040518163ffffffff1660e01b8152600401602060405180830381865afa15801561089a573d6000803e3d6000fd5b505050506040513d601f19601f820116820180604052508101906108be9190611b8d565b6108fe5760405162461bcd60e51b815260206004820152600c60248201526b3737ba10309031aa37b5b2b760a11b60448201526064015b60405180910390fd5b6001600160a01b03821660008181526005602090815260408083208054600160ff19918216811783556003830180549092169091558082018590556006805491820181559094527ff652222313e28459528d920b65115c16c04f3efc82aaedc97be59f3f377c0d3f90930180546001600160a01b031916851790555192835290917fcf583bb0c569eb967f806b11601c4cb93c10310485c67add5f8362c2f212321f910160405180910390a160009392505050565b6001600160a01b038116600090815260046020908152604091829020805483518184028101840190945280845260609392830182828015610a1d57602002820191906000526020600020905b81546001600160a01b031681526001909101906020018083116109ff575b50505050509050919050565b60606006805480602002602001604051908101604052809291908181526020018280548015610a8157602002820191906000526020600020905b81546001600160a01b03168152600190910190602001808311610a63575b5050505050905090565b60606000825167ffffffffffffffff811115610aa957610aa9611a1f565b604051908082528060200260200182016040528015610ad2578160200160208202803683370190505b50905060005b8351811015610b4457610b04848281518110610af657610af6611baf565b602002602001015133611590565b6011811115610b1557610b15611b5e565b828281518110610b2757610b27611baf565b602090810291909101015280610b3c81611bdb565b915050610ad8565b5092915050565b60015460405163fc57d4df60e01b81526001600160a01b038581166004830152600092839283929091169063fc57d4df90602401602060405180830381865afa158015610b9c573d6000803e3d6000fd5b505050506040513d601f19601f82011682018060405250810190610bc09190611b74565b60015460405163fc57d4df60e01b81526001600160a01b0388811660048301529293506000929091169063fc57d4df90602401602060405180830381865afa158015610c10573d6000803e3d6000fd5b505050506040513d601f19601f82011682018060405250810190610c349190611b74565b9050811580610c41575080155b15610c5557600d6000935093505050610d36565b6000866001600160a01b031663182df0f56040518163ffffffff1660e01b8152600401602060405180830381865afa158015610c95573d6000803e3d6000fd5b505050506040513d601f19601f82011682018060405250810190610cb99190611b74565b90506000610ce56040518060200160405280600354815250604051806020016040528087815250611680565b90506000610d0f604051806020016040528086815250604051806020016040528086815250611680565b90506000610d1d83836116ca565b90506000610d2b828b61157c565b975097505050505050505b935093915050565b6001600160a01b03851660009081526005602052604081205460ff161580610d7f57506001600160a01b03851660009081526005602052604090205460ff16155b15610d8b5760096106f8565b846001600160a01b0316635fe3b5676040518163ffffffff1660e01b8152600401602060405180830381865afa158015610dc9573d6000803e3d6000fd5b505050506040513d601f19601f82011682018060405250810190610ded9190611bf4565b6001600160a01b0316866001600160a01b0316635fe3b5676040518163ffffffff1660e01b8152600401602060405180830381865afa158015610e34573d6000803e3d6000fd5b505050506040513d601f19601f82011682018060405250810190610e589190611bf4565b6001600160a01b031614610e6d5760026106f8565b60009695505050505050565b6001600160a01b03831660009081526005602052604081205460ff16610ea0576009610547565b6001600160a01b038085166000908152600560209081526040808320938716835260029093019052205460ff16610f6457336001600160a01b03851614610f215760405162461bcd60e51b815260206004820152601560248201527439b2b73232b91036bab9ba1031329031aa37b5b2b760591b60448201526064016108f5565b6000610f2d3385611590565b90506000816011811115610f4357610f43611b5e565b14610f6257806011811115610f5a57610f5a611b5e565b915050610554565b505b60015460405163fc57d4df60e01b81526001600160a01b0386811660048301529091169063fc57d4df90602401602060405180830381865afa158015610fae573d6000803e3d6000fd5b505050506040513d601f19601f82011682018060405250810190610fd29190611b74565b600003610fe057600d610547565b600080610ff0858760008761124a565b9193509091506000905082601181111561100c5761100c611b5e565b1461102c5781601181111561102357611023611b5e565b92505050610554565b8015610e6d576004611023565b6004602052816000526040600020818154811061105557600080fd5b6000918252602090912001546001600160a01b03169150829050565b600080546001600160a01b0316331461109757611090600160066111d1565b9050610469565b6001600160a01b0383166000908152600560205260409020805460ff166110cc576110c4600960076111d1565b915050610469565b670c7d713b49da00008311156110e8576110c4600660086111d1565b8215801590611162575060015460405163fc57d4df60e01b81526001600160a01b0386811660048301529091169063fc57d4df90602401602060405180830381865afa15801561113c573d6000803e3d6000fd5b505050506040513d601f19601f820116820180604052508101906111609190611b74565b155b15611173576110c4600d60096111d1565b6001810154604080516001600160a01b0387168152602081019290925281018490527f70483e6592cd5182d45ac970e05bc62cdcc90e9d8ef2c2dbe686cf383bcd7fc59060600160405180910390a160010191909155506000919050565b60007f45b96fe442630264581b197e84bbada861235052c5a1aadfff9ea4e40a969aa083601181111561120657611206611b5e565b83601281111561121857611218611b5e565b60408051928352602083019190915260009082015260600160405180910390a182601181111561055457610554611b5e565b600080600061125761175d565b6001600160a01b0388166000908152600460209081526040808320805482518185028101850190935280835284938301828280156112be57602002820191906000526020600020905b81546001600160a01b031681526001909101906020018083116112a0575b5050505050905060005b81518110156115265760008282815181106112e5576112e5611baf565b60209081029190910101516040516361bfb47160e11b81526001600160a01b038e811660048301529192509082169063c37f68e290602401608060405180830381865afa15801561133a573d6000803e3d6000fd5b505050506040513d601f19601f8201168201806040525081019061135e9190611c11565b6080890152606088015260408701529350831561138a57600f600080975097509750505050505061051a565b60408051602080820183526001600160a01b03848116600081815260058452859020600190810154855260c08b01949094528451928301855260808a0151835260e08a01929092529154925163fc57d4df60e01b8152600481019190915291169063fc57d4df90602401602060405180830381865afa158015611411573d6000803e3d6000fd5b505050506040513d601f19601f820116820180604052508101906114359190611b74565b60a0860181905260000361145857600d600080975097509750505050505061051a565b604080516020810190915260a0860151815261010086015260c085015160e08601516114929161148791611680565b866101000151611680565b6101208601819052604086015186516114ac929190611701565b8552610100850151606086015160208701516114c9929190611701565b60208601526001600160a01b03808c1690821603611513576114f58561012001518b8760200151611701565b6020860181905261010086015161150d918b90611701565b60208601525b508061151e81611bdb565b9150506112c8565b50602083015183511115611557576020830151835160009161154791611c47565b600095509550955050505061051a565b6000808460000151856020015161156e9190611c47565b95509550955050505061051a565b600061055461158b848461171b565b611745565b6001600160a01b0382166000908152600560205260408120805460ff166115bb576009915050610469565b6001600160a01b038316600090815260028201602052604090205460ff16156115e8576000915050610469565b6001600160a01b03838116600081815260028401602090815260408083208054600160ff199091168117909155600483528184208054918201815584529282902090920180546001600160a01b031916948916948517905581519384528301919091527f3ab23ab0d51cccc0c3085aec51f99228625aa1a922b3a8ca89a26b0f2027a1a5910160405180910390a15060009392505050565b6040805160208101909152600081526040518060200160405280670de0b6b3a7640000846000015186600001516116b79190611c5a565b6116c19190611c71565b90529392505050565b60408051602081019091526000815260405180602001604052808360000151670de0b6b3a764000086600001516116b79190611c5a565b60008161171161158b868661171b565b6105519190611c93565b60408051602081019091526000815260405180602001604052808385600001516116c19190611c5a565b805160009061046990670de0b6b3a764000090611c71565b6040518061014001604052806000815260200160008152602001600081526020016000815260200160008152602001600081526020016117a96040518060200160405280600081525090565b81526020016117c46040518060200160405280600081525090565b81526020016117df6040518060200160405280600081525090565b81526020016117fa6040518060200160405280600081525090565b905290565b6001600160a01b038116811461181457600080fd5b50565b8035611822816117ff565b919050565b6000806000806080858703121561183d57600080fd5b8435611848816117ff565b93506020850135611858816117ff565b92506040850135611868816117ff565b9396929550929360600135925050565b60006020828403121561188a57600080fd5b5035919050565b600080600080608085870312156118a757600080fd5b84356118b2816117ff565b935060208501356118c2816117ff565b93969395505050506040820135916060013590565b6000806000606084860312156118ec57600080fd5b83356118f7816117ff565b92506020840135611907816117ff565b929592945050506040919091013590565b60006020828403121561192a57600080fd5b8135610554816117ff565b600080600080600060a0868803121561194d57600080fd5b8535611958816117ff565b94506020860135611968816117ff565b93506040860135611978816117ff565b92506060860135611988816117ff565b949793965091946080013592915050565b600080604083850312156119ac57600080fd5b82356119b7816117ff565b915060208301356119c7816117ff565b809150509250929050565b6020808252825182820181905260009190848201906040850190845b81811015611a135783516001600160a01b0316835292840192918401916001016119ee565b50909695505050505050565b634e487b7160e01b600052604160045260246000fd5b60006020808385031215611a4857600080fd5b823567ffffffffffffffff80821115611a6057600080fd5b818501915085601f830112611a7457600080fd5b813581811115611a8657611a86611a1f565b8060051b604051601f19603f83011681018181108582111715611aab57611aab611a1f565b604052918252848201925083810185019188831115611ac957600080fd5b938501935b82851015611aee57611adf85611817565b84529385019392850192611ace565b98975050505050505050565b6020808252825182820181905260009190848201906040850190845b81811015611a1357835183529284019291840191600101611b16565b60008060408385031215611b4557600080fd5b8235611b50816117ff565b946020939093013593505050565b634e487b7160e01b600052602160045260246000fd5b600060208284031215611b8657600080fd5b5051919050565b600060208284031215611b9f57600080fd5b8151801515811461055457600080fd5b634e487b7160e01b600052603260045260246000fd5b634e487b7160e01b600052601160045260246000fd5b600060018201611bed57611bed611bc5565b5060010190565b600060208284031215611c0657600080fd5b8151610554816117ff565b60008060008060808587031215611c2757600080fd5b505082516020840151604085015160609095015191969095509092509050565b8181038181111561046957610469611bc5565b808202811582820484141761046957610469611bc5565b600082611c8e57634e487b7160e01b600052601260045260246000fd5b500490565b8082018082111561046957610469611bc556fea2646970667358221220b46ba2b9bd240e2e23821d37115baa87c167ebe033a92d85080b59981538283064736f6c63430008150033"
+
+// CErc20ABI is the ABI of contracts/CErc20.sol CErc20
+const CErc20ABI = "[{\"inputs\":[{\"internalType\":\"address\",\"name\":\"underlying_\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"comptroller_\",\"type\":\"address\"},{\"internalType\":\"contract InterestRateModel\",\"name\":\"interestRateModel_\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"initialExchangeRateMantissa_\",\"type\":\"uint256\"},{\"internalType\":\"string\",\"name\":\"name_\",\"type\":\"string\"},{\"internalType\":\"string\",\"name\":\"symbol_\",\"type\":\"string\"},{\"internalType\":\"uint8\",\"name\":\"decimals_\",\"type\":\"uint8\"}],\"stateMutability\":\"nonpayable\",\"type\":\"constructor\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"interestAccumulated\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"borrowIndex\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"totalBorrows\",\"type\":\"uint256\"}],\"name\":\"AccrueInterest\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"address\",\"name\":\"borrower\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"borrowAmount\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"accountBorrows\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"totalBorrows\",\"type\":\"uint256\"}],\"name\":\"Borrow\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"error\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"info\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"detail\",\"type\":\"uint256\"}],\"name\":\"Failure\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"address\",\"name\":\"liquidator\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"address\",\"name\":\"borrower\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"repayAmount\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"address\",\"name\":\"cTokenCollateral\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"seizeTokens\",\"type\":\"uint256\"}],\"name\":\"LiquidateBorrow\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"address\",\"name\":\"minter\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"mintAmount\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"mintTokens\",\"type\":\"uint256\"}],\"name\":\"Mint\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"address\",\"name\":\"payer\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"address\",\"name\":\"borrower\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"repayAmount\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"accountBorrows\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"totalBorrows\",\"type\":\"uint256\"}],\"name\":\"RepayBorrow\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"from\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"amount\",\"type\":\"uint256\"}],\"name\":\"Transfer\",\"type\":\"event\"},{\"inputs\":[],\"name\":\"accrualBlockNumber\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"accrueInterest\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"admin\",\"outputs\":[{\"internalType\":\"address payable\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"owner\",\"type\":\"address\"}],\"name\":\"balanceOf\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"owner\",\"type\":\"address\"}],\"name\":\"balanceOfUnderlying\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"borrowAmount\",\"type\":\"uint256\"}],\"name\":\"borrow\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"account\",\"type\":\"address\"}],\"name\":\"borrowBalanceCurrent\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"account\",\"type\":\"address\"}],\"name\":\"borrowBalanceStored\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"borrowIndex\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"borrowRatePerBlock\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"comptroller\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"decimals\",\"outputs\":[{\"internalType\":\"uint8\",\"name\":\"\",\"type\":\"uint8\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"exchangeRateCurrent\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"exchangeRateStored\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"account\",\"type\":\"address\"}],\"name\":\"getAccountSnapshot\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"getCash\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"interestRateModel\",\"outputs\":[{\"internalType\":\"contract InterestRateModel\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"isCToken\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"pure\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"borrower\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"repayAmount\",\"type\":\"uint256\"},{\"internalType\":\"contract CTokenInterface\",\"name\":\"cTokenCollateral\",\"type\":\"address\"}],\"name\":\"liquidateBorrow\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"mintAmount\",\"type\":\"uint256\"}],\"name\":\"mint\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"name\",\"outputs\":[{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"repayAmount\",\"type\":\"uint256\"}],\"name\":\"repayBorrow\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"borrower\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"repayAmount\",\"type\":\"uint256\"}],\"name\":\"repayBorrowBehalf\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"reserveFactorMantissa\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"liquidator\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"borrower\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"seizeTokens\",\"type\":\"uint256\"}],\"name\":\"seize\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"supplyRatePerBlock\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"symbol\",\"outputs\":[{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"totalBorrows\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"totalBorrowsCurrent\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"totalReserves\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"totalSupply\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"underlying\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"}]"
+
+// CErc20Bin is the creation code of CErc20
+const CErc20Bin = "0x60806040523480156200001157600080fd5b506040516200268238038062002682833981016040819052620000349162000307565b85858585858560008411620000a95760405162461bcd60e51b815260206004820152603060248201527f696e697469616c2065786368616e67652072617465206d75737420626520677260448201526f32b0ba32b9103a3430b7103d32b9379760811b60648201526084015b60405180910390fd5b856001600160a01b0316627e3dd26040518163ffffffff1660e01b8152600401602060405180830381865afa158015620000e7573d6000803e3d6000fd5b505050506040513d601f19601f820116820180604052508101906200010d9190620003d7565b6200015b5760405162461bcd60e51b815260206004820152601c60248201527f6d61726b6572206d6574686f642072657475726e65642066616c7365000000006044820152606401620000a0565b600380546101003302610100600160a81b0319909116179055600480546001600160a01b038881166001600160a01b0319928316179092556005805492881692909116919091179055600684905543600855670de0b6b3a76400006009556001620001c7848262000491565b506002620001d6838262000491565b506003805460ff90921660ff199283161790556000805490911660011790555050600f80546001600160a01b03909b166001600160a01b0319909b169a909a17909955506200055d975050505050505050565b6001600160a01b03811681146200023f57600080fd5b50565b634e487b7160e01b600052604160045260246000fd5b600082601f8301126200026a57600080fd5b81516001600160401b038082111562000287576200028762000242565b604051601f8301601f19908116603f01168101908282118183101715620002b257620002b262000242565b81604052838152602092508683858801011115620002cf57600080fd5b600091505b83821015620002f35785820183015181830184015290820190620002d4565b600093810190920192909252949350505050565b600080600080600080600060e0888a0312156200032357600080fd5b8751620003308162000229565b6020890151909750620003438162000229565b6040890151909650620003568162000229565b606089015160808a015191965094506001600160401b03808211156200037b57600080fd5b620003898b838c0162000258565b945060a08a0151915080821115620003a057600080fd5b50620003af8a828b0162000258565b92505060c088015160ff81168114620003c757600080fd5b8091505092959891949750929550565b600060208284031215620003ea57600080fd5b81518015158114620003fb57600080fd5b9392505050565b600181811c908216806200041757607f821691505b6020821081036200043857634e487b7160e01b600052602260045260246000fd5b50919050565b601f8211156200048c57600081815260208120601f850160051c81016020861015620004675750805b601f850160051c820191505b81811015620004885782815560010162000473565b5050505b505050565b81516001600160401b03811115620004ad57620004ad62000242565b620004c581620004be845462000402565b846200043e565b602080601f831160018114620004fd5760008415620004e45750858301515b600019600386901b1c1916600185901b17855562000488565b600085815260208120601f198616915b828110156200052e578886015182559484019460019091019084016200050d565b50858210156200054d5787850151600019600388901b60f8161c191681555b5050505050600190811b01905550565b612115806200056d6000396000f3fe608060405234801561001057600080fd5b50600436106101f05760003560e01c80638f840ddd1161010f578063bd6d894d116100a2578063f5e3c46211610071578063f5e3c462146103f3578063f851a44014610406578063f8f9da281461041e578063fe9c44ae1461042657600080fd5b8063bd6d894d14610392578063c37f68e21461039a578063c5ebeaec146103cd578063f3fdb15a146103e057600080fd5b8063a6afed95116100de578063a6afed9514610366578063aa5af0fd1461036e578063ae9d70b014610377578063b2a02ff11461037f57600080fd5b80638f840ddd1461032f57806395d89b411461033857806395dd919314610340578063a0712d681461035357600080fd5b80633af9e669116101875780636c540baf116101565780636c540baf146102e25780636f307dc3146102eb57806370a08231146102fe57806373acee981461032757600080fd5b80633af9e669146102935780633b1d21a2146102a657806347bd3718146102ae5780635fe3b567146102b757600080fd5b806318160ddd116101c357806318160ddd14610250578063182df0f5146102595780632608f81814610261578063313ce5671461027457600080fd5b806306fdde03146101f55780630e75270214610213578063173b99041461023457806317bfdfbc1461023d575b600080fd5b6101fd610435565b60405161020a9190611df7565b60405180910390f35b610226610221366004611e2a565b6104c3565b60405190815260200161020a565b61022660075481565b61022661024b366004611e5b565b6104d4565b610226600c5481565b61022661054d565b61022661026f366004611e78565b61055c565b6003546102819060ff1681565b60405160ff909116815260200161020a565b6102266102a1366004611e5b565b61056f565b6102266105ae565b610226600a5481565b6004546102ca906001600160a01b031681565b6040516001600160a01b03909116815260200161020a565b61022660085481565b600f546102ca906001600160a01b031681565b61022661030c366004611e5b565b6001600160a01b03166000908152600d602052604090205490565b6102266105b8565b610226600b5481565b6101fd61061e565b61022661034e366004611e5b565b61062b565b610226610361366004611e2a565b610636565b610226610641565b61022660095481565b610226610828565b61022661038d366004611ea4565b6108c0565b610226610910565b6103ad6103a8366004611e5b565b61097c565b60408051948552602085019390935291830152606082015260800161020a565b6102266103db366004611e2a565b6109bb565b6005546102ca906001600160a01b031681565b610226610401366004611ee5565b6109c6565b6003546102ca9061010090046001600160a01b031681565b6102266109db565b6040516001815260200161020a565b6001805461044290611f27565b80601f016020809104026020016040519081016040528092919081815260200182805461046e90611f27565b80156104bb5780601f10610490576101008083540402835291602001916104bb565b820191906000526020600020905b81548152906001019060200180831161049e57829003601f168201915b505050505081565b60006104ce82610a2e565b92915050565b6000805460ff166105005760405162461bcd60e51b81526004016104f790611f61565b60405180910390fd5b6000805460ff19168155610512610641565b1461052f5760405162461bcd60e51b81526004016104f790611f9b565b6105388261062b565b90505b6000805460ff19166001179055919050565b6000610557610ab3565b905090565b60006105688383610b0b565b9392505050565b6000806040518060200160405280610585610910565b90526001600160a01b0384166000908152600d6020526040902054909150610568908290610b8b565b6000610557610b9f565b6000805460ff166105db5760405162461bcd60e51b81526004016104f790611f61565b6000805460ff191681556105ed610641565b1461060a5760405162461bcd60e51b81526004016104f790611f9b565b50600a546000805460ff1916600117905590565b6002805461044290611f27565b60006104ce82610bd0565b60006104ce82610c17565b600854600090439081810361065a5760005b9250505090565b6000610664610b9f565b600a54600b546009546005546040516315f2405360e01b81526004810186905260248101859052604481018490529495509293919290916000916001600160a01b0316906315f2405390606401602060405180830381865afa1580156106ce573d6000803e3d6000fd5b505050506040513d601f19601f820116820180604052508101906106f29190611fcb565b905065048c2739500081111561074a5760405162461bcd60e51b815260206004820152601c60248201527f626f72726f772072617465206973206162737572646c7920686967680000000060448201526064016104f7565b60006107568789611ffa565b9050600061077260405180602001604052808581525083610c8f565b905060006107808288610b8b565b9050600061078e888361200d565b905060006107ad6040518060200160405280600754815250848a610cc2565b905060006107bc85898a610cc2565b60088e90556009819055600a849055600b83905560408051868152602081018390529081018590529091507f875352fb3fadeb8c0be7cbbe8ff761b308fa7033470cd0287f02f3436fd76cb99060600160405180910390a160009d505050505050505050505050505090565b6005546000906001600160a01b031663b8168816610844610b9f565b600a54600b546007546040516001600160e01b031960e087901b16815260048101949094526024840192909252604483015260648201526084015b602060405180830381865afa15801561089c573d6000803e3d6000fd5b505050506040513d601f19601f820116820180604052508101906105579190611fcb565b6000805460ff166108e35760405162461bcd60e51b81526004016104f790611f61565b6000805460ff191690556108f933858585610cdc565b90505b6000805460ff191660011790559392505050565b6000805460ff166109335760405162461bcd60e51b81526004016104f790611f61565b6000805460ff19168155610945610641565b146109625760405162461bcd60e51b81526004016104f790611f9b565b61096a61054d565b90506000805460ff1916600117905590565b6000808080806001600160a01b0386166000908152600d60205260409020546109a487610bd0565b6109ac610ab3565b93509350935093509193509193565b60006104ce82610e70565b60006109d3848484610ed3565b949350505050565b6005546000906001600160a01b03166315f240536109f7610b9f565b600a54600b546040516001600160e01b031960e086901b16815260048101939093526024830191909152604482015260640161087f565b6000805460ff16610a515760405162461bcd60e51b81526004016104f790611f61565b6000805460ff19168155610a63610641565b90508015610a8f57610a87816010811115610a8057610a80611f85565b6017610fdf565b91505061053b565b6000610a9c333386611058565b50925050506000805460ff19166001179055919050565b600c54600090808203610ac857505060065490565b6000600b54600a54610ad8610b9f565b610ae2919061200d565b610aec9190611ffa565b905081610b01670de0b6b3a764000083612020565b6106539190612037565b6000805460ff16610b2e5760405162461bcd60e51b81526004016104f790611f61565b6000805460ff19168155610b40610641565b90508015610b6557610b5d816010811115610a8057610a80611f85565b915050610b78565b6000610b72338686611058565b50925050505b6000805460ff1916600117905592915050565b6000610568610b9a8484610c8f565b611216565b600f546040516370a0823160e01b81523060048201526000916001600160a01b0316906370a082319060240161087f565b6001600160a01b0381166000908152600e6020526040812080548203610bf95750600092915050565b60018101546009548254610c0d9190612020565b6105689190612037565b6000805460ff16610c3a5760405162461bcd60e51b81526004016104f790611f61565b6000805460ff19168155610c4c610641565b90508015610c7057610a87816010811115610c6957610c69611f85565b6010610fdf565b610c7a338461122e565b9150506000805460ff19166001179055919050565b6040805160208101909152600081526040518060200160405280838560000151610cb99190612020565b90529392505050565b600081610cd2610b9a8686610c8f565b6109d3919061200d565b6004805460405163d02f735160e01b815230928101929092526001600160a01b03868116602484015285811660448401528481166064840152608483018490526000928392919091169063d02f73519060a4016020604051808303816000875af1158015610d4e573d6000803e3d6000fd5b505050506040513d601f19601f82011682018060405250810190610d729190611fcb565b90508015610d8f57610d876003600e836113ef565b9150506109d3565b846001600160a01b0316846001600160a01b031603610db457610d876006600f610fdf565b6001600160a01b0384166000908152600d6020526040902054610dd8908490611ffa565b6001600160a01b038086166000908152600d60205260408082209390935590871681522054610e0890849061200d565b6001600160a01b038087166000818152600d602052604090819020939093559151908616907fddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef90610e5c9087815260200190565b60405180910390a350600095945050505050565b6000805460ff16610e935760405162461bcd60e51b81526004016104f790611f61565b6000805460ff19168155610ea5610641565b90508015610ec957610a87816010811115610ec257610ec2611f85565b6001610fdf565b610c7a3384611467565b6000805460ff16610ef65760405162461bcd60e51b81526004016104f790611f61565b6000805460ff19168155610f08610641565b90508015610f3457610f2c816010811115610f2557610f25611f85565b6005610fdf565b9150506108fc565b826001600160a01b031663a6afed956040518163ffffffff1660e01b81526004016020604051808303816000875af1158015610f74573d6000803e3d6000fd5b505050506040513d601f19601f82011682018060405250810190610f989190611fcb565b90508015610fbc57610f2c816010811115610fb557610fb5611f85565b6006610fdf565b610fc8338686866115dd565b9150506000805460ff191660011790559392505050565b60007f45b96fe442630264581b197e84bbada861235052c5a1aadfff9ea4e40a969aa083601081111561101457611014611f85565b83602081111561102657611026611f85565b60408051928352602083019190915260009082015260600160405180910390a182601081111561056857610568611f85565b60048054604051631200453160e11b815230928101929092526001600160a01b0385811660248401528481166044840152606483018490526000928392839216906324008a62906084016020604051808303816000875af11580156110c1573d6000803e3d6000fd5b505050506040513d601f19601f820116820180604052508101906110e59190611fcb565b90508015611106576110fa60036018836113ef565b6000925092505061120e565b436008541461111b576110fa600a6019610fdf565b600061112686610bd0565b905060006000198614611139578561113b565b815b905060006111498983611a88565b905060006111578285611ffa565b9050600082600a546111699190611ffa565b6001600160a01b038b166000908152600e602052604090819020848155600954600190910155600a829055519091507f1a2a22cb034d26d1854bdc6666a5b91fe25efbbb5dcad3b0355478d6f5c362a1906111f9908d908d908790879087906001600160a01b03958616815293909416602084015260408301919091526060820152608081019190915260a00190565b60405180910390a16000975091955050505050505b935093915050565b80516000906104ce90670de0b6b3a764000090612037565b60048054604051634ef4c3e160e01b815260009283926001600160a01b031691634ef4c3e1916112649130918991899101612059565b6020604051808303816000875af1158015611283573d6000803e3d6000fd5b505050506040513d601f19601f820116820180604052508101906112a79190611fcb565b905080156112c4576112bc60036011836113ef565b9150506104ce565b43600854146112d9576112bc600a6012610fdf565b600060405180602001604052806112ee610ab3565b9052905060006112fe8686611a88565b9050600061130c8284611ca2565b905080600c5461131c919061200d565b600c556001600160a01b0387166000908152600d602052604090205461134390829061200d565b6001600160a01b0388166000818152600d60209081526040918290209390935580519182529181018490529081018290527f4c209b5fc8ad50758f13e2e1088ba56a560dff690a1c6fef26394f4c03821c4f9060600160405180910390a16040518181526001600160a01b0388169030907fddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef9060200160405180910390a360005b979650505050505050565b60007f45b96fe442630264581b197e84bbada861235052c5a1aadfff9ea4e40a969aa084601081111561142457611424611f85565b84602081111561143657611436611f85565b604080519283526020830191909152810184905260600160405180910390a18360108111156109d3576109d3611f85565b6004805460405163368f515360e21b815260009283926001600160a01b03169163da3d454c9161149d9130918991899101612059565b6020604051808303816000875af11580156114bc573d6000803e3d6000fd5b505050506040513d601f19601f820116820180604052508101906114e09190611fcb565b905080156114f5576112bc60036004836113ef565b436008541461150a576112bc600a6003610fdf565b82611513610b9f565b1015611525576112bc600e6002610fdf565b600061153085610bd0565b9050600061153e858361200d565b9050600085600a54611550919061200d565b6001600160a01b0388166000908152600e60205260409020838155600954600190910155600a81905590506115858787611cb9565b604080516001600160a01b038916815260208101889052908101839052606081018290527f13ed6866d4e1ee6da46f845c46d7e54120883d75c5ea9a2dacc1c4ca8984ab809060800160405180910390a160006113e4565b60048054604051632fe3f38f60e11b815230928101929092526001600160a01b038381166024840152868116604484015285811660648401526084830185905260009283929190911690635fc7e71e9060a4016020604051808303816000875af115801561164f573d6000803e3d6000fd5b505050506040513d601f19601f820116820180604052508101906116739190611fcb565b9050801561168857610d8760036008836113ef565b436008541461169d57610d87600a600b610fdf565b43836001600160a01b0316636c540baf6040518163ffffffff1660e01b8152600401602060405180830381865afa1580156116dc573d6000803e3d6000fd5b505050506040513d601f19601f820116820180604052508101906117009190611fcb565b1461171157610d87600a6007610fdf565b856001600160a01b0316856001600160a01b03160361173657610d876006600c610fdf565b8360000361174a57610d876007600a610fdf565b600019840361175f57610d8760076009610fdf565b60008061176d888888611058565b9092509050811561179e5761179482601081111561178d5761178d611f85565b600d610fdf565b93505050506109d3565b6004805460405163c488847b60e01b815260009283926001600160a01b03169163c488847b916117d49130918c91899101612059565b6040805180830381865afa1580156117f0573d6000803e3d6000fd5b505050506040513d601f19601f82011682018060405250810190611814919061207d565b909250905081156118835760405162461bcd60e51b815260206004820152603360248201527f4c49515549444154455f434f4d5054524f4c4c45525f43414c43554c4154455f604482015272105353d5539517d4d152569157d19052531151606a1b60648201526084016104f7565b6040516370a0823160e01b81526001600160a01b038a811660048301528291908916906370a0823190602401602060405180830381865afa1580156118cc573d6000803e3d6000fd5b505050506040513d601f19601f820116820180604052508101906118f09190611fcb565b101561193e5760405162461bcd60e51b815260206004820152601860248201527f4c49515549444154455f5345495a455f544f4f5f4d554348000000000000000060448201526064016104f7565b6000306001600160a01b038916036119635761195c308c8c85610cdc565b90506119d9565b60405163b2a02ff160e01b81526001600160a01b0389169063b2a02ff190611993908e908e908790600401612059565b6020604051808303816000875af11580156119b2573d6000803e3d6000fd5b505050506040513d601f19601f820116820180604052508101906119d69190611fcb565b90505b8015611a1e5760405162461bcd60e51b81526020600482015260146024820152731d1bdad95b881cd95a5e9d5c994819985a5b195960621b60448201526064016104f7565b604080516001600160a01b038d811682528c811660208301528183018790528a1660608201526080810184905290517f298637f684da70674f26509b10f07ec2fbc77a335ab1e7d6215a4b2484d8bb529181900360a00190a160009b9a5050505050505050505050565b600f546040516370a0823160e01b815230600482015260009182916001600160a01b03909116906370a0823190602401602060405180830381865afa158015611ad5573d6000803e3d6000fd5b505050506040513d601f19601f82011682018060405250810190611af99190611fcb565b600f5460405191925060009182916001600160a01b0316906323b872dd60e01b90611b2c90899030908a90602401612059565b60408051601f198184030181529181526020820180516001600160e01b03166001600160e01b0319909416939093179092529051611b6a91906120a1565b6000604051808303816000865af19150503d8060008114611ba7576040519150601f19603f3d011682016040523d82523d6000602084013e611bac565b606091505b5091509150818015611bd6575080511580611bd6575080806020019051810190611bd691906120bd565b611c225760405162461bcd60e51b815260206004820152601860248201527f544f4b454e5f5452414e534645525f494e5f4641494c4544000000000000000060448201526064016104f7565b600f546040516370a0823160e01b815230600482015284916001600160a01b0316906370a0823190602401602060405180830381865afa158015611c6a573d6000803e3d6000fd5b505050506040513d601f19601f82011682018060405250810190611c8e9190611fcb565b611c989190611ffa565b9695505050505050565b8051600090610c0d670de0b6b3a764000085612020565b600f54604080516001600160a01b038581166024830152604480830186905283518084039091018152606490920183526020820180516001600160e01b031663a9059cbb60e01b179052915160009384931691611d15916120a1565b6000604051808303816000865af19150503d8060008114611d52576040519150601f19603f3d011682016040523d82523d6000602084013e611d57565b606091505b5091509150818015611d81575080511580611d81575080806020019051810190611d8191906120bd565b611dcd5760405162461bcd60e51b815260206004820152601960248201527f544f4b454e5f5452414e534645525f4f55545f4641494c45440000000000000060448201526064016104f7565b50505050565b60005b83811015611dee578181015183820152602001611dd6565b50506000910152565b6020815260008251806020840152611e16816040850160208701611dd3565b601f01601f19169190910160400192915050565b600060208284031215611e3c57600080fd5b5035919050565b6001600160a01b0381168114611e5857600080fd5b50565b600060208284031215611e6d57600080fd5b813561056881611e43565b60008060408385031215611e8b57600080fd5b8235611e9681611e43565b946020939093013593505050565b600080600060608486031215611eb957600080fd5b8335611ec481611e43565b92506020840135611ed481611e43565b929592945050506040919091013590565b600080600060608486031215611efa57600080fd5b8335611f0581611e43565b9250602084013591506040840135611f1c81611e43565b809150509250925092565b600181811c90821680611f3b57607f821691505b602082108103611f5b57634e487b7160e01b600052602260045260246000fd5b50919050565b6020808252600a90820152691c994b595b9d195c995960b21b604082015260600190565b634e487b7160e01b600052602160045260246000fd5b6020808252601690820152751858d8dc9d59481a5b9d195c995cdd0819985a5b195960521b604082015260600190565b600060208284031215611fdd57600080fd5b5051919050565b634e487b7160e01b600052601160045260246000fd5b818103818111156104ce576104ce611fe4565b808201808211156104ce576104ce611fe4565b80820281158282048414176104ce576104ce611fe4565b60008261205457634e487b7160e01b600052601260045260246000fd5b500490565b6001600160a01b039384168152919092166020820152604081019190915260600190565b6000806040838503121561209057600080fd5b505080516020909101519092909150565b600082516120b3818460208701611dd3565b9190910192915050565b6000602082840312156120cf57600080fd5b8151801515811461056857600080fdfea26469706673582212207699c38907499b80b1c3bddecfec2752b7bcde8d26609b83590b42835cfab0f464736f6c63430008150033"
+
+// Multicall3ABI is the ABI of contracts/Multicall3.sol Multicall3
+const Multicall3ABI = "[{\"inputs\":[{\"components\":[{\"internalType\":\"address\",\"name\":\"target\",\"type\":\"address\"},{\"internalType\":\"bytes\",\"name\":\"callData\",\"type\":\"bytes\"}],\"internalType\":\"struct Multicall3.Call[]\",\"name\":\"calls\",\"type\":\"tuple[]\"}],\"name\":\"aggregate\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"blockNumber\",\"type\":\"uint256\"},{\"internalType\":\"bytes[]\",\"name\":\"returnData\",\"type\":\"bytes[]\"}],\"stateMutability\":\"payable\",\"type\":\"function\"},{\"inputs\":[{\"components\":[{\"internalType\":\"address\",\"name\":\"target\",\"type\":\"address\"},{\"internalType\":\"bool\",\"name\":\"allowFailure\",\"type\":\"bool\"},{\"internalType\":\"bytes\",\"name\":\"callData\",\"type\":\"bytes\"}],\"internalType\":\"struct Multicall3.Call3[]\",\"name\":\"calls\",\"type\":\"tuple[]\"}],\"name\":\"aggregate3\",\"outputs\":[{\"components\":[{\"internalType\":\"bool\",\"name\":\"success\",\"type\":\"bool\"},{\"internalType\":\"bytes\",\"name\":\"returnData\",\"type\":\"bytes\"}],\"internalType\":\"struct Multicall3.Result[]\",\"name\":\"returnData\",\"type\":\"tuple[]\"}],\"stateMutability\":\"payable\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"getBlockNumber\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"blockNumber\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"getChainId\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"chainid\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"getCurrentBlockTimestamp\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"timestamp\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"addr\",\"type\":\"address\"}],\"name\":\"getEthBalance\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"balance\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bool\",\"name\":\"requireSuccess\",\"type\":\"bool\"},{\"components\":[{\"internalType\":\"address\",\"name\":\"target\",\"type\":\"address\"},{\"internalType\":\"bytes\",\"name\":\"callData\",\"type\":\"bytes\"}],\"internalType\":\"struct Multicall3.Call[]\",\"name\":\"calls\",\"type\":\"tuple[]\"}],\"name\":\"tryAggregate\",\"outputs\":[{\"components\":[{\"internalType\":\"bool\",\"name\":\"success\",\"type\":\"bool\"},{\"internalType\":\"bytes\",\"name\":\"returnData\",\"type\":\"bytes\"}],\"internalType\":\"struct Multicall3.Result[]\",\"name\":\"returnData\",\"type\":\"tuple[]\"}],\"stateMutability\":\"payable\",\"type\":\"function\"}]"
+
+// Multicall3Runtime is the runtime code of Multicall3
+const Multicall3Runtime = "0x6080604052600436106100705760003560e01c806342cbb15c1161004e57806342cbb15c146100cb5780634d2301cc146100de57806382ad56cb14610106578063bce38bd71461012657600080fd5b80630f28c97d14610075578063252dba42146100975780633408e470146100b8575b600080fd5b34801561008157600080fd5b50425b6040519081526020015b60405180910390f35b6100aa6100a536600461061c565b610139565b60405161008e9291906106a4565b3480156100c457600080fd5b5046610084565b3480156100d757600080fd5b5043610084565b3480156100ea57600080fd5b506100846100f936600461070e565b6001600160a01b03163190565b61011961011436600461061c565b6102b6565b60405161008e919061073e565b6101196101343660046107c9565b61043d565b4360608267ffffffffffffffff8111156101555761015561081c565b60405190808252806020026020018201604052801561018857816020015b60608152602001906001900390816101735790505b50905060005b838110156102ae5760008585838181106101aa576101aa610832565b90506020028101906101bc9190610848565b6101ca90602081019061070e565b6001600160a01b03168686848181106101e5576101e5610832565b90506020028101906101f79190610848565b610205906020810190610868565b6040516102139291906108af565b6000604051808303816000865af19150503d8060008114610250576040519150601f19603f3d011682016040523d82523d6000602084013e610255565b606091505b5084848151811061026857610268610832565b602090810291909101015290508061029b5760405162461bcd60e51b8152600401610292906108bf565b60405180910390fd5b50806102a6816108f6565b91505061018e565b509250929050565b60608167ffffffffffffffff8111156102d1576102d161081c565b60405190808252806020026020018201604052801561031757816020015b6040805180820190915260008152606060208201528152602001906001900390816102ef5790505b50905060005b8281101561043657600082828151811061033957610339610832565b602002602001015190503685858481811061035657610356610832565b9050602002810190610368919061091d565b9050610377602082018261070e565b6001600160a01b031661038d6040830183610868565b60405161039b9291906108af565b6000604051808303816000865af19150503d80600081146103d8576040519150601f19603f3d011682016040523d82523d6000602084013e6103dd565b606091505b5060208085019190915290151583526103fc9060408301908301610933565b80610405575081515b6104215760405162461bcd60e51b8152600401610292906108bf565b5050808061042e906108f6565b91505061031d565b5092915050565b60608167ffffffffffffffff8111156104585761045861081c565b60405190808252806020026020018201604052801561049e57816020015b6040805180820190915260008152606060208201528152602001906001900390816104765790505b50905060005b828110156105c85760008282815181106104c0576104c0610832565b602002602001015190508484838181106104dc576104dc610832565b90506020028101906104ee9190610848565b6104fc90602081019061070e565b6001600160a01b031685858481811061051757610517610832565b90506020028101906105299190610848565b610537906020810190610868565b6040516105459291906108af565b6000604051808303816000865af19150503d8060008114610582576040519150601f19603f3d011682016040523d82523d6000602084013e610587565b606091505b5060208301521515815285156105b55780516105b55760405162461bcd60e51b8152600401610292906108bf565b50806105c0816108f6565b9150506104a4565b509392505050565b60008083601f8401126105e257600080fd5b50813567ffffffffffffffff8111156105fa57600080fd5b6020830191508360208260051b850101111561061557600080fd5b9250929050565b6000806020838503121561062f57600080fd5b823567ffffffffffffffff81111561064657600080fd5b610652858286016105d0565b90969095509350505050565b6000815180845260005b8181101561068457602081850181015186830182015201610668565b506000602082860101526020601f19601f83011685010191505092915050565b600060408201848352602060408185015281855180845260608601915060608160051b870101935082870160005b8281101561070057605f198887030184526106ee86835161065e565b955092840192908401906001016106d2565b509398975050505050505050565b60006020828403121561072057600080fd5b81356001600160a01b038116811461073757600080fd5b9392505050565b60006020808301818452808551808352604092508286019150828160051b87010184880160005b838110156107a657888303603f1901855281518051151584528701518784018790526107938785018261065e565b9588019593505090860190600101610765565b509098975050505050505050565b803580151581146107c457600080fd5b919050565b6000806000604084860312156107de57600080fd5b6107e7846107b4565b9250602084013567ffffffffffffffff81111561080357600080fd5b61080f868287016105d0565b9497909650939450505050565b634e487b7160e01b600052604160045260246000fd5b634e487b7160e01b600052603260045260246000fd5b60008235603e1983360301811261085e57600080fd5b9190910192915050565b6000808335601e1984360301811261087f57600080fd5b83018035915067ffffffffffffffff82111561089a57600080fd5b60200191503681900382131561061557600080fd5b8183823760009101908152919050565b60208082526017908201527f4d756c746963616c6c333a2063616c6c206661696c6564000000000000000000604082015260600190565b60006001820161091657634e487b7160e01b600052601160045260246000fd5b5060010190565b60008235605e1983360301811261085e57600080fd5b60006020828403121561094557600080fd5b610737826107b456fea2646970667358221220dd84e26d5a836522864dcaac996461d88c6aef183c0383edc90e1dc6c2b679d064736f6c63430008150033"
@@ -0,0 +1,119 @@
+package simulated
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"net/url"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/config"
+)
+
+// Config represents bot config for the harness, fields can be changed to drive other code paths
+type Config struct {
+	Key              *ecdsa.PrivateKey
+	Interval         time.Duration
+	RPC              *url.URL
+	Comptroller      common.Address
+	CUSDC            common.Address
+	MarketList       []config.Market
+	GasSettings      config.Gas
+	ProfitSettings   config.Profit
+	DryRunSettings   config.DryRun
+	StoreSettings    config.Store
+	HTTPSettings     config.HTTP
+	HealthSettings   config.Health
+	LogSettings      config.Log
+	NotifySettings   config.Notify
+	ScanSettings     config.Scan
+	ChainStartChecks bool
+}
+
+func (c *Config) RPCURL() *url.URL {
+	return c.RPC
+}
+
+func (c *Config) AccountAddress() common.Address {
+	return crypto.PubkeyToAddress(c.Key.PublicKey)
+}
+
+func (c *Config) AccountKey() *ecdsa.PrivateKey {
+	return c.Key
+}
+
+func (c *Config) Account() config.Account {
+	return config.Account{Address: c.AccountAddress()}
+}
+
+func (c *Config) UpdateInterval() time.Duration {
+	return c.Interval
+}
+
+func (c *Config) ContractComptrollerAddress() common.Address {
+	return c.Comptroller
+}
+
+func (c *Config) ContractCusdcAddress() common.Address {
+	return c.CUSDC
+}
+
+func (c *Config) ContractMulticallAddress() common.Address {
+	return MulticallAddress
+}
+
+func (c *Config) Markets() []config.Market {
+	return c.MarketList
+}
+
+func (c *Config) Gas() config.Gas {
+	return c.GasSettings
+}
+
+func (c *Config) Profit() config.Profit {
+	return c.ProfitSettings
+}
+
+func (c *Config) Endpoints() config.Endpoints {
+	return config.Endpoints{RPC: c.RPC}
+}
+
+func (c *Config) DryRun() config.DryRun {
+	return c.DryRunSettings
+}
+
+func (c *Config) Store() config.Store {
+	return c.StoreSettings
+}
+
+func (c *Config) HTTP() config.HTTP {
+	return c.HTTPSettings
+}
+
+func (c *Config) Health() config.Health {
+	return c.HealthSettings
+}
+
+func (c *Config) Log() config.Log {
+	return c.LogSettings
+}
+
+func (c *Config) Notify() config.Notify {
+	return c.NotifySettings
+}
+
+func (c *Config) Scan() config.Scan {
+	return c.ScanSettings
+}
+
+func (c *Config) ChainID() *big.Int {
+	return big.NewInt(simulatedChainID)
+}
+
+func (c *Config) StartupChecks() bool {
+	return c.ChainStartChecks
+}
+
+// simulatedChainID is the chain id of go-ethereum simulated backend
+const simulatedChainID = 1337
@@ -0,0 +1,447 @@
+// SPDX-License-Identifier: BSD-3-Clause
+pragma solidity 0.8.21;
+
+import "./ErrorReporter.sol";
+import "./ExponentialNoError.sol";
+import "./Interfaces.sol";
+
+// CToken is the money market of compound-protocol v2 CToken: interest accrual, mint, borrow, repay,
+// liquidate and seize, returning error codes like the original. Redeem, cToken transfers and
+// admin functions other than the constructor are left out.
+abstract contract CToken is CTokenInterface, TokenErrorReporter, ExponentialNoError {
+    bool internal _notEntered;
+
+    string public name;
+    string public symbol;
+    uint8 public decimals;
+
+    // maximum borrow rate that can ever be applied (.0005% / block)
+    uint internal constant borrowRateMaxMantissa = 0.0005e16;
+
+    address payable public admin;
+    address public override comptroller;
+    InterestRateModel public interestRateModel;
+    uint internal initialExchangeRateMantissa;
+    uint public reserveFactorMantissa;
+    uint public accrualBlockNumber;
+    uint public borrowIndex;
+    uint public totalBorrows;
+    uint public totalReserves;
+    uint public totalSupply;
+
+    mapping(address => uint) internal accountTokens;
+
+    struct BorrowSnapshot {
+        uint principal;
+        uint interestIndex;
+    }
+
+    mapping(address => BorrowSnapshot) internal accountBorrows;
+
+    event AccrueInterest(uint interestAccumulated, uint borrowIndex, uint totalBorrows);
+    event Mint(address minter, uint mintAmount, uint mintTokens);
+    event Borrow(address borrower, uint borrowAmount, uint accountBorrows, uint totalBorrows);
+    event RepayBorrow(address payer, address borrower, uint repayAmount, uint accountBorrows, uint totalBorrows);
+    event LiquidateBorrow(address liquidator, address borrower, uint repayAmount, address cTokenCollateral, uint seizeTokens);
+    event Transfer(address indexed from, address indexed to, uint amount);
+
+    constructor(
+        address comptroller_,
+        InterestRateModel interestRateModel_,
+        uint initialExchangeRateMantissa_,
+        string memory name_,
+        string memory symbol_,
+        uint8 decimals_
+    ) {
+        require(initialExchangeRateMantissa_ > 0, "initial exchange rate must be greater than zero.");
+        require(ComptrollerInterface(comptroller_).isComptroller(), "marker method returned false");
+
+        admin = payable(msg.sender);
+        comptroller = comptroller_;
+        interestRateModel = interestRateModel_;
+        initialExchangeRateMantissa = initialExchangeRateMantissa_;
+        accrualBlockNumber = block.number;
+        borrowIndex = 1e18;
+
+        name = name_;
+        symbol = symbol_;
+        decimals = decimals_;
+
+        _notEntered = true;
+    }
+
+    modifier nonReentrant() {
+        require(_notEntered, "re-entered");
+        _notEntered = false;
+        _;
+        _notEntered = true;
+    }
+
+    function isCToken() external pure override returns (bool) {
+        return true;
+    }
+
+    function balanceOf(address owner) external view override returns (uint256) {
+        return accountTokens[owner];
+    }
+
+    function balanceOfUnderlying(address owner) external returns (uint) {
+        Exp memory exchangeRate = Exp({mantissa: exchangeRateCurrent()});
+        return mul_ScalarTruncate(exchangeRate, accountTokens[owner]);
+    }
+
+    function getAccountSnapshot(address account) external view override returns (uint, uint, uint, uint) {
+        return (uint(Error.NO_ERROR), accountTokens[account], borrowBalanceStoredInternal(account), exchangeRateStoredInternal());
+    }
+
+    function borrowRatePerBlock() external view returns (uint) {
+        return interestRateModel.getBorrowRate(getCashPrior(), totalBorrows, totalReserves);
+    }
+
+    function supplyRatePerBlock() external view returns (uint) {
+        return interestRateModel.getSupplyRate(getCashPrior(), totalBorrows, totalReserves, reserveFactorMantissa);
+    }
+
+    function totalBorrowsCurrent() external nonReentrant returns (uint) {
+        require(accrueInterest() == uint(Error.NO_ERROR), "accrue interest failed");
+        return totalBorrows;
+    }
+
+    function borrowBalanceCurrent(address account) external nonReentrant returns (uint) {
+        require(accrueInterest() == uint(Error.NO_ERROR), "accrue interest failed");
+        return borrowBalanceStored(account);
+    }
+
+    function borrowBalanceStored(address account) public view override returns (uint) {
+        return borrowBalanceStoredInternal(account);
+    }
+
+    function borrowBalanceStoredInternal(address account) internal view returns (uint) {
+        BorrowSnapshot storage borrowSnapshot = accountBorrows[account];
+
+        // if borrowBalance = 0 then borrowIndex is likely also 0
+        if (borrowSnapshot.principal == 0) {
+            return 0;
+        }
+
+        // recentBorrowBalance = borrower.borrowBalance * market.borrowIndex / borrower.borrowIndex
+        return (borrowSnapshot.principal * borrowIndex) / borrowSnapshot.interestIndex;
+    }
+
+    function exchangeRateCurrent() public nonReentrant returns (uint) {
+        require(accrueInterest() == uint(Error.NO_ERROR), "accrue interest failed");
+        return exchangeRateStored();
+    }
+
+    function exchangeRateStored() public view override returns (uint) {
+        return exchangeRateStoredInternal();
+    }
+
+    function exchangeRateStoredInternal() internal view returns (uint) {
+        uint _totalSupply = totalSupply;
+        if (_totalSupply == 0) {
+            return initialExchangeRateMantissa;
+        }
+
+        // exchangeRate = (totalCash + totalBorrows - totalReserves) / totalSupply
+        uint cashPlusBorrowsMinusReserves = getCashPrior() + totalBorrows - totalReserves;
+        return (cashPlusBorrowsMinusReserves * expScale) / _totalSupply;
+    }
+
+    function getCash() external view returns (uint) {
+        return getCashPrior();
+    }
+
+    function accrueInterest() public override returns (uint) {
+        uint currentBlockNumber = block.number;
+        uint accrualBlockNumberPrior = accrualBlockNumber;
+        if (accrualBlockNumberPrior == currentBlockNumber) {
+            return uint(Error.NO_ERROR);
+        }
+
+        uint cashPrior = getCashPrior();
+        uint borrowsPrior = totalBorrows;
+        uint reservesPrior = totalReserves;
+        uint borrowIndexPrior = borrowIndex;
+
+        uint borrowRateMantissa = interestRateModel.getBorrowRate(cashPrior, borrowsPrior, reservesPrior);
+        require(borrowRateMantissa <= borrowRateMaxMantissa, "borrow rate is absurdly high");
+
+        uint blockDelta = currentBlockNumber - accrualBlockNumberPrior;
+
+        Exp memory simpleInterestFactor = mul_(Exp({mantissa: borrowRateMantissa}), blockDelta);
+        uint interestAccumulated = mul_ScalarTruncate(simpleInterestFactor, borrowsPrior);
+        uint totalBorrowsNew = interestAccumulated + borrowsPrior;
+        uint totalReservesNew =
+            mul_ScalarTruncateAddUInt(Exp({mantissa: reserveFactorMantissa}), interestAccumulated, reservesPrior);
+        uint borrowIndexNew = mul_ScalarTruncateAddUInt(simpleInterestFactor, borrowIndexPrior, borrowIndexPrior);
+
+        accrualBlockNumber = currentBlockNumber;
+        borrowIndex = borrowIndexNew;
+        totalBorrows = totalBorrowsNew;
+        totalReserves = totalReservesNew;
+
+        emit AccrueInterest(interestAccumulated, borrowIndexNew, totalBorrowsNew);
+        return uint(Error.NO_ERROR);
+    }
+
+    /*** Mint ***/
+
+    function mintInternal(uint mintAmount) internal nonReentrant returns (uint) {
+        uint error = accrueInterest();
+        if (error != uint(Error.NO_ERROR)) {
+            return fail(Error(error), FailureInfo.MINT_ACCRUE_INTEREST_FAILED);
+        }
+        return mintFresh(msg.sender, mintAmount);
+    }
+
+    function mintFresh(address minter, uint mintAmount) internal returns (uint) {
+        uint allowed = ComptrollerInterface(comptroller).mintAllowed(address(this), minter, mintAmount);
+        if (allowed != 0) {
+            return failOpaque(Error.COMPTROLLER_REJECTION, FailureInfo.MINT_COMPTROLLER_REJECTION, allowed);
+        }
+        if (accrualBlockNumber != block.number) {
+            return fail(Error.MARKET_NOT_FRESH, FailureInfo.MINT_FRESHNESS_CHECK);
+        }
+
+        Exp memory exchangeRate = Exp({mantissa: exchangeRateStoredInternal()});
+
+        uint actualMintAmount = doTransferIn(minter, mintAmount);
+        uint mintTokens = div_(actualMintAmount, exchangeRate);
+
+        totalSupply = totalSupply + mintTokens;
+        accountTokens[minter] = accountTokens[minter] + mintTokens;
+
+        emit Mint(minter, actualMintAmount, mintTokens);
+        emit Transfer(address(this), minter, mintTokens);
+        return uint(Error.NO_ERROR);
+    }
+
+    /*** Borrow ***/
+
+    function borrowInternal(uint borrowAmount) internal nonReentrant returns (uint) {
+        uint error = accrueInterest();
+        if (error != uint(Error.NO_ERROR)) {
+            return fail(Error(error), FailureInfo.BORROW_ACCRUE_INTEREST_FAILED);
+        }
+        return borrowFresh(payable(msg.sender), borrowAmount);
+    }
+
+    function borrowFresh(address payable borrower, uint borrowAmount) internal returns (uint) {
+        uint allowed = ComptrollerInterface(comptroller).borrowAllowed(address(this), borrower, borrowAmount);
+        if (allowed != 0) {
+            return failOpaque(Error.COMPTROLLER_REJECTION, FailureInfo.BORROW_COMPTROLLER_REJECTION, allowed);
+        }
+        if (accrualBlockNumber != block.number) {
+            return fail(Error.MARKET_NOT_FRESH, FailureInfo.BORROW_FRESHNESS_CHECK);
+        }
+        if (getCashPrior() < borrowAmount) {
+            return fail(Error.TOKEN_INSUFFICIENT_CASH, FailureInfo.BORROW_CASH_NOT_AVAILABLE);
+        }
+
+        uint accountBorrowsPrev = borrowBalanceStoredInternal(borrower);
+        uint accountBorrowsNew = accountBorrowsPrev + borrowAmount;
+        uint totalBorrowsNew = totalBorrows + borrowAmount;
+
+        accountBorrows[borrower].principal = accountBorrowsNew;
+        accountBorrows[borrower].interestIndex = borrowIndex;
+        totalBorrows = totalBorrowsNew;
+
+        doTransferOut(borrower, borrowAmount);
+
+        emit Borrow(borrower, borrowAmount, accountBorrowsNew, totalBorrowsNew);
+        return uint(Error.NO_ERROR);
+    }
+
+    /*** Repay ***/
+
+    function repayBorrowInternal(uint repayAmount) internal nonReentrant returns (uint) {
+        uint error = accrueInterest();
+        if (error != uint(Error.NO_ERROR)) {
+            return fail(Error(error), FailureInfo.REPAY_BORROW_ACCRUE_INTEREST_FAILED);
+        }
+        (uint err, ) = repayBorrowFresh(msg.sender, msg.sender, repayAmount);
+        return err;
+    }
+
+    function repayBorrowBehalfInternal(address borrower, uint repayAmount) internal nonReentrant returns (uint) {
+        uint error = accrueInterest();
+        if (error != uint(Error.NO_ERROR)) {
+            return fail(Error(error), FailureInfo.REPAY_BORROW_ACCRUE_INTEREST_FAILED);
+        }
+        (uint err, ) = repayBorrowFresh(msg.sender, borrower, repayAmount);
+        return err;
+    }
+
+    function repayBorrowFresh(address payer, address borrower, uint repayAmount) internal returns (uint, uint) {
+        uint allowed = ComptrollerInterface(comptroller).repayBorrowAllowed(address(this), payer, borrower, repayAmount);
+        if (allowed != 0) {
+            return (failOpaque(Error.COMPTROLLER_REJECTION, FailureInfo.REPAY_BORROW_COMPTROLLER_REJECTION, allowed), 0);
+        }
+        if (accrualBlockNumber != block.number) {
+            return (fail(Error.MARKET_NOT_FRESH, FailureInfo.REPAY_BORROW_FRESHNESS_CHECK), 0);
+        }
+
+        uint accountBorrowsPrev = borrowBalanceStoredInternal(borrower);
+
+        // repayAmount == type(uint).max repays the whole borrow
+        uint repayAmountFinal = repayAmount == type(uint).max ? accountBorrowsPrev : repayAmount;
+
+        uint actualRepayAmount = doTransferIn(payer, repayAmountFinal);
+
+        uint accountBorrowsNew = accountBorrowsPrev - actualRepayAmount;
+        uint totalBorrowsNew = totalBorrows - actualRepayAmount;
+
+        accountBorrows[borrower].principal = accountBorrowsNew;
+        accountBorrows[borrower].interestIndex = borrowIndex;
+        totalBorrows = totalBorrowsNew;
+
+        emit RepayBorrow(payer, borrower, actualRepayAmount, accountBorrowsNew, totalBorrowsNew);
+        return (uint(Error.NO_ERROR), actualRepayAmount);
+    }
+
+    /*** Liquidate ***/
+
+    function liquidateBorrowInternal(address borrower, uint repayAmount, CTokenInterface cTokenCollateral) internal nonReentrant returns (uint) {
+        uint error = accrueInterest();
+        if (error != uint(Error.NO_ERROR)) {
+            return fail(Error(error), FailureInfo.LIQUIDATE_ACCRUE_BORROW_INTEREST_FAILED);
+        }
+
+        error = cTokenCollateral.accrueInterest();
+        if (error != uint(Error.NO_ERROR)) {
+            return fail(Error(error), FailureInfo.LIQUIDATE_ACCRUE_COLLATERAL_INTEREST_FAILED);
+        }
+
+        return liquidateBorrowFresh(msg.sender, borrower, repayAmount, cTokenCollateral);
+    }
+
+    function liquidateBorrowFresh(address liquidator, address borrower, uint repayAmount, CTokenInterface cTokenCollateral) internal returns (uint) {
+        uint allowed = ComptrollerInterface(comptroller).liquidateBorrowAllowed(
+            address(this), address(cTokenCollateral), liquidator, borrower, repayAmount);
+        if (allowed != 0) {
+            return failOpaque(Error.COMPTROLLER_REJECTION, FailureInfo.LIQUIDATE_COMPTROLLER_REJECTION, allowed);
+        }
+        if (accrualBlockNumber != block.number) {
+            return fail(Error.MARKET_NOT_FRESH, FailureInfo.LIQUIDATE_FRESHNESS_CHECK);
+        }
+        if (CToken(address(cTokenCollateral)).accrualBlockNumber() != block.number) {
+            return fail(Error.MARKET_NOT_FRESH, FailureInfo.LIQUIDATE_COLLATERAL_FRESHNESS_CHECK);
+        }
+        if (borrower == liquidator) {
+            return fail(Error.INVALID_ACCOUNT_PAIR, FailureInfo.LIQUIDATE_LIQUIDATOR_IS_BORROWER);
+        }
+        if (repayAmount == 0) {
+            return fail(Error.INVALID_CLOSE_AMOUNT_REQUESTED, FailureInfo.LIQUIDATE_CLOSE_AMOUNT_IS_ZERO);
+        }
+        if (repayAmount == type(uint).max) {
+            return fail(Error.INVALID_CLOSE_AMOUNT_REQUESTED, FailureInfo.LIQUIDATE_CLOSE_AMOUNT_IS_UINT_MAX);
+        }
+
+        (uint repayBorrowError, uint actualRepayAmount) = repayBorrowFresh(liquidator, borrower, repayAmount);
+        if (repayBorrowError != uint(Error.NO_ERROR)) {
+            return fail(Error(repayBorrowError), FailureInfo.LIQUIDATE_REPAY_BORROW_FRESH_FAILED);
+        }
+
+        (uint amountSeizeError, uint seizeTokens) = ComptrollerInterface(comptroller).liquidateCalculateSeizeTokens(
+            address(this), address(cTokenCollateral), actualRepayAmount);
+        require(amountSeizeError == uint(Error.NO_ERROR), "LIQUIDATE_COMPTROLLER_CALCULATE_AMOUNT_SEIZE_FAILED");
+        require(cTokenCollateral.balanceOf(borrower) >= seizeTokens, "LIQUIDATE_SEIZE_TOO_MUCH");
+
+        uint seizeError;
+        if (address(cTokenCollateral) == address(this)) {
+            seizeError = seizeInternal(address(this), liquidator, borrower, seizeTokens);
+        } else {
+            seizeError = cTokenCollateral.seize(liquidator, borrower, seizeTokens);
+        }
+        require(seizeError == uint(Error.NO_ERROR), "token seizure failed");
+
+        emit LiquidateBorrow(liquidator, borrower, actualRepayAmount, address(cTokenCollateral), seizeTokens);
+        return uint(Error.NO_ERROR);
+    }
+
+    function seize(address liquidator, address borrower, uint seizeTokens) external override nonReentrant returns (uint) {
+        return seizeInternal(msg.sender, liquidator, borrower, seizeTokens);
+    }
+
+    function seizeInternal(address seizerToken, address liquidator, address borrower, uint seizeTokens) internal returns (uint) {
+        uint allowed = ComptrollerInterface(comptroller).seizeAllowed(address(this), seizerToken, liquidator, borrower, seizeTokens);
+        if (allowed != 0) {
+            return failOpaque(Error.COMPTROLLER_REJECTION, FailureInfo.LIQUIDATE_SEIZE_COMPTROLLER_REJECTION, allowed);
+        }
+        if (borrower == liquidator) {
+            return fail(Error.INVALID_ACCOUNT_PAIR, FailureInfo.LIQUIDATE_SEIZE_LIQUIDATOR_IS_BORROWER);
+        }
+
+        accountTokens[borrower] = accountTokens[borrower] - seizeTokens;
+        accountTokens[liquidator] = accountTokens[liquidator] + seizeTokens;
+
+        emit Transfer(borrower, liquidator, seizeTokens);
+        return uint(Error.NO_ERROR);
+    }
+
+    /*** Underlying ***/
+
+    function getCashPrior() internal view virtual returns (uint);
+
+    function doTransferIn(address from, uint amount) internal virtual returns (uint);
+
+    function doTransferOut(address payable to, uint amount) internal virtual;
+}
+
+// CErc20 is a CToken wrapping an ERC20 underlying, like compound-protocol CErc20Immutable
+contract CErc20 is CToken {
+    address public override underlying;
+
+    constructor(
+        address underlying_,
+        address comptroller_,
+        InterestRateModel interestRateModel_,
+        uint initialExchangeRateMantissa_,
+        string memory name_,
+        string memory symbol_,
+        uint8 decimals_
+    ) CToken(comptroller_, interestRateModel_, initialExchangeRateMantissa_, name_, symbol_, decimals_) {
+        underlying = underlying_;
+    }
+
+    function mint(uint mintAmount) external returns (uint) {
+        return mintInternal(mintAmount);
+    }
+
+    function borrow(uint borrowAmount) external returns (uint) {
+        return borrowInternal(borrowAmount);
+    }
+
+    function repayBorrow(uint repayAmount) external returns (uint) {
+        return repayBorrowInternal(repayAmount);
+    }
+
+    function repayBorrowBehalf(address borrower, uint repayAmount) external returns (uint) {
+        return repayBorrowBehalfInternal(borrower, repayAmount);
+    }
+
+    function liquidateBorrow(address borrower, uint repayAmount, CTokenInterface cTokenCollateral) external returns (uint) {
+        return liquidateBorrowInternal(borrower, repayAmount, cTokenCollateral);
+    }
+
+    function getCashPrior() internal view override returns (uint) {
+        return EIP20Interface(underlying).balanceOf(address(this));
+    }
+
+    // doTransferIn transfers amount in and returns the amount actually received,
+    // it reverts when the token call fails or returns false
+    function doTransferIn(address from, uint amount) internal override returns (uint) {
+        uint balanceBefore = EIP20Interface(underlying).balanceOf(address(this));
+        (bool success, bytes memory result) =
+            underlying.call(abi.encodeWithSelector(EIP20Interface.transferFrom.selector, from, address(this), amount));
+        require(success && (result.length == 0 || abi.decode(result, (bool))), "TOKEN_TRANSFER_IN_FAILED");
+        return EIP20Interface(underlying).balanceOf(address(this)) - balanceBefore;
+    }
+
+    function doTransferOut(address payable to, uint amount) internal override {
+        (bool success, bytes memory result) =
+            underlying.call(abi.encodeWithSelector(EIP20Interface.transfer.selector, to, amount));
+        require(success && (result.length == 0 || abi.decode(result, (bool))), "TOKEN_TRANSFER_OUT_FAILED");
+    }
+}
@@ -0,0 +1,363 @@
+// SPDX-License-Identifier: BSD-3-Clause
+pragma solidity 0.8.21;
+
+import "./ErrorReporter.sol";
+import "./ExponentialNoError.sol";
+import "./Interfaces.sol";
+
+// Comptroller is the risk model of compound-protocol v2 Comptroller: market listing and membership,
+// account liquidity and the mint, borrow, repay, liquidate and seize checks. Redeem, transfers,
+// pause guardians and COMP distribution are left out.
+contract Comptroller is ComptrollerInterface, ComptrollerErrorReporter, ExponentialNoError {
+    struct Market {
+        bool isListed;
+        uint collateralFactorMantissa;
+        mapping(address => bool) accountMembership;
+        bool isComped;
+    }
+
+    address public admin;
+    PriceOracle public oracle;
+    uint public closeFactorMantissa;
+    uint public liquidationIncentiveMantissa;
+    mapping(address => address[]) public accountAssets;
+    mapping(address => Market) public markets;
+    address[] public allMarkets;
+
+    event MarketListed(address cToken);
+    event MarketEntered(address cToken, address account);
+    event NewCloseFactor(uint oldCloseFactorMantissa, uint newCloseFactorMantissa);
+    event NewCollateralFactor(address cToken, uint oldCollateralFactorMantissa, uint newCollateralFactorMantissa);
+    event NewLiquidationIncentive(uint oldLiquidationIncentiveMantissa, uint newLiquidationIncentiveMantissa);
+    event NewPriceOracle(address oldPriceOracle, address newPriceOracle);
+
+    // closeFactorMantissa must be strictly greater than this value
+    uint internal constant closeFactorMinMantissa = 0.05e18;
+    // closeFactorMantissa must not exceed this value
+    uint internal constant closeFactorMaxMantissa = 0.9e18;
+    // no collateralFactorMantissa may exceed this value
+    uint internal constant collateralFactorMaxMantissa = 0.9e18;
+
+    constructor() {
+        admin = msg.sender;
+    }
+
+    function isComptroller() external pure override returns (bool) {
+        return true;
+    }
+
+    /*** Assets You Are In ***/
+
+    function getAssetsIn(address account) external view returns (address[] memory) {
+        return accountAssets[account];
+    }
+
+    function checkMembership(address account, address cToken) external view returns (bool) {
+        return markets[cToken].accountMembership[account];
+    }
+
+    function getAllMarkets() external view returns (address[] memory) {
+        return allMarkets;
+    }
+
+    function enterMarkets(address[] memory cTokens) public returns (uint[] memory) {
+        uint[] memory results = new uint[](cTokens.length);
+        for (uint i = 0; i < cTokens.length; i++) {
+            results[i] = uint(addToMarketInternal(cTokens[i], msg.sender));
+        }
+        return results;
+    }
+
+    function addToMarketInternal(address cToken, address borrower) internal returns (Error) {
+        Market storage marketToJoin = markets[cToken];
+        if (!marketToJoin.isListed) {
+            return Error.MARKET_NOT_LISTED;
+        }
+        if (marketToJoin.accountMembership[borrower]) {
+            return Error.NO_ERROR;
+        }
+
+        marketToJoin.accountMembership[borrower] = true;
+        accountAssets[borrower].push(cToken);
+
+        emit MarketEntered(cToken, borrower);
+        return Error.NO_ERROR;
+    }
+
+    /*** Policy Hooks ***/
+
+    function mintAllowed(address cToken, address, uint) external view override returns (uint) {
+        if (!markets[cToken].isListed) {
+            return uint(Error.MARKET_NOT_LISTED);
+        }
+        return uint(Error.NO_ERROR);
+    }
+
+    function borrowAllowed(address cToken, address borrower, uint borrowAmount) external override returns (uint) {
+        if (!markets[cToken].isListed) {
+            return uint(Error.MARKET_NOT_LISTED);
+        }
+
+        if (!markets[cToken].accountMembership[borrower]) {
+            // only cTokens may call borrowAllowed if borrower not in market
+            require(msg.sender == cToken, "sender must be cToken");
+
+            Error addErr = addToMarketInternal(msg.sender, borrower);
+            if (addErr != Error.NO_ERROR) {
+                return uint(addErr);
+            }
+        }
+
+        if (oracle.getUnderlyingPrice(cToken) == 0) {
+            return uint(Error.PRICE_ERROR);
+        }
+
+        (Error err, , uint shortfall) = getHypotheticalAccountLiquidityInternal(borrower, cToken, 0, borrowAmount);
+        if (err != Error.NO_ERROR) {
+            return uint(err);
+        }
+        if (shortfall > 0) {
+            return uint(Error.INSUFFICIENT_LIQUIDITY);
+        }
+
+        return uint(Error.NO_ERROR);
+    }
+
+    function repayBorrowAllowed(address cToken, address, address, uint) external view override returns (uint) {
+        if (!markets[cToken].isListed) {
+            return uint(Error.MARKET_NOT_LISTED);
+        }
+        return uint(Error.NO_ERROR);
+    }
+
+    function liquidateBorrowAllowed(
+        address cTokenBorrowed,
+        address cTokenCollateral,
+        address,
+        address borrower,
+        uint repayAmount
+    ) external view override returns (uint) {
+        if (!markets[cTokenBorrowed].isListed || !markets[cTokenCollateral].isListed) {
+            return uint(Error.MARKET_NOT_LISTED);
+        }
+
+        // the borrower must have shortfall in order to be liquidatable
+        (Error err, , uint shortfall) = getHypotheticalAccountLiquidityInternal(borrower, address(0), 0, 0);
+        if (err != Error.NO_ERROR) {
+            return uint(err);
+        }
+        if (shortfall == 0) {
+            return uint(Error.INSUFFICIENT_SHORTFALL);
+        }
+
+        // the liquidator may not repay more than what is allowed by the closeFactor
+        uint borrowBalance = CTokenInterface(cTokenBorrowed).borrowBalanceStored(borrower);
+        uint maxClose = mul_ScalarTruncate(Exp({mantissa: closeFactorMantissa}), borrowBalance);
+        if (repayAmount > maxClose) {
+            return uint(Error.TOO_MUCH_REPAY);
+        }
+
+        return uint(Error.NO_ERROR);
+    }
+
+    function seizeAllowed(
+        address cTokenCollateral,
+        address cTokenBorrowed,
+        address,
+        address,
+        uint
+    ) external view override returns (uint) {
+        if (!markets[cTokenCollateral].isListed || !markets[cTokenBorrowed].isListed) {
+            return uint(Error.MARKET_NOT_LISTED);
+        }
+
+        if (CTokenInterface(cTokenCollateral).comptroller() != CTokenInterface(cTokenBorrowed).comptroller()) {
+            return uint(Error.COMPTROLLER_MISMATCH);
+        }
+
+        return uint(Error.NO_ERROR);
+    }
+
+    /*** Liquidity/Liquidation Calculations ***/
+
+    struct AccountLiquidityLocalVars {
+        uint sumCollateral;
+        uint sumBorrowPlusEffects;
+        uint cTokenBalance;
+        uint borrowBalance;
+        uint exchangeRateMantissa;
+        uint oraclePriceMantissa;
+        Exp collateralFactor;
+        Exp exchangeRate;
+        Exp oraclePrice;
+        Exp tokensToDenom;
+    }
+
+    function getAccountLiquidity(address account) public view returns (uint, uint, uint) {
+        (Error err, uint liquidity, uint shortfall) = getHypotheticalAccountLiquidityInternal(account, address(0), 0, 0);
+        return (uint(err), liquidity, shortfall);
+    }
+
+    function getHypotheticalAccountLiquidity(
+        address account,
+        address cTokenModify,
+        uint redeemTokens,
+        uint borrowAmount
+    ) public view returns (uint, uint, uint) {
+        (Error err, uint liquidity, uint shortfall) =
+            getHypotheticalAccountLiquidityInternal(account, cTokenModify, redeemTokens, borrowAmount);
+        return (uint(err), liquidity, shortfall);
+    }
+
+    function getHypotheticalAccountLiquidityInternal(
+        address account,
+        address cTokenModify,
+        uint redeemTokens,
+        uint borrowAmount
+    ) internal view returns (Error, uint, uint) {
+        AccountLiquidityLocalVars memory vars;
+        uint oErr;
+
+        address[] memory assets = accountAssets[account];
+        for (uint i = 0; i < assets.length; i++) {
+            address asset = assets[i];
+
+            (oErr, vars.cTokenBalance, vars.borrowBalance, vars.exchangeRateMantissa) =
+                CTokenInterface(asset).getAccountSnapshot(account);
+            if (oErr != 0) {
+                return (Error.SNAPSHOT_ERROR, 0, 0);
+            }
+            vars.collateralFactor = Exp({mantissa: markets[asset].collateralFactorMantissa});
+            vars.exchangeRate = Exp({mantissa: vars.exchangeRateMantissa});
+
+            vars.oraclePriceMantissa = oracle.getUnderlyingPrice(asset);
+            if (vars.oraclePriceMantissa == 0) {
+                return (Error.PRICE_ERROR, 0, 0);
+            }
+            vars.oraclePrice = Exp({mantissa: vars.oraclePriceMantissa});
+
+            // pre-compute a conversion factor from tokens -> ether (normalized price value)
+            vars.tokensToDenom = mul_(mul_(vars.collateralFactor, vars.exchangeRate), vars.oraclePrice);
+
+            // sumCollateral += tokensToDenom * cTokenBalance
+            vars.sumCollateral = mul_ScalarTruncateAddUInt(vars.tokensToDenom, vars.cTokenBalance, vars.sumCollateral);
+
+            // sumBorrowPlusEffects += oraclePrice * borrowBalance
+            vars.sumBorrowPlusEffects =
+                mul_ScalarTruncateAddUInt(vars.oraclePrice, vars.borrowBalance, vars.sumBorrowPlusEffects);
+
+            if (asset == cTokenModify) {
+                // redeem effect
+                vars.sumBorrowPlusEffects =
+                    mul_ScalarTruncateAddUInt(vars.tokensToDenom, redeemTokens, vars.sumBorrowPlusEffects);
+
+                // borrow effect
+                vars.sumBorrowPlusEffects =
+                    mul_ScalarTruncateAddUInt(vars.oraclePrice, borrowAmount, vars.sumBorrowPlusEffects);
+            }
+        }
+
+        if (vars.sumCollateral > vars.sumBorrowPlusEffects) {
+            return (Error.NO_ERROR, vars.sumCollateral - vars.sumBorrowPlusEffects, 0);
+        } else {
+            return (Error.NO_ERROR, 0, vars.sumBorrowPlusEffects - vars.sumCollateral);
+        }
+    }
+
+    function liquidateCalculateSeizeTokens(
+        address cTokenBorrowed,
+        address cTokenCollateral,
+        uint actualRepayAmount
+    ) external view override returns (uint, uint) {
+        uint priceBorrowedMantissa = oracle.getUnderlyingPrice(cTokenBorrowed);
+        uint priceCollateralMantissa = oracle.getUnderlyingPrice(cTokenCollateral);
+        if (priceBorrowedMantissa == 0 || priceCollateralMantissa == 0) {
+            return (uint(Error.PRICE_ERROR), 0);
+        }
+
+        // seizeTokens = actualRepayAmount * liquidationIncentive * priceBorrowed / (priceCollateral * exchangeRate)
+        uint exchangeRateMantissa = CTokenInterface(cTokenCollateral).exchangeRateStored();
+        Exp memory numerator = mul_(Exp({mantissa: liquidationIncentiveMantissa}), Exp({mantissa: priceBorrowedMantissa}));
+        Exp memory denominator = mul_(Exp({mantissa: priceCollateralMantissa}), Exp({mantissa: exchangeRateMantissa}));
+        Exp memory ratio = div_(numerator, denominator);
+
+        return (uint(Error.NO_ERROR), mul_ScalarTruncate(ratio, actualRepayAmount));
+    }
+
+    /*** Admin Functions ***/
+
+    function _setPriceOracle(PriceOracle newOracle) public returns (uint) {
+        if (msg.sender != admin) {
+            return fail(Error.UNAUTHORIZED, FailureInfo.SET_PRICE_ORACLE_OWNER_CHECK);
+        }
+
+        emit NewPriceOracle(address(oracle), address(newOracle));
+        oracle = newOracle;
+        return uint(Error.NO_ERROR);
+    }
+
+    function _setCloseFactor(uint newCloseFactorMantissa) external returns (uint) {
+        if (msg.sender != admin) {
+            return fail(Error.UNAUTHORIZED, FailureInfo.SET_CLOSE_FACTOR_OWNER_CHECK);
+        }
+        if (newCloseFactorMantissa <= closeFactorMinMantissa || newCloseFactorMantissa > closeFactorMaxMantissa) {
+            return fail(Error.INVALID_CLOSE_FACTOR, FailureInfo.SET_CLOSE_FACTOR_VALIDATION);
+        }
+
+        emit NewCloseFactor(closeFactorMantissa, newCloseFactorMantissa);
+        closeFactorMantissa = newCloseFactorMantissa;
+        return uint(Error.NO_ERROR);
+    }
+
+    function _setCollateralFactor(address cToken, uint newCollateralFactorMantissa) external returns (uint) {
+        if (msg.sender != admin) {
+            return fail(Error.UNAUTHORIZED, FailureInfo.SET_COLLATERAL_FACTOR_OWNER_CHECK);
+        }
+
+        Market storage market = markets[cToken];
+        if (!market.isListed) {
+            return fail(Error.MARKET_NOT_LISTED, FailureInfo.SET_COLLATERAL_FACTOR_NO_EXISTS);
+        }
+        if (newCollateralFactorMantissa > collateralFactorMaxMantissa) {
+            return fail(Error.INVALID_COLLATERAL_FACTOR, FailureInfo.SET_COLLATERAL_FACTOR_VALIDATION);
+        }
+        if (newCollateralFactorMantissa != 0 && oracle.getUnderlyingPrice(cToken) == 0) {
+            return fail(Error.PRICE_ERROR, FailureInfo.SET_COLLATERAL_FACTOR_WITHOUT_PRICE);
+        }
+
+        emit NewCollateralFactor(cToken, market.collateralFactorMantissa, newCollateralFactorMantissa);
+        market.collateralFactorMantissa = newCollateralFactorMantissa;
+        return uint(Error.NO_ERROR);
+    }
+
+    function _setLiquidationIncentive(uint newLiquidationIncentiveMantissa) external returns (uint) {
+        if (msg.sender != admin) {
+            return fail(Error.UNAUTHORIZED, FailureInfo.SET_LIQUIDATION_INCENTIVE_OWNER_CHECK);
+        }
+
+        emit NewLiquidationIncentive(liquidationIncentiveMantissa, newLiquidationIncentiveMantissa);
+        liquidationIncentiveMantissa = newLiquidationIncentiveMantissa;
+        return uint(Error.NO_ERROR);
+    }
+
+    function _supportMarket(address cToken) external returns (uint) {
+        if (msg.sender != admin) {
+            return fail(Error.UNAUTHORIZED, FailureInfo.SUPPORT_MARKET_OWNER_CHECK);
+        }
+        if (markets[cToken].isListed) {
+            return fail(Error.MARKET_ALREADY_LISTED, FailureInfo.SUPPORT_MARKET_EXISTS);
+        }
+
+        // sanity check to make sure its really a CToken
+        require(CTokenInterface(cToken).isCToken(), "not a cToken");
+
+        Market storage market = markets[cToken];
+        market.isListed = true;
+        market.isComped = false;
+        market.collateralFactorMantissa = 0;
+        allMarkets.push(cToken);
+
+        emit MarketListed(cToken);
+        return uint(Error.NO_ERROR);
+    }
+}
@@ -0,0 +1,126 @@
+// SPDX-License-Identifier: BSD-3-Clause
+pragma solidity 0.8.21;
+
+// Error codes of compound-protocol v2, contracts return them instead of reverting for most failures
+
+contract ComptrollerErrorReporter {
+    enum Error {
+        NO_ERROR,
+        UNAUTHORIZED,
+        COMPTROLLER_MISMATCH,
+        INSUFFICIENT_SHORTFALL,
+        INSUFFICIENT_LIQUIDITY,
+        INVALID_CLOSE_FACTOR,
+        INVALID_COLLATERAL_FACTOR,
+        INVALID_LIQUIDATION_INCENTIVE,
+        MARKET_NOT_ENTERED,
+        MARKET_NOT_LISTED,
+        MARKET_ALREADY_LISTED,
+        MATH_ERROR,
+        NONZERO_BORROW_BALANCE,
+        PRICE_ERROR,
+        REJECTION,
+        SNAPSHOT_ERROR,
+        TOO_MANY_ASSETS,
+        TOO_MUCH_REPAY
+    }
+
+    enum FailureInfo {
+        ACCEPT_ADMIN_PENDING_ADMIN_CHECK,
+        ACCEPT_PENDING_IMPLEMENTATION_ADDRESS_CHECK,
+        EXIT_MARKET_BALANCE_OWED,
+        EXIT_MARKET_REJECTION,
+        SET_CLOSE_FACTOR_OWNER_CHECK,
+        SET_CLOSE_FACTOR_VALIDATION,
+        SET_COLLATERAL_FACTOR_OWNER_CHECK,
+        SET_COLLATERAL_FACTOR_NO_EXISTS,
+        SET_COLLATERAL_FACTOR_VALIDATION,
+        SET_COLLATERAL_FACTOR_WITHOUT_PRICE,
+        SET_IMPLEMENTATION_OWNER_CHECK,
+        SET_LIQUIDATION_INCENTIVE_OWNER_CHECK,
+        SET_LIQUIDATION_INCENTIVE_VALIDATION,
+        SET_MAX_ASSETS_OWNER_CHECK,
+        SET_PENDING_ADMIN_OWNER_CHECK,
+        SET_PENDING_IMPLEMENTATION_OWNER_CHECK,
+        SET_PRICE_ORACLE_OWNER_CHECK,
+        SUPPORT_MARKET_EXISTS,
+        SUPPORT_MARKET_OWNER_CHECK
+    }
+
+    event Failure(uint error, uint info, uint detail);
+
+    function fail(Error err, FailureInfo info) internal returns (uint) {
+        emit Failure(uint(err), uint(info), 0);
+        return uint(err);
+    }
+}
+
+contract TokenErrorReporter {
+    enum Error {
+        NO_ERROR,
+        UNAUTHORIZED,
+        BAD_INPUT,
+        COMPTROLLER_REJECTION,
+        COMPTROLLER_CALCULATION_ERROR,
+        INTEREST_RATE_MODEL_ERROR,
+        INVALID_ACCOUNT_PAIR,
+        INVALID_CLOSE_AMOUNT_REQUESTED,
+        INVALID_COLLATERAL_FACTOR,
+        MATH_ERROR,
+        MARKET_NOT_FRESH,
+        MARKET_NOT_LISTED,
+        TOKEN_INSUFFICIENT_ALLOWANCE,
+        TOKEN_INSUFFICIENT_BALANCE,
+        TOKEN_INSUFFICIENT_CASH,
+        TOKEN_TRANSFER_IN_FAILED,
+        TOKEN_TRANSFER_OUT_FAILED
+    }
+
+    enum FailureInfo {
+        ACCEPT_ADMIN_PENDING_ADMIN_CHECK,
+        BORROW_ACCRUE_INTEREST_FAILED,
+        BORROW_CASH_NOT_AVAILABLE,
+        BORROW_FRESHNESS_CHECK,
+        BORROW_COMPTROLLER_REJECTION,
+        LIQUIDATE_ACCRUE_BORROW_INTEREST_FAILED,
+        LIQUIDATE_ACCRUE_COLLATERAL_INTEREST_FAILED,
+        LIQUIDATE_COLLATERAL_FRESHNESS_CHECK,
+        LIQUIDATE_COMPTROLLER_REJECTION,
+        LIQUIDATE_CLOSE_AMOUNT_IS_UINT_MAX,
+        LIQUIDATE_CLOSE_AMOUNT_IS_ZERO,
+        LIQUIDATE_FRESHNESS_CHECK,
+        LIQUIDATE_LIQUIDATOR_IS_BORROWER,
+        LIQUIDATE_REPAY_BORROW_FRESH_FAILED,
+        LIQUIDATE_SEIZE_COMPTROLLER_REJECTION,
+        LIQUIDATE_SEIZE_LIQUIDATOR_IS_BORROWER,
+        MINT_ACCRUE_INTEREST_FAILED,
+        MINT_COMPTROLLER_REJECTION,
+        MINT_FRESHNESS_CHECK,
+        REDEEM_ACCRUE_INTEREST_FAILED,
+        REDEEM_COMPTROLLER_REJECTION,
+        REDEEM_FRESHNESS_CHECK,
+        REDEEM_TRANSFER_OUT_NOT_POSSIBLE,
+        REPAY_BORROW_ACCRUE_INTEREST_FAILED,
+        REPAY_BORROW_COMPTROLLER_REJECTION,
+        REPAY_BORROW_FRESHNESS_CHECK,
+        SET_INTEREST_RATE_MODEL_OWNER_CHECK,
+        SET_RESERVE_FACTOR_ADMIN_CHECK,
+        SET_RESERVE_FACTOR_BOUNDS_CHECK,
+        TRANSFER_COMPTROLLER_REJECTION,
+        TRANSFER_NOT_ALLOWED,
+        TRANSFER_NOT_ENOUGH,
+        TRANSFER_TOO_MUCH
+    }
+
+    event Failure(uint error, uint info, uint detail);
+
+    function fail(Error err, FailureInfo info) internal returns (uint) {
+        emit Failure(uint(err), uint(info), 0);
+        return uint(err);
+    }
+
+    function failOpaque(Error err, FailureInfo info, uint opaqueError) internal returns (uint) {
+        emit Failure(uint(err), uint(info), opaqueError);
+        return uint(err);
+    }
+}
@@ -0,0 +1,43 @@
+// SPDX-License-Identifier: BSD-3-Clause
+pragma solidity 0.8.21;
+
+// Fixed point math on 18 decimal mantissas, like compound-protocol ExponentialNoError
+contract ExponentialNoError {
+    uint constant expScale = 1e18;
+
+    struct Exp {
+        uint mantissa;
+    }
+
+    function truncate(Exp memory exp) internal pure returns (uint) {
+        return exp.mantissa / expScale;
+    }
+
+    function mul_ScalarTruncate(Exp memory a, uint scalar) internal pure returns (uint) {
+        return truncate(mul_(a, scalar));
+    }
+
+    function mul_ScalarTruncateAddUInt(Exp memory a, uint scalar, uint addend) internal pure returns (uint) {
+        return truncate(mul_(a, scalar)) + addend;
+    }
+
+    function mul_(Exp memory a, Exp memory b) internal pure returns (Exp memory) {
+        return Exp({mantissa: (a.mantissa * b.mantissa) / expScale});
+    }
+
+    function mul_(Exp memory a, uint b) internal pure returns (Exp memory) {
+        return Exp({mantissa: a.mantissa * b});
+    }
+
+    function mul_(uint a, Exp memory b) internal pure returns (uint) {
+        return (a * b.mantissa) / expScale;
+    }
+
+    function div_(Exp memory a, Exp memory b) internal pure returns (Exp memory) {
+        return Exp({mantissa: (a.mantissa * expScale) / b.mantissa});
+    }
+
+    function div_(uint a, Exp memory b) internal pure returns (uint) {
+        return (a * expScale) / b.mantissa;
+    }
+}
@@ -0,0 +1,55 @@
+// SPDX-License-Identifier: BSD-3-Clause
+pragma solidity 0.8.21;
+
+// FaucetToken is an ERC20 anyone can mint, like compound-protocol FaucetToken
+contract FaucetToken {
+    string public name;
+    string public symbol;
+    uint8 public decimals;
+    uint256 public totalSupply;
+    mapping(address => uint256) public balanceOf;
+    mapping(address => mapping(address => uint256)) public allowance;
+
+    event Transfer(address indexed from, address indexed to, uint256 amount);
+    event Approval(address indexed owner, address indexed spender, uint256 amount);
+
+    constructor(string memory name_, string memory symbol_, uint8 decimals_) {
+        name = name_;
+        symbol = symbol_;
+        decimals = decimals_;
+    }
+
+    function allocateTo(address owner, uint256 value) external {
+        balanceOf[owner] += value;
+        totalSupply += value;
+        emit Transfer(address(this), owner, value);
+    }
+
+    function approve(address spender, uint256 amount) external returns (bool) {
+        allowance[msg.sender][spender] = amount;
+        emit Approval(msg.sender, spender, amount);
+        return true;
+    }
+
+    function transfer(address dst, uint256 amount) external returns (bool) {
+        _transfer(msg.sender, dst, amount);
+        return true;
+    }
+
+    function transferFrom(address src, address dst, uint256 amount) external returns (bool) {
+        uint256 allowed = allowance[src][msg.sender];
+        require(allowed >= amount, "Insufficient allowance");
+        if (allowed != type(uint256).max) {
+            allowance[src][msg.sender] = allowed - amount;
+        }
+        _transfer(src, dst, amount);
+        return true;
+    }
+
+    function _transfer(address src, address dst, uint256 amount) internal {
+        require(balanceOf[src] >= amount, "Insufficient balance");
+        balanceOf[src] -= amount;
+        balanceOf[dst] += amount;
+        emit Transfer(src, dst, amount);
+    }
+}
@@ -0,0 +1,37 @@
+// SPDX-License-Identifier: BSD-3-Clause
+pragma solidity 0.8.21;
+
+import "./Interfaces.sol";
+
+// FixedRateModel charges the same borrow rate per block whatever the utilization, the admin can change it
+contract FixedRateModel is InterestRateModel {
+    address public admin;
+    uint public borrowRate;
+
+    constructor(uint borrowRate_) {
+        admin = msg.sender;
+        borrowRate = borrowRate_;
+    }
+
+    function isInterestRateModel() external pure override returns (bool) {
+        return true;
+    }
+
+    function setBorrowRate(uint borrowRate_) external {
+        require(msg.sender == admin, "only admin");
+        borrowRate = borrowRate_;
+    }
+
+    function getBorrowRate(uint, uint, uint) external view override returns (uint) {
+        return borrowRate;
+    }
+
+    function getSupplyRate(uint cash, uint borrows, uint reserves, uint reserveFactorMantissa) external view override returns (uint) {
+        uint total = cash + borrows - reserves;
+        if (total == 0) {
+            return 0;
+        }
+        uint rateToPool = (borrowRate * (1e18 - reserveFactorMantissa)) / 1e18;
+        return (((borrows * 1e18) / total) * rateToPool) / 1e18;
+    }
+}
@@ -0,0 +1,59 @@
+// SPDX-License-Identifier: BSD-3-Clause
+pragma solidity 0.8.21;
+
+interface EIP20Interface {
+    function balanceOf(address owner) external view returns (uint256);
+    function transfer(address dst, uint256 amount) external returns (bool);
+    function transferFrom(address src, address dst, uint256 amount) external returns (bool);
+}
+
+interface ComptrollerInterface {
+    function isComptroller() external view returns (bool);
+
+    function mintAllowed(address cToken, address minter, uint mintAmount) external returns (uint);
+    function borrowAllowed(address cToken, address borrower, uint borrowAmount) external returns (uint);
+    function repayBorrowAllowed(address cToken, address payer, address borrower, uint repayAmount) external returns (uint);
+    function liquidateBorrowAllowed(
+        address cTokenBorrowed,
+        address cTokenCollateral,
+        address liquidator,
+        address borrower,
+        uint repayAmount
+    ) external returns (uint);
+    function seizeAllowed(
+        address cTokenCollateral,
+        address cTokenBorrowed,
+        address liquidator,
+        address borrower,
+        uint seizeTokens
+    ) external returns (uint);
+
+    function liquidateCalculateSeizeTokens(
+        address cTokenBorrowed,
+        address cTokenCollateral,
+        uint repayAmount
+    ) external view returns (uint, uint);
+}
+
+interface CTokenInterface {
+    function isCToken() external view returns (bool);
+    function comptroller() external view returns (address);
+    function underlying() external view returns (address);
+    function balanceOf(address owner) external view returns (uint);
+    function borrowBalanceStored(address account) external view returns (uint);
+    function exchangeRateStored() external view returns (uint);
+    function getAccountSnapshot(address account) external view returns (uint, uint, uint, uint);
+    function accrueInterest() external returns (uint);
+    function seize(address liquidator, address borrower, uint seizeTokens) external returns (uint);
+}
+
+interface PriceOracle {
+    function isPriceOracle() external view returns (bool);
+    function getUnderlyingPrice(address cToken) external view returns (uint);
+}
+
+interface InterestRateModel {
+    function isInterestRateModel() external view returns (bool);
+    function getBorrowRate(uint cash, uint borrows, uint reserves) external view returns (uint);
+    function getSupplyRate(uint cash, uint borrows, uint reserves, uint reserveFactorMantissa) external view returns (uint);
+}
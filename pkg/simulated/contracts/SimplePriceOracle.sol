@@ -0,0 +1,30 @@
+// SPDX-License-Identifier: BSD-3-Clause
+pragma solidity 0.8.21;
+
+import "./Interfaces.sol";
+
+// SimplePriceOracle keeps prices set by anyone by underlying, like compound-protocol SimplePriceOracle.
+// Prices are USD scaled by 1e(36 - underlying decimals).
+contract SimplePriceOracle is PriceOracle {
+    mapping(address => uint) prices;
+
+    event PricePosted(address asset, uint previousPriceMantissa, uint requestedPriceMantissa, uint newPriceMantissa);
+
+    function isPriceOracle() external pure override returns (bool) {
+        return true;
+    }
+
+    function getUnderlyingPrice(address cToken) public view override returns (uint) {
+        return prices[CTokenInterface(cToken).underlying()];
+    }
+
+    function setUnderlyingPrice(address cToken, uint underlyingPriceMantissa) public {
+        address asset = CTokenInterface(cToken).underlying();
+        emit PricePosted(asset, prices[asset], underlyingPriceMantissa, underlyingPriceMantissa);
+        prices[asset] = underlyingPriceMantissa;
+    }
+
+    function assetPrices(address asset) external view returns (uint) {
+        return prices[asset];
+    }
+}
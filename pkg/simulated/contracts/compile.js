@@ -0,0 +1,52 @@
+// compile.js compiles the simulated Compound deployment with soljson and writes ../bytecode.go,
+// run through go generate: SOLJSON=/path/to/soljson-v0.8.21+commit.d9974bed.js go generate ./pkg/simulated
+const fs = require('fs');
+const path = require('path');
+
+const soljson = require(process.env.SOLJSON);
+const compile = soljson.cwrap('solidity_compile', 'string', ['string', 'number', 'number']);
+
+const sources = {};
+for (const file of fs.readdirSync(__dirname).filter((f) => f.endsWith('.sol')).sort()) {
+  sources[file] = { content: fs.readFileSync(path.join(__dirname, file), 'utf8') };
+}
+
+const input = {
+  language: 'Solidity',
+  sources,
+  settings: {
+    // the simulated backend does not run Shanghai, no PUSH0
+    evmVersion: 'paris',
+    optimizer: { enabled: true, runs: 200 },
+    outputSelection: { '*': { '*': ['abi', 'evm.bytecode.object', 'evm.deployedBytecode.object'] } },
+  },
+};
+
+const output = JSON.parse(compile(JSON.stringify(input), 0, 0));
+const errors = (output.errors || []).filter((e) => e.severity === 'error');
+if (errors.length > 0) {
+  errors.forEach((e) => console.error(e.formattedMessage));
+  process.exit(1);
+}
+
+// deployed contracts, Multicall3 is placed in the genesis at its canonical address
+const contracts = [
+  ['FaucetToken.sol', 'FaucetToken', 'bytecode'],
+  ['SimplePriceOracle.sol', 'SimplePriceOracle', 'bytecode'],
+  ['FixedRateModel.sol', 'FixedRateModel', 'bytecode'],
+  ['Comptroller.sol', 'Comptroller', 'bytecode'],
+  ['CErc20.sol', 'CErc20', 'bytecode'],
+  ['Multicall3.sol', 'Multicall3', 'deployedBytecode'],
+];
+
+const version = soljson.cwrap('solidity_version', 'string', [])();
+let out = '// Code generated by contracts/compile.js with solc ' + version + '. DO NOT EDIT.\n\npackage simulated\n\n';
+for (const [file, name, kind] of contracts) {
+  const contract = output.contracts[file][name];
+  const suffix = kind === 'bytecode' ? 'Bin' : 'Runtime';
+  out += '// ' + name + 'ABI is the ABI of contracts/' + file + ' ' + name + '\n';
+  out += 'const ' + name + 'ABI = ' + JSON.stringify(JSON.stringify(contract.abi)) + '\n\n';
+  out += '// ' + name + suffix + ' is the ' + (kind === 'bytecode' ? 'creation' : 'runtime') + ' code of ' + name + '\n';
+  out += 'const ' + name + suffix + ' = "0x' + contract.evm[kind].object + '"\n\n';
+}
+fs.writeFileSync(path.join(__dirname, '..', 'bytecode.go'), out.trimEnd() + '\n');
@@ -0,0 +1,389 @@
+package simulated
+
+//go:generate node contracts/compile.js
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/config"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/contracts"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/multicall"
+)
+
+// MulticallAddress is the canonical Multicall3 address, Multicall3 is placed there in the genesis
+var MulticallAddress = multicall.Address
+
+// Harness represents a simulated chain with a minimal Compound deployment compiled from contracts/:
+// a comptroller, a price oracle, cDAI and cUSDC markets over DAI and USDC faucet tokens,
+// a supplier providing USDC cash, a borrower and a liquidator holding USDC.
+// The admin deployed the contracts and sets prices and rates.
+type Harness struct {
+	Backend *Backend
+
+	AdminKey      *ecdsa.PrivateKey
+	SupplierKey   *ecdsa.PrivateKey
+	BorrowerKey   *ecdsa.PrivateKey
+	LiquidatorKey *ecdsa.PrivateKey
+
+	Comptroller common.Address
+	Oracle      common.Address
+	CDAI        common.Address
+	CUSDC       common.Address
+	DAI         common.Address
+	USDC        common.Address
+
+	// rateModels holds the interest rate model of each market
+	rateModels map[common.Address]common.Address
+}
+
+// NewHarness creates new harness, the chain commits a block after every transaction
+func NewHarness() (*Harness, error) {
+	h := &Harness{rateModels: map[common.Address]common.Address{}}
+	alloc := core.GenesisAlloc{}
+	for _, key := range []**ecdsa.PrivateKey{&h.AdminKey, &h.SupplierKey, &h.BorrowerKey, &h.LiquidatorKey} {
+		k, err := crypto.GenerateKey()
+		if err != nil {
+			return nil, errors.New("Generating key: " + err.Error())
+		}
+		*key = k
+		alloc[crypto.PubkeyToAddress(k.PublicKey)] = core.GenesisAccount{Balance: new(big.Int).Mul(big.NewInt(1000), expScale)}
+	}
+
+	h.Backend = newBackend(alloc, true)
+
+	if err := h.deployCompound(); err != nil {
+		h.Backend.Close()
+		return nil, err
+	}
+
+	// market cash
+	if err := h.Supply(h.SupplierKey, h.CUSDC, units(1000000, 6)); err != nil {
+		h.Backend.Close()
+		return nil, errors.New("Supplying USDC: " + err.Error())
+	}
+
+	// liquidator repays USDC borrows
+	if err := h.Faucet(h.USDC, h.Liquidator(), units(100000, 6)); err != nil {
+		h.Backend.Close()
+		return nil, errors.New("Funding liquidator: " + err.Error())
+	}
+	if err := h.Approve(h.LiquidatorKey, h.USDC, h.CUSDC, units(100000, 6)); err != nil {
+		h.Backend.Close()
+		return nil, errors.New("Approving USDC: " + err.Error())
+	}
+
+	return h, nil
+}
+
+// deployCompound deploys and configures the Compound contracts: close factor 50%, incentive 8%,
+// collateral factors 75%, no interest, both tokens at 1 USD
+func (h *Harness) deployCompound() error {
+	var err error
+	if h.DAI, err = h.deploy(faucetTokenABI, FaucetTokenBin, "DAI", "DAI", uint8(18)); err != nil {
+		return errors.New("Deploying DAI: " + err.Error())
+	}
+	if h.USDC, err = h.deploy(faucetTokenABI, FaucetTokenBin, "USDC", "USDC", uint8(6)); err != nil {
+		return errors.New("Deploying USDC: " + err.Error())
+	}
+	if h.Oracle, err = h.deploy(oracleABI, SimplePriceOracleBin); err != nil {
+		return errors.New("Deploying oracle: " + err.Error())
+	}
+	if h.Comptroller, err = h.deploy(comptrollerABI, ComptrollerBin); err != nil {
+		return errors.New("Deploying comptroller: " + err.Error())
+	}
+
+	for _, call := range []struct {
+		method string
+		args   []interface{}
+	}{
+		{"_setPriceOracle", []interface{}{h.Oracle}},
+		{"_setCloseFactor", []interface{}{fraction(50, 100)}},
+		{"_setLiquidationIncentive", []interface{}{fraction(108, 100)}},
+	} {
+		if err := h.transact(h.AdminKey, h.Comptroller, comptrollerABI, call.method, call.args...); err != nil {
+			return fmt.Errorf("Configuring comptroller %s: %v", call.method, err)
+		}
+	}
+
+	for _, market := range []struct {
+		ctoken     *common.Address
+		underlying common.Address
+		symbol     string
+		decimals   int64
+	}{
+		{&h.CDAI, h.DAI, "DAI", 18},
+		{&h.CUSDC, h.USDC, "USDC", 6},
+	} {
+		rateModel, err := h.deploy(rateModelABI, FixedRateModelBin, new(big.Int))
+		if err != nil {
+			return errors.New("Deploying rate model: " + err.Error())
+		}
+		// initial Compound exchange rate, 0.02 underlying per cToken
+		exchangeRate := new(big.Int).Mul(big.NewInt(2), pow10(16+market.decimals-8))
+		ctoken, err := h.deploy(cErc20ABI, CErc20Bin, market.underlying, h.Comptroller, rateModel, exchangeRate,
+			"Compound "+market.symbol, "c"+market.symbol, uint8(8))
+		if err != nil {
+			return fmt.Errorf("Deploying c%s: %v", market.symbol, err)
+		}
+		*market.ctoken = ctoken
+		h.rateModels[ctoken] = rateModel
+
+		if err := h.transact(h.AdminKey, h.Comptroller, comptrollerABI, "_supportMarket", ctoken); err != nil {
+			return fmt.Errorf("Listing c%s: %v", market.symbol, err)
+		}
+		if err := h.SetPrice(ctoken, 1); err != nil {
+			return fmt.Errorf("Setting c%s price: %v", market.symbol, err)
+		}
+		if err := h.transact(h.AdminKey, h.Comptroller, comptrollerABI, "_setCollateralFactor", ctoken, fraction(75, 100)); err != nil {
+			return fmt.Errorf("Setting c%s collateral factor: %v", market.symbol, err)
+		}
+	}
+
+	return nil
+}
+
+// Supplier returns supplier address
+func (h *Harness) Supplier() common.Address {
+	return crypto.PubkeyToAddress(h.SupplierKey.PublicKey)
+}
+
+// Borrower returns borrower address
+func (h *Harness) Borrower() common.Address {
+	return crypto.PubkeyToAddress(h.BorrowerKey.PublicKey)
+}
+
+// Liquidator returns liquidator address
+func (h *Harness) Liquidator() common.Address {
+	return crypto.PubkeyToAddress(h.LiquidatorKey.PublicKey)
+}
+
+// Faucet mints amount of token to account
+func (h *Harness) Faucet(token, account common.Address, amount *big.Int) error {
+	return h.transact(h.AdminKey, token, faucetTokenABI, "allocateTo", account, amount)
+}
+
+// SetPrice sets oracle price of market underlying in USD
+func (h *Harness) SetPrice(market common.Address, usd float64) error {
+	ctoken, err := contracts.NewCTokenCaller(market, h.Backend)
+	if err != nil {
+		return err
+	}
+	underlying, err := ctoken.Underlying(&bind.CallOpts{})
+	if err != nil {
+		return err
+	}
+	token, err := contracts.NewErc20Caller(underlying, h.Backend)
+	if err != nil {
+		return err
+	}
+	decimals, err := token.Decimals(&bind.CallOpts{})
+	if err != nil {
+		return err
+	}
+
+	price, _ := new(big.Float).Mul(big.NewFloat(usd), new(big.Float).SetInt(pow10(36-int64(decimals)))).Int(nil)
+	return h.SetPriceMantissa(market, price)
+}
+
+// SetPriceMantissa sets oracle price of market underlying, scaled by 1e(36 - underlying decimals)
+func (h *Harness) SetPriceMantissa(market common.Address, price *big.Int) error {
+	return h.transact(h.AdminKey, h.Oracle, oracleABI, "setUnderlyingPrice", market, price)
+}
+
+// SetCollateralFactor sets comptroller collateral factor of market, a mantissa up to 0.9
+func (h *Harness) SetCollateralFactor(market common.Address, factor *big.Int) error {
+	return h.transact(h.AdminKey, h.Comptroller, comptrollerABI, "_setCollateralFactor", market, factor)
+}
+
+// SetBorrowRate accrues market interest and sets its borrow rate per block, a mantissa
+func (h *Harness) SetBorrowRate(market common.Address, ratePerBlock *big.Int) error {
+	if err := h.AccrueInterest(market); err != nil {
+		return err
+	}
+	return h.transact(h.AdminKey, h.rateModels[market], rateModelABI, "setBorrowRate", ratePerBlock)
+}
+
+// AccrueInterest accrues market interest in a new block
+func (h *Harness) AccrueInterest(market common.Address) error {
+	return h.transact(h.AdminKey, market, cErc20ABI, "accrueInterest")
+}
+
+// Mine commits blocks empty blocks
+func (h *Harness) Mine(blocks int) {
+	for i := 0; i < blocks; i++ {
+		h.Backend.Commit()
+	}
+}
+
+// Approve approves spender to transfer amount of token held by key
+func (h *Harness) Approve(key *ecdsa.PrivateKey, token, spender common.Address, amount *big.Int) error {
+	return h.transact(key, token, faucetTokenABI, "approve", spender, amount)
+}
+
+// Supply mints amount of market underlying to key account and mints cTokens with it
+func (h *Harness) Supply(key *ecdsa.PrivateKey, market common.Address, amount *big.Int) error {
+	account := crypto.PubkeyToAddress(key.PublicKey)
+	ctoken, err := contracts.NewCTokenCaller(market, h.Backend)
+	if err != nil {
+		return err
+	}
+	underlying, err := ctoken.Underlying(&bind.CallOpts{})
+	if err != nil {
+		return err
+	}
+
+	if err := h.Faucet(underlying, account, amount); err != nil {
+		return err
+	}
+	if err := h.Approve(key, underlying, market, amount); err != nil {
+		return err
+	}
+	return h.transact(key, market, cErc20ABI, "mint", amount)
+}
+
+// Borrow enters key account into collateral market and borrows amount of market underlying
+func (h *Harness) Borrow(key *ecdsa.PrivateKey, collateral, market common.Address, amount *big.Int) error {
+	if err := h.transact(key, h.Comptroller, comptrollerABI, "enterMarkets", []common.Address{collateral}); err != nil {
+		return err
+	}
+	return h.transact(key, market, cErc20ABI, "borrow", amount)
+}
+
+// OpenBorrow supplies 1000 DAI as borrower collateral and borrows 700 USDC against it,
+// the account gets a 25 USD shortfall once DAI drops to 0.90 USD
+func (h *Harness) OpenBorrow() error {
+	if err := h.Supply(h.BorrowerKey, h.CDAI, units(1000, 18)); err != nil {
+		return errors.New("Supplying DAI: " + err.Error())
+	}
+	if err := h.Borrow(h.BorrowerKey, h.CDAI, h.CUSDC, units(700, 6)); err != nil {
+		return errors.New("Borrowing USDC: " + err.Error())
+	}
+	return nil
+}
+
+// Shortfall returns account shortfall in USD mantissa
+func (h *Harness) Shortfall(account common.Address) (*big.Int, error) {
+	comptroller, err := contracts.NewComptrollerCoreCaller(h.Comptroller, h.Backend)
+	if err != nil {
+		return nil, err
+	}
+	_, _, shortfall, err := comptroller.GetAccountLiquidity(&bind.CallOpts{}, account)
+	return shortfall, err
+}
+
+// Config creates bot config liquidating with the liquidator account in both markets
+func (h *Harness) Config() *Config {
+	rpcURL, _ := url.Parse("http://127.0.0.1:8545")
+	return &Config{
+		Key:              h.LiquidatorKey,
+		Interval:         100 * time.Millisecond,
+		RPC:              rpcURL,
+		Comptroller:      h.Comptroller,
+		CUSDC:            h.CUSDC,
+		MarketList:       []config.Market{{Name: "cDAI", Address: h.CDAI}, {Name: "cUSDC", Address: h.CUSDC}},
+		GasSettings:      config.Gas{PriceMultiplier: 1},
+		ScanSettings:     config.Scan{Concurrency: 4, BatchSize: 100, PositionMaxAge: 100, LookaheadBlocks: 20, WatchBand: 0.1, FullScanInterval: time.Minute},
+		ChainStartChecks: true,
+	}
+}
+
+func (h *Harness) transactOpts(key *ecdsa.PrivateKey) *bind.TransactOpts {
+	chainID, _ := h.Backend.ChainID(context.Background())
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, chainID)
+	return opts
+}
+
+// deploy deploys the contract with the admin key
+func (h *Harness) deploy(parsed *abi.ABI, bin string, params ...interface{}) (common.Address, error) {
+	address, tx, _, err := bind.DeployContract(h.transactOpts(h.AdminKey), *parsed, common.FromHex(bin), h.Backend, params...)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return address, h.checkReceipt(tx)
+}
+
+// transact sends method of the contract at address from the key account. Compound methods return
+// error codes instead of reverting, the code is checked with a call first.
+func (h *Harness) transact(key *ecdsa.PrivateKey, address common.Address, parsed *abi.ABI, method string, args ...interface{}) error {
+	contract := bind.NewBoundContract(address, *parsed, h.Backend, h.Backend, h.Backend)
+
+	if outputs := parsed.Methods[method].Outputs; len(outputs) == 1 && outputs[0].Type.T == abi.UintTy {
+		var out []interface{}
+		opts := &bind.CallOpts{From: crypto.PubkeyToAddress(key.PublicKey)}
+		if err := contract.Call(opts, &out, method, args...); err != nil {
+			return err
+		}
+		if code := out[0].(*big.Int); code.Sign() != 0 {
+			return fmt.Errorf("%s returned error code %s", method, code)
+		}
+	}
+
+	tx, err := contract.Transact(h.transactOpts(key), method, args...)
+	if err != nil {
+		return err
+	}
+	return h.checkReceipt(tx)
+}
+
+func (h *Harness) checkReceipt(tx *types.Transaction) error {
+	receipt, err := h.Backend.TransactionReceipt(context.Background(), tx.Hash())
+	if err != nil {
+		return err
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return errors.New("transaction " + tx.Hash().Hex() + " failed")
+	}
+	return nil
+}
+
+// units returns amount scaled to token decimals
+func units(amount int64, decimals int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(amount), pow10(decimals))
+}
+
+// fraction returns numerator / denominator as mantissa
+func fraction(numerator, denominator int64) *big.Int {
+	mantissa := new(big.Int).Mul(big.NewInt(numerator), expScale)
+	return mantissa.Div(mantissa, big.NewInt(denominator))
+}
+
+func pow10(n int64) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(n), nil)
+}
+
+// mulExp returns a * b / 1e18
+func mulExp(a, b *big.Int) *big.Int {
+	product := new(big.Int).Mul(a, b)
+	return product.Div(product, expScale)
+}
+
+var (
+	expScale = big.NewInt(1e18)
+
+	faucetTokenABI = mustABI(FaucetTokenABI)
+	oracleABI      = mustABI(SimplePriceOracleABI)
+	rateModelABI   = mustABI(FixedRateModelABI)
+	comptrollerABI = mustABI(ComptrollerABI)
+	cErc20ABI      = mustABI(CErc20ABI)
+)
+
+func mustABI(definition string) *abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(definition))
+	if err != nil {
+		panic(err)
+	}
+	return &parsed
+}
@@ -0,0 +1,247 @@
+package simulated
+
+import (
+	"context"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/contracts"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/liqbot"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/subgraph"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/subgraph/subgraphtest"
+)
+
+// Fixture returns the subgraph view of the chain at the head block, it makes the harness a subgraphtest.Source.
+// Accounts are indexed from the market events like the subgraph does. Values the subgraph reports in ETH
+// are in USD, the deployment has no ETH market.
+func (h *Harness) Fixture(ctx context.Context) (*subgraphtest.Fixture, error) {
+	head, err := h.Backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	fixture := &subgraphtest.Fixture{}
+	fixture.Meta.Block.Number = head.Number.Uint64()
+	fixture.Meta.Block.Timestamp = int64(head.Time)
+
+	opts := &bind.CallOpts{Context: ctx, BlockNumber: head.Number}
+	idx, err := h.index(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	fixture.Markets = idx.markets
+	if fixture.Accounts, err = h.borrowers(opts, idx); err != nil {
+		return nil, err
+	}
+
+	return fixture, nil
+}
+
+// AccountSource returns borrowers the way the subgraph would, with their current health
+func (h *Harness) AccountSource() liqbot.AccountSource {
+	return &accountSource{harness: h}
+}
+
+type accountSource struct {
+	harness *Harness
+}
+
+func (s *accountSource) GetAccounts(ctx context.Context) ([]subgraph.Account, error) {
+	fixture, err := s.harness.Fixture(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return fixture.Accounts, nil
+}
+
+// marketIndex holds the markets as the subgraph indexes them and the accounts that ever held a position
+type marketIndex struct {
+	addresses []common.Address
+	markets   []subgraph.Market
+	decimals  map[common.Address]int64
+	prices    map[common.Address]*big.Int
+	factors   map[common.Address]*big.Int
+	accounts  map[common.Address]bool
+}
+
+func (h *Harness) index(ctx context.Context, opts *bind.CallOpts) (*marketIndex, error) {
+	comptroller, err := contracts.NewComptrollerCoreCaller(h.Comptroller, h.Backend)
+	if err != nil {
+		return nil, err
+	}
+	oracle, err := contracts.NewPriceOracleCaller(h.Oracle, h.Backend)
+	if err != nil {
+		return nil, err
+	}
+	addresses, err := comptroller.GetAllMarkets(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	idx := &marketIndex{
+		addresses: addresses,
+		decimals:  map[common.Address]int64{},
+		prices:    map[common.Address]*big.Int{},
+		factors:   map[common.Address]*big.Int{},
+		accounts:  map[common.Address]bool{},
+	}
+	for _, address := range addresses {
+		ctoken, err := contracts.NewCTokenCaller(address, h.Backend)
+		if err != nil {
+			return nil, err
+		}
+		symbol, err := ctoken.Symbol(opts)
+		if err != nil {
+			return nil, err
+		}
+		underlyingAddress, err := ctoken.Underlying(opts)
+		if err != nil {
+			return nil, err
+		}
+		exchangeRate, err := ctoken.ExchangeRateStored(opts)
+		if err != nil {
+			return nil, err
+		}
+		underlying, err := contracts.NewErc20Caller(underlyingAddress, h.Backend)
+		if err != nil {
+			return nil, err
+		}
+		underlyingSymbol, err := underlying.Symbol(opts)
+		if err != nil {
+			return nil, err
+		}
+		decimals, err := underlying.Decimals(opts)
+		if err != nil {
+			return nil, err
+		}
+		price, err := oracle.GetUnderlyingPrice(opts, address)
+		if err != nil {
+			return nil, err
+		}
+		market, err := comptroller.Markets(opts, address)
+		if err != nil {
+			return nil, err
+		}
+
+		d := int64(decimals)
+		idx.decimals[address] = d
+		idx.prices[address] = price
+		idx.factors[address] = market.CollateralFactorMantissa
+		idx.markets = append(idx.markets, subgraph.Market{
+			Id:                 strings.ToLower(address.Hex()),
+			Symbol:             symbol,
+			UnderlyingAddress:  strings.ToLower(underlyingAddress.Hex()),
+			UnderlyingSymbol:   underlyingSymbol,
+			UnderlyingDecimals: int(d),
+			UnderlyingPriceUSD: decimal(price, 36-d),
+			CollateralFactor:   decimal(market.CollateralFactorMantissa, 18),
+			ExchangeRate:       decimal(exchangeRate, 18+d-8),
+		})
+	}
+
+	// minters, borrowers and cToken recipients
+	ctokenABI, err := contracts.CTokenMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	mint, borrow, transfer := ctokenABI.Events["Mint"], ctokenABI.Events["Borrow"], ctokenABI.Events["Transfer"]
+	logs, err := h.Backend.FilterLogs(ctx, ethereum.FilterQuery{
+		ToBlock:   opts.BlockNumber,
+		Addresses: addresses,
+		Topics:    [][]common.Hash{{mint.ID, borrow.ID, transfer.ID}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, log := range logs {
+		switch log.Topics[0] {
+		case mint.ID, borrow.ID:
+			// minter and borrower come first
+			idx.accounts[common.BytesToAddress(log.Data[:32])] = true
+		case transfer.ID:
+			idx.accounts[common.BytesToAddress(log.Topics[2].Bytes())] = true
+		}
+	}
+	for _, address := range addresses {
+		delete(idx.accounts, address)
+	}
+
+	return idx, nil
+}
+
+// borrowers returns accounts with borrows, health is risk adjusted collateral over borrows
+func (h *Harness) borrowers(opts *bind.CallOpts, idx *marketIndex) ([]subgraph.Account, error) {
+	comptroller, err := contracts.NewComptrollerCoreCaller(h.Comptroller, h.Backend)
+	if err != nil {
+		return nil, err
+	}
+
+	var accounts []subgraph.Account
+	for account := range idx.accounts {
+		assetsIn, err := comptroller.GetAssetsIn(opts, account)
+		if err != nil {
+			return nil, err
+		}
+		entered := map[common.Address]bool{}
+		for _, asset := range assetsIn {
+			entered[asset] = true
+		}
+
+		collateral, borrows := new(big.Int), new(big.Int)
+		var tokens []subgraph.AccountToken
+		for i, address := range idx.addresses {
+			ctoken, err := contracts.NewCTokenCaller(address, h.Backend)
+			if err != nil {
+				return nil, err
+			}
+			_, balance, borrow, exchangeRate, err := ctoken.GetAccountSnapshot(opts, account)
+			if err != nil {
+				return nil, err
+			}
+			if balance.Sign() == 0 && borrow.Sign() == 0 {
+				continue
+			}
+
+			price := idx.prices[address]
+			if entered[address] {
+				tokensToDenom := mulExp(mulExp(idx.factors[address], exchangeRate), price)
+				collateral.Add(collateral, mulExp(tokensToDenom, balance))
+			}
+			borrows.Add(borrows, mulExp(price, borrow))
+			tokens = append(tokens, subgraph.AccountToken{
+				Id:                  strings.ToLower(address.Hex() + "-" + account.Hex()),
+				Symbol:              idx.markets[i].Symbol,
+				EnteredMarket:       entered[address],
+				CTokenBalance:       decimal(balance, 8),
+				StoredBorrowBalance: decimal(borrow, idx.decimals[address]),
+			})
+		}
+		if borrows.Sign() == 0 {
+			continue
+		}
+
+		accounts = append(accounts, subgraph.Account{
+			Id:                    strings.ToLower(account.Hex()),
+			TotalBorrowValueInEth: decimal(borrows, 18),
+			Health:                new(big.Rat).SetFrac(collateral, borrows).FloatString(18),
+			Tokens:                tokens,
+		})
+	}
+
+	sort.Slice(accounts, func(i, j int) bool {
+		return accounts[i].Id < accounts[j].Id
+	})
+	return accounts, nil
+}
+
+// decimal formats mantissa scaled by 10^decimals the way the subgraph formats BigDecimal
+func decimal(mantissa *big.Int, decimals int64) string {
+	if mantissa == nil {
+		mantissa = new(big.Int)
+	}
+	return new(big.Rat).SetFrac(mantissa, pow10(decimals)).FloatString(int(decimals))
+}
@@ -0,0 +1,101 @@
+package store
+
+import (
+	"encoding/json"
+	"sort"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// LedgerEntry represents realized PnL of mined liquidations
+type LedgerEntry struct {
+	// Key is the day (YYYY-MM-DD, UTC) or the collateral market name
+	Key          string  `json:"key"`
+	Liquidations int     `json:"liquidations"`
+	Reverted     int     `json:"reverted"`
+	RepayUSD     float64 `json:"repay_usd"`
+	SeizedUSD    float64 `json:"seized_usd"`
+	GasUSD       float64 `json:"gas_usd"`
+	ProfitUSD    float64 `json:"profit_usd"`
+}
+
+// Ledger represents realized PnL per day and per collateral market, where the profit is seized
+type Ledger struct {
+	Total    LedgerEntry   `json:"total"`
+	ByDay    []LedgerEntry `json:"by_day"`
+	ByMarket []LedgerEntry `json:"by_market"`
+}
+
+// Ledger sums outcomes of mined liquidations, reverted ones count their gas as a loss
+func (s *Store) Ledger() (*Ledger, error) {
+	days := map[string]*LedgerEntry{}
+	markets := map[string]*LedgerEntry{}
+	ledger := &Ledger{Total: LedgerEntry{Key: "total"}}
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		attempts := tx.Bucket(attemptsBucket)
+		return tx.Bucket(outcomesBucket).ForEach(func(k, v []byte) error {
+			o := new(Outcome)
+			if err := json.Unmarshal(v, o); err != nil {
+				return err
+			}
+			if o.Status != StatusSucceeded && o.Status != StatusReverted {
+				return nil
+			}
+
+			market := "unknown"
+			if data := attempts.Get(k); data != nil {
+				a := new(Attempt)
+				if err := json.Unmarshal(data, a); err != nil {
+					return err
+				}
+				market = a.CollateralMarket
+			}
+
+			day := o.BlockTime.UTC().Format("2006-01-02")
+			for _, entry := range []*LedgerEntry{&ledger.Total, entry(days, day), entry(markets, market)} {
+				entry.add(o)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ledger.ByDay = sorted(days)
+	ledger.ByMarket = sorted(markets)
+	return ledger, nil
+}
+
+func (e *LedgerEntry) add(o *Outcome) {
+	if o.Status == StatusReverted {
+		e.Reverted++
+	} else {
+		e.Liquidations++
+	}
+	e.RepayUSD += o.RepayUSD
+	e.SeizedUSD += o.SeizedUSD
+	e.GasUSD += o.GasUSD
+	e.ProfitUSD += o.ProfitUSD
+}
+
+func entry(entries map[string]*LedgerEntry, key string) *LedgerEntry {
+	if e, ok := entries[key]; ok {
+		return e
+	}
+	e := &LedgerEntry{Key: key}
+	entries[key] = e
+	return e
+}
+
+func sorted(entries map[string]*LedgerEntry) []LedgerEntry {
+	result := make([]LedgerEntry, 0, len(entries))
+	for _, e := range entries {
+		result = append(result, *e)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Key < result[j].Key
+	})
+	return result
+}
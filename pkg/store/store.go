@@ -0,0 +1,380 @@
+package store
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Outcome statuses
+const (
+	// StatusSucceeded is a mined liquidation
+	StatusSucceeded = "succeeded"
+	// StatusReverted is a mined liquidation that reverted
+	StatusReverted = "reverted"
+	// StatusDropped is a liquidation the node lost whose nonce was used by another transaction
+	StatusDropped = "dropped"
+	// StatusNotSent is a liquidation the node rejected
+	StatusNotSent = "not_sent"
+)
+
+var (
+	evaluationsBucket = []byte("evaluations")
+	attemptsBucket    = []byte("attempts")
+	outcomesBucket    = []byte("outcomes")
+	// pendingBucket maps borrower to the hash of its attempt without outcome
+	pendingBucket = []byte("pending")
+)
+
+// ErrLocked is returned when another process holds the store
+var ErrLocked = errors.New("store is locked by another process")
+
+// Store represents persistent bot state: evaluated borrowers, liquidation attempts and their outcomes
+type Store struct {
+	db *bolt.DB
+}
+
+// Evaluation represents borrower health seen at a block
+type Evaluation struct {
+	Borrower common.Address `json:"borrower"`
+	Block    uint64         `json:"block"`
+	Time     time.Time      `json:"time"`
+	// Health is the subgraph health, collateral over borrows
+	Health float64 `json:"health"`
+	// Shortfall is the comptroller shortfall in USD mantissa
+	Shortfall *big.Int `json:"shortfall"`
+	// Opportunity is set when a profitable liquidation was found
+	Opportunity       bool    `json:"opportunity"`
+	ExpectedProfitUSD float64 `json:"expected_profit_usd,omitempty"`
+}
+
+// Attempt represents signed liquidation transaction, stored before it is sent
+type Attempt struct {
+	TxHash            common.Hash    `json:"tx_hash"`
+	Borrower          common.Address `json:"borrower"`
+	RepayMarket       string         `json:"repay_market"`
+	RepayCToken       common.Address `json:"repay_ctoken"`
+	CollateralMarket  string         `json:"collateral_market"`
+	CollateralCToken  common.Address `json:"collateral_ctoken"`
+	RepayAmount       *big.Int       `json:"repay_amount"`
+	SeizeTokens       *big.Int       `json:"seize_tokens"`
+	Nonce             uint64         `json:"nonce"`
+	GasPrice          *big.Int       `json:"gas_price"`
+	GasLimit          uint64         `json:"gas_limit"`
+	ExpectedProfitUSD float64        `json:"expected_profit_usd"`
+	Block             uint64         `json:"block"`
+	Time              time.Time      `json:"time"`
+	RawTx             hexutil.Bytes  `json:"raw_tx"`
+}
+
+// Outcome represents what happened to an attempt, amounts are realized from the LiquidateBorrow event
+type Outcome struct {
+	TxHash      common.Hash `json:"tx_hash"`
+	Status      string      `json:"status"`
+	Block       uint64      `json:"block,omitempty"`
+	BlockTime   time.Time   `json:"block_time,omitempty"`
+	GasUsed     uint64      `json:"gas_used,omitempty"`
+	GasPrice    *big.Int    `json:"gas_price,omitempty"`
+	RepayAmount *big.Int    `json:"repay_amount,omitempty"`
+	SeizeTokens *big.Int    `json:"seize_tokens,omitempty"`
+	// CollateralCToken is the market tokens were seized in, from the event
+	CollateralCToken common.Address `json:"collateral_ctoken,omitempty"`
+	// SeizedUnderlying is SeizeTokens converted with the collateral exchange rate
+	SeizedUnderlying *big.Int `json:"seized_underlying,omitempty"`
+	// PnL in USD at the oracle prices of the mined block, gas is not priced without an ETH market
+	RepayUSD  float64   `json:"repay_usd"`
+	SeizedUSD float64   `json:"seized_usd"`
+	GasUSD    float64   `json:"gas_usd"`
+	ProfitUSD float64   `json:"profit_usd"`
+	Error     string    `json:"error,omitempty"`
+	Time      time.Time `json:"time"`
+}
+
+// Open opens or creates store at path
+func Open(path string) (*Store, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err == bolt.ErrTimeout {
+		return nil, fmt.Errorf("Opening store %s: %w", path, ErrLocked)
+	}
+	if err != nil {
+		return nil, errors.New("Opening store: " + err.Error())
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, bucket := range [][]byte{evaluationsBucket, attemptsBucket, outcomesBucket, pendingBucket} {
+			if _, err := tx.CreateBucketIfNotExists(bucket); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, errors.New("Creating store buckets: " + err.Error())
+	}
+
+	return &Store{db: db}, nil
+}
+
+// OpenReadOnly opens existing store at path for reading, it fails while a running bot holds the store
+func OpenReadOnly(path string) (*Store, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second, ReadOnly: true})
+	if err == bolt.ErrTimeout {
+		return nil, fmt.Errorf("Opening store %s: %w, stop the bot first", path, ErrLocked)
+	}
+	if err != nil {
+		return nil, errors.New("Opening store: " + err.Error())
+	}
+	return &Store{db: db}, nil
+}
+
+// Close closes the store
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// PutEvaluations records the evaluations of a scan in one transaction. Only changes are kept: an evaluation is
+// skipped when the latest one of its borrower has the same health tier, shortfall and opportunity.
+// Evaluations before block pruneBefore are deleted, nothing is pruned when it is 0.
+func (s *Store) PutEvaluations(evaluations []*Evaluation, pruneBefore uint64) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(evaluationsBucket)
+
+		if pruneBefore > 0 {
+			var old [][]byte
+			err := bucket.ForEach(func(k, v []byte) error {
+				if binary.BigEndian.Uint64(k[common.AddressLength:]) < pruneBefore {
+					old = append(old, append([]byte{}, k...))
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			for _, k := range old {
+				if err := bucket.Delete(k); err != nil {
+					return err
+				}
+			}
+		}
+
+		for _, e := range evaluations {
+			latest, err := latestEvaluation(bucket, e.Borrower)
+			if err != nil {
+				return err
+			}
+			if latest != nil && !changed(latest, e) {
+				continue
+			}
+
+			data, err := json.Marshal(e)
+			if err != nil {
+				return err
+			}
+			if err := bucket.Put(evaluationKey(e.Borrower, e.Block), data); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Evaluations returns evaluations of borrower ordered by block
+func (s *Store) Evaluations(borrower common.Address) ([]*Evaluation, error) {
+	var evaluations []*Evaluation
+	err := s.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(evaluationsBucket).Cursor()
+		prefix := borrower.Bytes()
+		for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+			e := new(Evaluation)
+			if err := json.Unmarshal(v, e); err != nil {
+				return err
+			}
+			evaluations = append(evaluations, e)
+		}
+		return nil
+	})
+	return evaluations, err
+}
+
+// PutAttempt records attempt as pending for its borrower
+func (s *Store) PutAttempt(a *Attempt) error {
+	data, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		if err := tx.Bucket(attemptsBucket).Put(a.TxHash.Bytes(), data); err != nil {
+			return err
+		}
+		return tx.Bucket(pendingBucket).Put(a.Borrower.Bytes(), a.TxHash.Bytes())
+	})
+}
+
+// Attempt returns attempt by transaction hash, nil when not found
+func (s *Store) Attempt(txHash common.Hash) (*Attempt, error) {
+	a := new(Attempt)
+	found, err := s.get(attemptsBucket, txHash.Bytes(), a)
+	if !found {
+		return nil, err
+	}
+	return a, err
+}
+
+// Attempts returns all attempts
+func (s *Store) Attempts() ([]*Attempt, error) {
+	var attempts []*Attempt
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(attemptsBucket).ForEach(func(k, v []byte) error {
+			a := new(Attempt)
+			if err := json.Unmarshal(v, a); err != nil {
+				return err
+			}
+			attempts = append(attempts, a)
+			return nil
+		})
+	})
+	return attempts, err
+}
+
+// PendingAttempt returns attempt liquidating borrower that has no outcome yet, nil when there is none
+func (s *Store) PendingAttempt(borrower common.Address) (*Attempt, error) {
+	var txHash []byte
+	err := s.db.View(func(tx *bolt.Tx) error {
+		if v := tx.Bucket(pendingBucket).Get(borrower.Bytes()); v != nil {
+			txHash = append([]byte{}, v...)
+		}
+		return nil
+	})
+	if err != nil || txHash == nil {
+		return nil, err
+	}
+	return s.Attempt(common.BytesToHash(txHash))
+}
+
+// PendingAttempts returns attempts that have no outcome yet
+func (s *Store) PendingAttempts() ([]*Attempt, error) {
+	var hashes []common.Hash
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(pendingBucket).ForEach(func(k, v []byte) error {
+			hashes = append(hashes, common.BytesToHash(v))
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var attempts []*Attempt
+	for _, hash := range hashes {
+		a, err := s.Attempt(hash)
+		if err != nil {
+			return nil, err
+		}
+		if a != nil {
+			attempts = append(attempts, a)
+		}
+	}
+	return attempts, nil
+}
+
+// PutOutcome records outcome of an attempt, the borrower attempt is no longer pending
+func (s *Store) PutOutcome(o *Outcome) error {
+	data, err := json.Marshal(o)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		if err := tx.Bucket(outcomesBucket).Put(o.TxHash.Bytes(), data); err != nil {
+			return err
+		}
+
+		v := tx.Bucket(attemptsBucket).Get(o.TxHash.Bytes())
+		if v == nil {
+			return nil
+		}
+		a := new(Attempt)
+		if err := json.Unmarshal(v, a); err != nil {
+			return err
+		}
+		pending := tx.Bucket(pendingBucket)
+		if hash := pending.Get(a.Borrower.Bytes()); hash != nil && common.BytesToHash(hash) == o.TxHash {
+			return pending.Delete(a.Borrower.Bytes())
+		}
+		return nil
+	})
+}
+
+// Outcome returns outcome of attempt by transaction hash, nil when not known yet
+func (s *Store) Outcome(txHash common.Hash) (*Outcome, error) {
+	o := new(Outcome)
+	found, err := s.get(outcomesBucket, txHash.Bytes(), o)
+	if !found {
+		return nil, err
+	}
+	return o, err
+}
+
+func (s *Store) get(bucket, key []byte, v interface{}) (bool, error) {
+	var data []byte
+	err := s.db.View(func(tx *bolt.Tx) error {
+		if value := tx.Bucket(bucket).Get(key); value != nil {
+			data = append([]byte{}, value...)
+		}
+		return nil
+	})
+	if err != nil || data == nil {
+		return false, err
+	}
+	return true, json.Unmarshal(data, v)
+}
+
+// healthTierStep is the health change recorded as a new evaluation
+const healthTierStep = 0.05
+
+// changed returns true when e differs from the previous evaluation of the borrower in health tier,
+// shortfall or opportunity
+func changed(previous, e *Evaluation) bool {
+	return int(previous.Health/healthTierStep) != int(e.Health/healthTierStep) ||
+		inShortfall(previous) != inShortfall(e) ||
+		previous.Opportunity != e.Opportunity
+}
+
+func inShortfall(e *Evaluation) bool {
+	return e.Shortfall != nil && e.Shortfall.Sign() > 0
+}
+
+// latestEvaluation returns the evaluation of borrower at the highest block, nil when there is none
+func latestEvaluation(bucket *bolt.Bucket, borrower common.Address) (*Evaluation, error) {
+	c := bucket.Cursor()
+	k, v := c.Seek(evaluationKey(borrower, math.MaxUint64))
+	if k == nil || !bytes.Equal(k, evaluationKey(borrower, math.MaxUint64)) {
+		k, v = c.Prev()
+	}
+	if k == nil || !bytes.HasPrefix(k, borrower.Bytes()) {
+		return nil, nil
+	}
+
+	e := new(Evaluation)
+	if err := json.Unmarshal(v, e); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// evaluationKey orders evaluations by borrower then block
+func evaluationKey(borrower common.Address, block uint64) []byte {
+	key := make([]byte, common.AddressLength+8)
+	copy(key, borrower.Bytes())
+	binary.BigEndian.PutUint64(key[common.AddressLength:], block)
+	return key
+}
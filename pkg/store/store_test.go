@@ -0,0 +1,107 @@
+package store
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func openTemp(t *testing.T) *Store {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	st, err := Open(filepath.Join(dir, "liqbot.db"))
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		st.Close()
+		os.RemoveAll(dir)
+	})
+	return st
+}
+
+func TestPutEvaluations(t *testing.T) {
+	st := openTemp(t)
+	first := common.HexToAddress("0x01")
+	second := common.HexToAddress("0x02")
+
+	scans := []struct {
+		block       uint64
+		health      float64
+		shortfall   int64
+		opportunity bool
+		pruneBefore uint64
+	}{
+		{block: 10, health: 0.97, shortfall: 5},
+		// same tier and shortfall
+		{block: 11, health: 0.96, shortfall: 6},
+		// tier change
+		{block: 12, health: 0.94, shortfall: 8},
+		// opportunity found
+		{block: 13, health: 0.94, shortfall: 8, opportunity: true},
+		// shortfall gone
+		{block: 14, health: 0.94},
+		{block: 15, health: 0.94, pruneBefore: 13},
+	}
+	for _, scan := range scans {
+		var evaluations []*Evaluation
+		for _, borrower := range []common.Address{first, second} {
+			evaluations = append(evaluations, &Evaluation{
+				Borrower:    borrower,
+				Block:       scan.block,
+				Health:      scan.health,
+				Shortfall:   big.NewInt(scan.shortfall),
+				Opportunity: scan.opportunity,
+			})
+		}
+		if err := st.PutEvaluations(evaluations, scan.pruneBefore); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, borrower := range []common.Address{first, second} {
+		evaluations, err := st.Evaluations(borrower)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var blocks []uint64
+		for _, e := range evaluations {
+			blocks = append(blocks, e.Block)
+		}
+		if len(blocks) != 2 || blocks[0] != 13 || blocks[1] != 14 {
+			t.Errorf("%s evaluations at blocks %v, want [13 14]", borrower.Hex(), blocks)
+		}
+	}
+}
+
+func TestPutEvaluationsAfterPrune(t *testing.T) {
+	st := openTemp(t)
+	borrower := common.HexToAddress("0x01")
+
+	put := func(block, pruneBefore uint64) {
+		e := &Evaluation{Borrower: borrower, Block: block, Health: 0.9, Shortfall: big.NewInt(1)}
+		if err := st.PutEvaluations([]*Evaluation{e}, pruneBefore); err != nil {
+			t.Fatal(err)
+		}
+	}
+	put(10, 0)
+	// the only evaluation is pruned, an unchanged one is recorded again
+	put(20, 15)
+
+	evaluations, err := st.Evaluations(borrower)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(evaluations) != 1 || evaluations[0].Block != 20 {
+		t.Errorf("%d evaluations, want the one at block 20", len(evaluations))
+	}
+}
@@ -4,52 +4,167 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 )
 
-// NewBitstampPriceAPI creates new Bitstamp price API
+// ErrStale is returned when the subgraph head block is older than the allowed staleness
+var ErrStale = errors.New("subgraph is stale")
+
+// Option configures subgraph client
+type Option func(*subgraph)
+
+// WithPageSize sets number of accounts fetched per query
+func WithPageSize(pageSize int) Option {
+	return func(s *subgraph) {
+		s.pageSize = pageSize
+	}
+}
+
+// WithMaxStaleness makes queries fail with ErrStale when the indexed head block is older than maxStaleness
+func WithMaxStaleness(maxStaleness time.Duration) Option {
+	return func(s *subgraph) {
+		s.maxStaleness = maxStaleness
+	}
+}
+
+// NewSubgraph creates new Compound subgraph client querying the public endpoint
 func NewSubgraph() *subgraph {
-	return &subgraph{
-		client: &http.Client{},
+	return New(DefaultEndpoint, &http.Client{})
+}
+
+// New creates new subgraph client posting queries to endpoint with client
+func New(endpoint string, client *http.Client, opts ...Option) *subgraph {
+	s := &subgraph{
+		endpoint: endpoint,
+		client:   client,
+		pageSize: defaultPageSize,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.pageSize <= 0 {
+		s.pageSize = defaultPageSize
 	}
+	return s
 }
 
 type subgraph struct {
-	client *http.Client
+	endpoint     string
+	client       *http.Client
+	pageSize     int
+	maxStaleness time.Duration
 }
 
 func (s *subgraph) GetEndpoint() string {
-	return subgraphEndpoint
+	return s.endpoint
 }
 
-func (s *subgraph) GetAccounts(ctx context.Context) ([]subgraphAccount, error) {
+// GetAccounts returns all borrowers, pages are read at the block of the first one
+func (s *subgraph) GetAccounts(ctx context.Context) ([]Account, error) {
+	var (
+		accounts []Account
+		block    *blockHeight
+		lastID   string
+	)
 
-	respData, err := postQuery(ctx, s.client, accountQuery)
-	if err != nil {
+	for {
+		data := new(accountsData)
+		err := s.query(ctx, accountsQuery, map[string]interface{}{
+			"first":  s.pageSize,
+			"lastID": lastID,
+			"block":  block,
+		}, data)
+		if err != nil {
+			return nil, err
+		}
+
+		if block == nil {
+			if err := s.checkMeta(&data.Meta); err != nil {
+				return nil, err
+			}
+			block = &blockHeight{Number: data.Meta.Block.Number}
+		}
+
+		accounts = append(accounts, data.Accounts...)
+		if len(data.Accounts) < s.pageSize {
+			return accounts, nil
+		}
+		lastID = data.Accounts[len(data.Accounts)-1].Id
+	}
+}
+
+// GetMarkets returns all Compound markets
+func (s *subgraph) GetMarkets(ctx context.Context) ([]Market, error) {
+	data := new(marketsData)
+	if err := s.query(ctx, marketsQuery, map[string]interface{}{}, data); err != nil {
+		return nil, err
+	}
+	if err := s.checkMeta(&data.Meta); err != nil {
 		return nil, err
 	}
+	return data.Markets, nil
+}
 
-	response := new(subgraphResponse)
-	err = json.Unmarshal(respData, &response)
-	if err != nil {
+// GetMeta returns the indexing status of the subgraph
+func (s *subgraph) GetMeta(ctx context.Context) (*Meta, error) {
+	data := new(metaData)
+	if err := s.query(ctx, metaQuery, map[string]interface{}{}, data); err != nil {
 		return nil, err
 	}
+	return &data.Meta, nil
+}
 
-	accounts := response.Data.Accounts
-	return accounts, nil
+// checkMeta fails when the head block is too old, subgraphs not reporting block timestamps are not checked
+func (s *subgraph) checkMeta(meta *Meta) error {
+	if s.maxStaleness == 0 || meta.Block.Timestamp == 0 {
+		return nil
+	}
+	if age := time.Since(time.Unix(meta.Block.Timestamp, 0)); age > s.maxStaleness {
+		return fmt.Errorf("%w: block %d is %s old", ErrStale, meta.Block.Number, age.Truncate(time.Second))
+	}
+	return nil
 }
 
-func postQuery(ctx context.Context, client *http.Client, query string) ([]byte, error) {
-	payload := []byte(query)
-	req, err := http.NewRequest("POST", subgraphEndpoint, bytes.NewBuffer(payload))
+// query posts GraphQL query and decodes its data into data
+func (s *subgraph) query(ctx context.Context, query string, variables map[string]interface{}, data interface{}) error {
+	payload, err := json.Marshal(&request{Query: query, Variables: variables})
+	if err != nil {
+		return err
+	}
+
+	respData, err := postQuery(ctx, s.client, s.endpoint, payload)
+	if err != nil {
+		return err
+	}
+
+	response := &response{Data: data}
+	if err := json.Unmarshal(respData, response); err != nil {
+		return err
+	}
+
+	if len(response.Errors) > 0 {
+		messages := make([]string, len(response.Errors))
+		for i, e := range response.Errors {
+			messages[i] = e.Message
+		}
+		return errors.New("subgraph query failed: " + strings.Join(messages, "; "))
+	}
+
+	return nil
+}
+
+func postQuery(ctx context.Context, client *http.Client, endpoint string, payload []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewBuffer(payload))
 	if err != nil {
 		return nil, err
 	}
 	req.Header.Set("Content-Type", "application/json")
-	req.WithContext(ctx)
 
 	resp, err := client.Do(req)
 	if err != nil {
@@ -68,40 +183,121 @@ func postQuery(ctx context.Context, client *http.Client, query string) ([]byte,
 	return respData, nil
 }
 
-type subgraphResponse struct {
-	Data subgraphData `json:"data"`
+type request struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables"`
 }
-type subgraphData struct {
-	Accounts []subgraphAccount `json:"accounts"`
+
+type response struct {
+	Data   interface{} `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
 }
-type subgraphAccount struct {
-	Id                    string `json:"id"`
-	TotalBorrowValueInEth string `json:"totalBorrowValueInEth"`
-	Health                string `json:"health"`
+
+type blockHeight struct {
+	Number uint64 `json:"number"`
 }
 
-func (a *subgraphAccount) IsLiquidable() bool {
+type accountsData struct {
+	Meta     Meta      `json:"_meta"`
+	Accounts []Account `json:"accounts"`
+}
 
-	totalBorrowValueInEth, err := strconv.ParseFloat(a.TotalBorrowValueInEth, 64)
+type marketsData struct {
+	Meta    Meta     `json:"_meta"`
+	Markets []Market `json:"markets"`
+}
 
-	if err != nil {
-		fmt.Println("Error parsing float:", err)
+type metaData struct {
+	Meta Meta `json:"_meta"`
+}
 
-	}
+// Meta represents subgraph indexing status
+type Meta struct {
+	Block struct {
+		Number uint64 `json:"number"`
+		// Timestamp is 0 when the graph node does not report it
+		Timestamp int64 `json:"timestamp"`
+	} `json:"block"`
+	HasIndexingErrors bool `json:"hasIndexingErrors"`
+}
 
-	health, err := strconv.ParseFloat(a.Health, 64)
+// Account represents Compound borrower as indexed by the subgraph
+type Account struct {
+	Id                    string         `json:"id"`
+	TotalBorrowValueInEth string         `json:"totalBorrowValueInEth"`
+	Health                string         `json:"health"`
+	Tokens                []AccountToken `json:"tokens"`
+}
 
-	if err != nil {
-		fmt.Println("Error parsing float:", err)
+// AccountToken represents account position in a market, CTokenBalance is in cTokens, StoredBorrowBalance in underlying
+type AccountToken struct {
+	Id                  string `json:"id"`
+	Symbol              string `json:"symbol"`
+	EnteredMarket       bool   `json:"enteredMarket"`
+	CTokenBalance       string `json:"cTokenBalance"`
+	StoredBorrowBalance string `json:"storedBorrowBalance"`
+}
 
+// Market represents Compound market as indexed by the subgraph
+type Market struct {
+	Id                 string `json:"id"`
+	Symbol             string `json:"symbol"`
+	UnderlyingAddress  string `json:"underlyingAddress"`
+	UnderlyingSymbol   string `json:"underlyingSymbol"`
+	UnderlyingDecimals int    `json:"underlyingDecimals"`
+	UnderlyingPriceUSD string `json:"underlyingPriceUSD"`
+	CollateralFactor   string `json:"collateralFactor"`
+	ExchangeRate       string `json:"exchangeRate"`
+}
+
+// IsLiquidable reports whether account has borrows and a health, error is returned for values that do not parse
+func (a *Account) IsLiquidable() (bool, error) {
+	totalBorrowValueInEth, err := strconv.ParseFloat(a.TotalBorrowValueInEth, 64)
+	if err != nil {
+		return false, fmt.Errorf("parsing totalBorrowValueInEth: %v", err)
 	}
 
-	return totalBorrowValueInEth > 0 && health > 0 //&& health < 1 // to uncomment
+	health, err := strconv.ParseFloat(a.Health, 64)
+	if err != nil {
+		return false, fmt.Errorf("parsing health: %v", err)
+	}
 
+	return totalBorrowValueInEth > 0 && health > 0, nil //&& health < 1 // to uncomment
 }
 
-const subgraphEndpoint = "https://api.thegraph.com/subgraphs/name/graphprotocol/compound-v2"
+// DefaultEndpoint is the public Compound v2 subgraph
+const DefaultEndpoint = "https://api.thegraph.com/subgraphs/name/graphprotocol/compound-v2"
+
+const defaultPageSize = 1000
+
+const metaFields = `_meta(block: $block) { block { number timestamp } hasIndexingErrors }`
+
+const accountsQuery = `query accounts($first: Int!, $lastID: String!, $block: Block_height) {
+  ` + metaFields + `
+  accounts(first: $first, orderBy: id, where: {hasBorrowed: true, id_gt: $lastID}, block: $block) {
+    id
+    totalBorrowValueInEth
+    health
+    tokens { id symbol enteredMarket cTokenBalance storedBorrowBalance }
+  }
+}`
+
+const marketsQuery = `query markets($block: Block_height) {
+  ` + metaFields + `
+  markets(first: 1000, block: $block) {
+    id
+    symbol
+    underlyingAddress
+    underlyingSymbol
+    underlyingDecimals
+    underlyingPriceUSD
+    collateralFactor
+    exchangeRate
+  }
+}`
 
-const accountQuery = `
-{"query":"{\n  accounts(first: 1, orderBy: id, where : {hasBorrowed: true}) {\n\tid \n  totalBorrowValueInEth \n  health \n tokens {id }\n  }\n}","variables":{}}
-`
+const metaQuery = `query meta($block: Block_height) {
+  ` + metaFields + `
+}`
@@ -0,0 +1,124 @@
+package subgraph_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/subgraph"
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/subgraph/subgraphtest"
+)
+
+func fixture(accounts int, indexedAt time.Time) *subgraphtest.Fixture {
+	f := &subgraphtest.Fixture{}
+	f.Meta.Block.Number = 100
+	if !indexedAt.IsZero() {
+		f.Meta.Block.Timestamp = indexedAt.Unix()
+	}
+	for i := 0; i < accounts; i++ {
+		f.Accounts = append(f.Accounts, subgraph.Account{Id: fmt.Sprintf("0x%040x", i+1), TotalBorrowValueInEth: "1", Health: "0.9"})
+	}
+	f.Markets = []subgraph.Market{{Id: "0x5d3a536e4d6dbd6114cc1ead35777bab948e3643", Symbol: "cDAI"}}
+	return f
+}
+
+func TestGetAccountsPagination(t *testing.T) {
+	for _, c := range []struct {
+		accounts, pageSize, queries int
+	}{
+		{0, 2, 1},
+		{1, 2, 1},
+		{5, 2, 3},
+		// a full last page is followed by an empty one
+		{4, 2, 3},
+	} {
+		t.Run(fmt.Sprintf("%d accounts by %d", c.accounts, c.pageSize), func(t *testing.T) {
+			server := subgraphtest.NewServer(subgraphtest.Static(fixture(c.accounts, time.Time{})))
+			defer server.Close()
+
+			accounts, err := subgraph.New(server.URL, server.Client(), subgraph.WithPageSize(c.pageSize)).GetAccounts(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(accounts) != c.accounts {
+				t.Fatalf("got %d accounts, want %d", len(accounts), c.accounts)
+			}
+			for i, account := range accounts {
+				if want := fmt.Sprintf("0x%040x", i+1); account.Id != want {
+					t.Errorf("account %d is %s, want %s", i, account.Id, want)
+				}
+			}
+			if queries := server.Queries(); queries != c.queries {
+				t.Errorf("%d queries sent, want %d", queries, c.queries)
+			}
+		})
+	}
+}
+
+func TestQueryFailures(t *testing.T) {
+	for _, c := range []struct {
+		name    string
+		status  int
+		message string
+		errText string
+	}{
+		{"GraphQL error", http.StatusOK, "indexing_error", "subgraph query failed: indexing_error"},
+		{"HTTP error", http.StatusBadGateway, "", "unexpected http status code: 502"},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			server := subgraphtest.NewServer(subgraphtest.Static(fixture(3, time.Time{})))
+			defer server.Close()
+			client := subgraph.New(server.URL, server.Client(), subgraph.WithPageSize(2))
+
+			server.FailNext(c.status, c.message)
+			if _, err := client.GetAccounts(context.Background()); err == nil || !strings.Contains(err.Error(), c.errText) {
+				t.Errorf("GetAccounts: expected error containing %q, got %v", c.errText, err)
+			}
+
+			server.FailNext(c.status, c.message)
+			if _, err := client.GetMarkets(context.Background()); err == nil || !strings.Contains(err.Error(), c.errText) {
+				t.Errorf("GetMarkets: expected error containing %q, got %v", c.errText, err)
+			}
+
+			// the failure is not sticky
+			if _, err := client.GetAccounts(context.Background()); err != nil {
+				t.Errorf("GetAccounts after failure: %v", err)
+			}
+		})
+	}
+}
+
+func TestStale(t *testing.T) {
+	for _, c := range []struct {
+		name      string
+		indexedAt time.Time
+		stale     bool
+	}{
+		{"fresh", time.Now().Add(-time.Minute), false},
+		{"stale", time.Now().Add(-time.Hour), true},
+		// graph nodes not reporting block timestamps are not checked
+		{"no timestamp", time.Time{}, false},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			server := subgraphtest.NewServer(subgraphtest.Static(fixture(1, c.indexedAt)))
+			defer server.Close()
+			client := subgraph.New(server.URL, server.Client(), subgraph.WithMaxStaleness(10*time.Minute))
+
+			_, err := client.GetAccounts(context.Background())
+			if stale := errors.Is(err, subgraph.ErrStale); stale != c.stale {
+				t.Errorf("GetAccounts: got %v, stale %v expected", err, c.stale)
+			}
+			_, err = client.GetMarkets(context.Background())
+			if stale := errors.Is(err, subgraph.ErrStale); stale != c.stale {
+				t.Errorf("GetMarkets: got %v, stale %v expected", err, c.stale)
+			}
+			if !c.stale && err != nil {
+				t.Errorf("unexpected error %v", err)
+			}
+		})
+	}
+}
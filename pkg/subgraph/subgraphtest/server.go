@@ -0,0 +1,205 @@
+// Package subgraphtest provides an in-process stand-in for the Compound subgraph
+package subgraphtest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+
+	"gitlab.com/q-dev/exchange-rate-oracle/pkg/subgraph"
+)
+
+// Fixture represents subgraph data, Accounts are the borrowers
+type Fixture struct {
+	Meta     subgraph.Meta
+	Accounts []subgraph.Account
+	Markets  []subgraph.Market
+}
+
+// Source provides the fixture served for a query, e.g. derived from simulated chain state
+type Source interface {
+	Fixture(ctx context.Context) (*Fixture, error)
+}
+
+// Static returns source serving fixture for every query
+func Static(fixture *Fixture) Source {
+	return staticSource{fixture: fixture}
+}
+
+type staticSource struct {
+	fixture *Fixture
+}
+
+func (s staticSource) Fixture(ctx context.Context) (*Fixture, error) {
+	return s.fixture, nil
+}
+
+// Server is httptest server answering the `accounts`, `markets` and `_meta` queries of the subgraph client.
+// It does not parse GraphQL: requested fields are detected in the query text,
+// `first`, `lastID` and `block` are read from the variables and full objects are returned.
+type Server struct {
+	*httptest.Server
+
+	source Source
+
+	mu       sync.Mutex
+	failures []failure
+	queries  int
+}
+
+type failure struct {
+	status  int
+	message string
+}
+
+// NewServer creates and starts new fake subgraph server, Close it when done
+func NewServer(source Source) *Server {
+	s := &Server{source: source}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// FailNext makes the next query fail with HTTP status when it is not 200, with a GraphQL error message otherwise
+func (s *Server) FailNext(status int, message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, failure{status: status, message: message})
+}
+
+// Queries returns number of queries served
+func (s *Server) Queries() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.queries
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "POST only", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	req := new(request)
+	if err := json.Unmarshal(body, req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	s.queries++
+	var fail *failure
+	if len(s.failures) > 0 {
+		fail, s.failures = &s.failures[0], s.failures[1:]
+	}
+	s.mu.Unlock()
+
+	if fail != nil && fail.status != http.StatusOK {
+		http.Error(w, http.StatusText(fail.status), fail.status)
+		return
+	}
+	if fail != nil {
+		writeErrors(w, fail.message)
+		return
+	}
+
+	fixture, err := s.source.Fixture(r.Context())
+	if err != nil {
+		writeErrors(w, err.Error())
+		return
+	}
+
+	data, err := resolve(req, fixture)
+	if err != nil {
+		writeErrors(w, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+}
+
+// resolve answers the fields requested by req from fixture
+func resolve(req *request, fixture *Fixture) (map[string]interface{}, error) {
+	data := map[string]interface{}{}
+
+	if req.Variables.Block != nil && req.Variables.Block.Number > fixture.Meta.Block.Number {
+		return nil, fmt.Errorf("subgraph has only indexed up to block number %d and data for block number %d is therefore not yet available",
+			fixture.Meta.Block.Number, req.Variables.Block.Number)
+	}
+
+	resolved := false
+	if strings.Contains(req.Query, "_meta") {
+		data["_meta"] = fixture.Meta
+		resolved = true
+	}
+
+	if strings.Contains(req.Query, "accounts(") {
+		data["accounts"] = page(fixture.Accounts, req.Variables.LastID, req.Variables.First)
+		resolved = true
+	}
+
+	if strings.Contains(req.Query, "markets(") {
+		markets := fixture.Markets
+		if markets == nil {
+			markets = []subgraph.Market{}
+		}
+		data["markets"] = markets
+		resolved = true
+	}
+
+	if !resolved {
+		return nil, fmt.Errorf("unsupported query: %s", req.Query)
+	}
+	return data, nil
+}
+
+// page returns up to first accounts with id greater than lastID, ordered by id
+func page(accounts []subgraph.Account, lastID string, first int) []subgraph.Account {
+	sorted := make([]subgraph.Account, len(accounts))
+	copy(sorted, accounts)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Id < sorted[j].Id
+	})
+
+	result := []subgraph.Account{}
+	for _, account := range sorted {
+		if first > 0 && len(result) == first {
+			break
+		}
+		if account.Id > lastID {
+			result = append(result, account)
+		}
+	}
+	return result
+}
+
+func writeErrors(w http.ResponseWriter, messages ...string) {
+	errs := make([]map[string]string, len(messages))
+	for i, message := range messages {
+		errs[i] = map[string]string{"message": message}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"errors": errs})
+}
+
+type request struct {
+	Query     string `json:"query"`
+	Variables struct {
+		First  int    `json:"first"`
+		LastID string `json:"lastID"`
+		Block  *struct {
+			Number uint64 `json:"number"`
+		} `json:"block"`
+	} `json:"variables"`
+}